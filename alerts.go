@@ -7,7 +7,7 @@
 * Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
 * and Anthony Starks.
 *
-* Last Modified: Mon Aug 01 12:58:30 CDT 2016
+* Last Modified: Mon Oct 19 00:18:08 CDT 2026
 *
 * Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
 *
@@ -28,30 +28,34 @@
 
 package main
 
-import (
-  "fmt"
-)
-
 type AlertConditions struct {
   Alerts []Alerts
 }
 
 type Alerts struct {
-  Date        string
-  Expires     string
-  Description string
-  Message     string
+  Type          string
+  Phenomena     string
+  Significance  string
+  Date          string
+  Date_epoch    string
+  Expires       string
+  Expires_epoch string
+  Description   string
+  Message       string
 }
 
+const alertsTemplate = `{{if not .Alerts -}}
+{{T "No active alerts"}}
+{{else -}}
+{{T "Station"}}: {{station}}
+{{range .Alerts}}{{alert (printf "### %s ###" .Description)}}
+
+{{T "Issued at"}} {{.Date}}
+{{T "Expires at"}} {{.Expires}}
+{{.Message}}
+{{end}}{{end}}`
+
 // printAlerts prints the alerts for a given station to standard out
 func PrintAlerts(obs *AlertConditions, stationId string) {
-  if len(obs.Alerts) == 0 {
-    fmt.Println("No active alerts")
-  } else {
-    fmt.Printf("Station: %s\n", stationId)
-    for _, a := range obs.Alerts {
-      fmt.Printf("### %s ###\n\nIssued at %s\nExpires at %s\n%s\n",
-        a.Description, a.Date, a.Expires, a.Message)
-    }
-  }
+  Render("alerts", stationId, conf.Degrees, obs)
 }
@@ -0,0 +1,228 @@
+/*
+* alertwatch.go
+*
+* This file is part of wu.  It contains functions related to
+* the -alerts switch when combined with --watch (alert change
+* detection).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 02:37:39 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "encoding/json"
+  "fmt"
+  "io/ioutil"
+  "os"
+  "regexp"
+  "sort"
+  "strconv"
+  "strings"
+  "time"
+)
+
+// AlertEvent describes a change in the active alerts between two polls
+type AlertEvent struct {
+  Kind    string // "new", "updated" or "expired"
+  Station string
+  Alert   Alerts
+}
+
+// alertState is what we remember about a station's alerts between
+// polls (and between runs), keyed by alertKey
+type alertState struct {
+  Seen map[string]Alerts
+}
+
+// alertKey identifies an alert across polls.  An NWS alert whose
+// text carries its VTEC code (e.g. "/O.CON.KOAX.FL.W.0012...") is
+// known by its office, phenomena, significance and event number, so
+// that a reissue with a new expiry or text is an update.  Without
+// one, it is known by its phenomena and significance (e.g. "WS.W")
+// and its expiry, which tells apart two warnings of the same kind
+// for different areas; failing those, by its description.
+func alertKey(a Alerts) string {
+  if m := vtecPattern.FindStringSubmatch(a.Message); m != nil {
+    return strings.Join(m[1:], ".")
+  }
+  if a.Phenomena != "" {
+    return a.Phenomena + "." + a.Significance + "." + a.Expires_epoch
+  }
+  return a.Description
+}
+
+// vtecPattern matches the office, phenomena, significance and event
+// tracking number of a VTEC code
+var vtecPattern = regexp.MustCompile(`/[OTEX]\.[A-Z]{3}\.([A-Z]{4})\.([A-Z]{2})\.([A-Z])\.([0-9]{4})\.`)
+
+// alertKeys returns the keys of some alerts, numbering any that would
+// otherwise share one ("FL.W.1768824000#2") so that neither is lost
+func alertKeys(alerts []Alerts) []string {
+  keys := make([]string, len(alerts))
+  count := map[string]int{}
+  for i, a := range alerts {
+    key := alertKey(a)
+    count[key]++
+    if count[key] > 1 {
+      key += "#" + strconv.Itoa(count[key])
+    }
+    keys[i] = key
+  }
+  return keys
+}
+
+// expired reports whether an alert's expiry time has passed
+func expired(a Alerts, now time.Time) bool {
+  epoch, err := strconv.ParseInt(a.Expires_epoch, 10, 64)
+  return err == nil && epoch > 0 && time.Unix(epoch, 0).Before(now)
+}
+
+// readAlertState loads the alert state for a station, returning an
+// empty state if none has been saved yet
+func readAlertState(path string) *alertState {
+  state := &alertState{Seen: map[string]Alerts{}}
+  if b, err := ioutil.ReadFile(path); err == nil {
+    if jsonErr := json.Unmarshal(b, state); jsonErr != nil || state.Seen == nil {
+      fmt.Fprintf(os.Stderr, "Ignoring unreadable alert state in %s\n", path)
+      state.Seen = map[string]Alerts{}
+    }
+  }
+  // Re-key what was saved, in case the keys were made differently,
+  // in the order it was saved in so that numbered keys stay the same
+  var saved []string
+  for key := range state.Seen {
+    saved = append(saved, key)
+  }
+  number := func(key string) int {
+    n := 1
+    if i := strings.LastIndex(key, "#"); i >= 0 {
+      n, _ = strconv.Atoi(key[i+1:])
+    }
+    return n
+  }
+  sort.Slice(saved, func(i, j int) bool {
+    if number(saved[i]) != number(saved[j]) {
+      return number(saved[i]) < number(saved[j])
+    }
+    return saved[i] < saved[j]
+  })
+  var alerts []Alerts
+  for _, key := range saved {
+    alerts = append(alerts, state.Seen[key])
+  }
+  state.Seen = map[string]Alerts{}
+  for i, key := range alertKeys(alerts) {
+    state.Seen[key] = alerts[i]
+  }
+  return state
+}
+
+// save writes the alert state atomically so that an interrupted
+// write can't leave us re-announcing everything on restart
+func (state *alertState) save(path string) error {
+  b, err := json.MarshalIndent(state, "", "  ")
+  if err != nil {
+    return err
+  }
+  tmp := path + ".tmp"
+  if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+    return err
+  }
+  return os.Rename(tmp, path)
+}
+
+// DiffAlerts compares the active alerts with the remembered state,
+// updates the state, and returns the alerts that are new, have been
+// updated (new expiry or text), or have expired since the last poll.
+func (state *alertState) DiffAlerts(obs *AlertConditions, station string, now time.Time) []AlertEvent {
+  var events []AlertEvent
+  active := map[string]bool{}
+
+  keys := alertKeys(obs.Alerts)
+  for i, a := range obs.Alerts {
+    if expired(a, now) {
+      continue
+    }
+    key := keys[i]
+    active[key] = true
+    prev, seen := state.Seen[key]
+    switch {
+    case !seen:
+      events = append(events, AlertEvent{"new", station, a})
+    case prev.Expires != a.Expires || prev.Message != a.Message:
+      events = append(events, AlertEvent{"updated", station, a})
+    }
+    state.Seen[key] = a
+  }
+
+  for key, a := range state.Seen {
+    if !active[key] {
+      events = append(events, AlertEvent{"expired", station, a})
+      delete(state.Seen, key)
+    }
+  }
+  return events
+}
+
+// PrintAlertEvent prints a single alert change to standard out
+func PrintAlertEvent(e AlertEvent) {
+  switch e.Kind {
+  case "expired":
+    fmt.Printf("%s\n\n%s %s\n\n", StyleHeading("### "+T("EXPIRED")+": "+e.Alert.Description+" ###"), T("Issued at"), e.Alert.Date)
+  default:
+    label := T("NEW")
+    if e.Kind == "updated" {
+      label = T("UPDATED")
+    }
+    fmt.Printf("%s\n\n%s %s\n%s %s\n%s\n",
+      StyleAlert("### "+label+": "+e.Alert.Description+" ###"),
+      T("Issued at"), e.Alert.Date, T("Expires at"), e.Alert.Expires, e.Alert.Message)
+  }
+}
+
+// WatchAlerts polls the alerts for a station every interval and
+// reports only the alerts that are new, updated or expired, both on
+// standard out and to any configured webhooks.  What
+// has already been reported is kept in a state file so that
+// restarting the watch doesn't announce the same alerts again.
+func WatchAlerts(station string, interval time.Duration) {
+  path := StateFile("alerts", station)
+  state := readAlertState(path)
+
+  fmt.Printf(T("Watching alerts for %s every %s")+"\n", station, interval)
+  for {
+    var obs AlertConditions
+    if err := Get("alerts", station, &obs); err != nil {
+      fmt.Fprintf(os.Stderr, "%s: %v\n", time.Now().Format(time.Kitchen), err)
+    } else {
+      for _, e := range state.DiffAlerts(&obs, station, time.Now()) {
+        PrintAlertEvent(e)
+        Notify(AlertNotification(e))
+      }
+      if err := state.save(path); err != nil {
+        fmt.Fprintf(os.Stderr, "Could not save alert state: %v\n", err)
+      }
+    }
+    time.Sleep(interval)
+  }
+}
@@ -0,0 +1,99 @@
+/*
+* alertwatch_test.go
+*
+* This file is part of wu.  It contains tests for watching the
+* alerts.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 02:37:39 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "path/filepath"
+  "testing"
+  "time"
+)
+
+// kinds returns the kinds and descriptions of some alert events
+func kinds(events []AlertEvent) map[string]string {
+  got := map[string]string{}
+  for _, e := range events {
+    got[e.Alert.Message] = e.Kind
+  }
+  return got
+}
+
+func TestDiffAlertsSameKind(t *testing.T) {
+  now := time.Unix(1768780000, 0)
+  north := Alerts{Phenomena: "FL", Significance: "W", Description: "Flood Warning",
+    Expires_epoch: "1768824000", Message: "...for the Platte River near Ashland..."}
+  south := Alerts{Phenomena: "FL", Significance: "W", Description: "Flood Warning",
+    Expires_epoch: "1768824000", Message: "...for Salt Creek at Roca..."}
+  heat := Alerts{Phenomena: "HT", Significance: "Y", Description: "Heat Advisory",
+    Expires_epoch: "1768830000", Message: "...heat index values up to 105..."}
+
+  state := &alertState{Seen: map[string]Alerts{}}
+  events := state.DiffAlerts(&AlertConditions{Alerts: []Alerts{north, south, heat}}, "KLNK", now)
+  if got := kinds(events); len(got) != 3 || got[north.Message] != "new" || got[south.Message] != "new" || got[heat.Message] != "new" {
+    t.Errorf("first poll: %v; want all three new", got)
+  }
+  if events := state.DiffAlerts(&AlertConditions{Alerts: []Alerts{north, south, heat}}, "KLNK", now); len(events) != 0 {
+    t.Errorf("second poll: %v; want nothing", kinds(events))
+  }
+
+  // the state survives being saved and read back
+  path := filepath.Join(t.TempDir(), "alerts")
+  if err := state.save(path); err != nil {
+    t.Fatal(err)
+  }
+  state = readAlertState(path)
+  if len(state.Seen) != 3 {
+    t.Errorf("read back %d alerts; want 3", len(state.Seen))
+  }
+  events = state.DiffAlerts(&AlertConditions{Alerts: []Alerts{north, south, heat}}, "KLNK", now)
+  if len(events) != 0 {
+    t.Errorf("after reading back: %v; want nothing", kinds(events))
+  }
+
+  // one of the two warnings ends
+  events = state.DiffAlerts(&AlertConditions{Alerts: []Alerts{north, heat}}, "KLNK", now)
+  if got := kinds(events); len(got) != 1 || got[south.Message] != "expired" {
+    t.Errorf("one warning ended: %v; want it expired", got)
+  }
+}
+
+func TestDiffAlertsVTEC(t *testing.T) {
+  now := time.Unix(1768780000, 0)
+  warning := Alerts{Phenomena: "WS", Significance: "W", Description: "Winter Storm Warning",
+    Expires_epoch: "1768824000", Message: "/O.NEW.KOAX.WS.W.0003.260119T0000Z-260119T1200Z/\n...Heavy snow..."}
+  extended := warning
+  extended.Expires_epoch = "1768845600"
+  extended.Message = "/O.EXT.KOAX.WS.W.0003.260119T0000Z-260119T1800Z/\n...Heavy snow until noon..."
+
+  state := &alertState{Seen: map[string]Alerts{}}
+  state.DiffAlerts(&AlertConditions{Alerts: []Alerts{warning}}, "KOAX", now)
+  events := state.DiffAlerts(&AlertConditions{Alerts: []Alerts{extended}}, "KOAX", now)
+  if got := kinds(events); len(got) != 1 || got[extended.Message] != "updated" {
+    t.Errorf("extended warning: %v; want it updated", got)
+  }
+}
@@ -7,7 +7,7 @@
 * Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
 * and Anthony Starks.
 *
-* Last Modified: Mon Aug 01 12:28:46 CDT 2016
+* Last Modified: Mon Oct 19 00:18:08 CDT 2026
 *
 * Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
 *
@@ -28,10 +28,6 @@
 
 package main
 
-import (
-  "fmt"
-)
-
 type AlmanacConditions struct {
   Almanac Almanac
 }
@@ -63,32 +59,20 @@ type Record struct {
   C string
 }
 
+const almanacTemplate = `{{with .Almanac -}}
+{{if celsius -}}
+{{pad 11 (T "Normal high")}}: {{temp (printf "%s° C" .Temp_high.Normal.C)}} ({{.Temp_high.Normal.F}}° F)
+{{pad 11 (T "Record high")}}: {{temp (printf "%s° C" .Temp_high.Record.C)}} ({{.Temp_high.Record.F}}° F) [{{.Temp_high.Recordyear}}]
+{{pad 11 (T "Normal low")}}: {{temp (printf "%s° C" .Temp_low.Normal.C)}} ({{.Temp_low.Normal.F}}° F)
+{{pad 11 (T "Record low")}}: {{temp (printf "%s° C" .Temp_low.Record.C)}} ({{.Temp_low.Record.F}}° F) [{{.Temp_low.Recordyear}}]
+{{else -}}
+{{pad 11 (T "Normal high")}}: {{temp (printf "%s° C" .Temp_high.Normal.C)}} ({{.Temp_high.Normal.F}}° F)
+{{pad 11 (T "Record high")}}: {{temp (printf "%s° C" .Temp_high.Record.C)}} ({{.Temp_high.Record.F}}° F) [{{.Temp_high.Recordyear}}]
+{{pad 11 (T "Normal low")}}: {{temp (printf "%s° C" .Temp_low.Normal.C)}} ({{.Temp_low.Normal.F}}° F)
+{{pad 11 (T "Record low")}}: {{temp (printf "%s° F" .Temp_low.Record.C)}} ({{.Temp_low.Record.F}}° C) [{{.Temp_low.Recordyear}}]
+{{end}}{{end}}`
+
 // printAlmanac prints the Almanac for a given station to standard out
 func PrintAlmanac(obs *AlmanacConditions, stationId string, degrees string) {
-
-  normalHighF := obs.Almanac.Temp_high.Normal.F
-  normalHighC := obs.Almanac.Temp_high.Normal.C
-  normalLowF := obs.Almanac.Temp_low.Normal.F
-  normalLowC := obs.Almanac.Temp_low.Normal.C
-
-  recordHighF := obs.Almanac.Temp_high.Record.F
-  recordHighC := obs.Almanac.Temp_high.Record.C
-  recordHYear := obs.Almanac.Temp_high.Recordyear
-  recordLowF := obs.Almanac.Temp_low.Record.F
-  recordLowC := obs.Almanac.Temp_low.Record.C
-  recordLYear := obs.Almanac.Temp_low.Recordyear
-
-	if (degrees == "C") {
-		fmt.Printf("Normal high: %s\u00B0 C (%s\u00B0 F)\n", normalHighC, normalHighF)
-		fmt.Printf("Record high: %s\u00B0 C (%s\u00B0 F) [%s]\n", recordHighC, recordHighF, recordHYear)
-		fmt.Printf("Normal low : %s\u00B0 C (%s\u00B0 F)\n", normalLowC, normalLowF)
-		fmt.Printf("Record low : %s\u00B0 C (%s\u00B0 F) [%s]\n", recordLowC, recordLowF, recordLYear)
-
-	} else {
-		fmt.Printf("Normal high: %s\u00B0 C (%s\u00B0 F)\n", normalHighC, normalHighF)
-		fmt.Printf("Record high: %s\u00B0 C (%s\u00B0 F) [%s]\n", recordHighC, recordHighF, recordHYear)
-		fmt.Printf("Normal low : %s\u00B0 C (%s\u00B0 F)\n", normalLowC, normalLowF)
-		fmt.Printf("Record low : %s\u00B0 F (%s\u00B0 C) [%s]\n", recordLowC, recordLowF, recordLYear)
-	}
-
+  Render("almanac", stationId, degrees, obs)
 }
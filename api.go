@@ -0,0 +1,169 @@
+/*
+* api.go
+*
+* This file is part of wu.  It contains functions related to
+* the JSON API (wu serve --http).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 01:40:37 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "encoding/json"
+  "net/http"
+  "regexp"
+  "strconv"
+  "strings"
+  "time"
+)
+
+// endpoint describes how one API path maps onto a data stream
+type endpoint struct {
+  operation string
+  decode    func() interface{} // returns a pointer to decode into
+}
+
+var endpoints = map[string]endpoint{
+  "/v1/conditions": {"conditions", func() interface{} { return &Conditions{} }},
+  "/v1/forecast":   {"forecast", func() interface{} { return &ForecastConditions{} }},
+  "/v1/forecast10": {"forecast10day", func() interface{} { return &ForecastConditions{} }},
+  "/v1/hourly":     {"hourly", func() interface{} { return &HourlyConditions{} }},
+  "/v1/alerts":     {"alerts", func() interface{} { return &AlertConditions{} }},
+  "/v1/almanac":    {"almanac", func() interface{} { return &AlmanacConditions{} }},
+  "/v1/astro":      {"astronomy", func() interface{} { return &AstroConditions{} }},
+  "/v1/history":    {"history", func() interface{} { return &HistoryConditions{} }},
+  "/v1/yesterday":  {"yesterday", func() interface{} { return &HistoryConditions{} }},
+  "/v1/tides":      {"tide", func() interface{} { return &TideConditions{} }},
+}
+
+var historyDate = regexp.MustCompile("^[0-9]{8}$")
+
+func (s *server) apiHandler() http.Handler {
+  mux := http.NewServeMux()
+  for path, e := range endpoints {
+    mux.Handle(path, s.serveEndpoint(e))
+  }
+  return mux
+}
+
+// serveEndpoint answers GET /v1/<report>?station=KLNK[&date=YYYYMMDD]
+// with the decoded report re-encoded as JSON.  Only the stations wu
+// serve was started with are answered, so that clients can't spend
+// the API quota on stations of their own.  Responses come from the
+// cache, and carry an ETag and Last-Modified date so that clients
+// can make conditional requests.
+func (s *server) serveEndpoint(e endpoint) http.HandlerFunc {
+  return func(w http.ResponseWriter, r *http.Request) {
+    if r.Method != "GET" && r.Method != "HEAD" {
+      apiError(w, http.StatusMethodNotAllowed, "only GET is supported")
+      return
+    }
+
+    station := r.URL.Query().Get("station")
+    if station == "" {
+      station = s.stations[0]
+    }
+    station, err := NormalizeStation(station)
+    if err != nil {
+      apiError(w, http.StatusBadRequest, err.Error())
+      return
+    }
+    if !s.serves(station) {
+      apiError(w, http.StatusForbidden, "station "+station+" is not served here")
+      return
+    }
+
+    date := ""
+    if e.operation == "history" {
+      date = r.URL.Query().Get("date")
+      if !historyDate.MatchString(date) {
+        apiError(w, http.StatusBadRequest, "date must be given as YYYYMMDD")
+        return
+      }
+    }
+
+    entry, err := s.cache.Get(e.operation, date, station)
+    if err != nil {
+      apiError(w, http.StatusBadGateway, err.Error())
+      return
+    }
+
+    modified := entry.Fetched.UTC().Truncate(time.Second)
+    w.Header().Set("ETag", entry.ETag)
+    w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+    maxAge := int((s.refresh - time.Since(entry.Fetched)).Seconds())
+    if maxAge < 0 {
+      maxAge = 0
+    }
+    w.Header().Set("Cache-Control", "max-age="+strconv.Itoa(maxAge))
+    if notModified(r, entry.ETag, modified) {
+      w.WriteHeader(http.StatusNotModified)
+      return
+    }
+
+    obs := e.decode()
+    if err := json.Unmarshal(entry.Body, obs); err != nil {
+      apiError(w, http.StatusBadGateway, err.Error())
+      return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    enc := json.NewEncoder(w)
+    enc.SetIndent("", "  ")
+    enc.Encode(obs)
+  }
+}
+
+// serves reports whether a station is one of the server's own
+func (s *server) serves(station string) bool {
+  for _, st := range s.stations {
+    if st == station {
+      return true
+    }
+  }
+  return false
+}
+
+// notModified checks the request's conditional headers, preferring
+// If-None-Match as RFC 7232 asks.  If-None-Match may list several
+// tags, and uses the weak comparison, so W/"x" matches "x".
+func notModified(r *http.Request, etag string, modified time.Time) bool {
+  if match := r.Header.Get("If-None-Match"); match != "" {
+    for _, tag := range strings.Split(match, ",") {
+      tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+      if tag == "*" || tag == strings.TrimPrefix(etag, "W/") {
+        return true
+      }
+    }
+    return false
+  }
+  if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil {
+    return !modified.After(since)
+  }
+  return false
+}
+
+func apiError(w http.ResponseWriter, status int, message string) {
+  w.Header().Set("Content-Type", "application/json")
+  w.WriteHeader(status)
+  json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,217 @@
+/*
+* archive.go
+*
+* This file is part of wu.  It contains the archive of daily
+* history that wu keeps in $HOME/.wu, and the backfill command
+* that fills it.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 03:07:29 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "context"
+  "encoding/json"
+  "flag"
+  "fmt"
+  "io/ioutil"
+  "os"
+  "os/signal"
+  "sort"
+  "strconv"
+  "syscall"
+  "time"
+)
+
+// Archive is a station's daily history, keyed by date (YYYYMMDD)
+type Archive map[string]Dailysummary
+
+// archiveDate is the layout of the archive's (and the API's) dates
+const archiveDate = "20060102"
+
+// LoadArchive returns the archived history for a station
+func LoadArchive(station string) Archive {
+  archive := Archive{}
+  if b, err := ioutil.ReadFile(StateFile("history", station)); err == nil {
+    json.Unmarshal(b, &archive)
+  }
+  return archive
+}
+
+// Save writes the archive for a station
+func (a Archive) Save(station string) error {
+  b, err := json.Marshal(a)
+  if err != nil {
+    return err
+  }
+  return ioutil.WriteFile(StateFile("history", station), b, 0600)
+}
+
+// Dates returns the archive's dates in order
+func (a Archive) Dates() []string {
+  var dates []string
+  for date := range a {
+    dates = append(dates, date)
+  }
+  sort.Strings(dates)
+  return dates
+}
+
+// Between returns the archived days from one date to another
+// (inclusive), in order, and the dates for which there's nothing
+func (a Archive) Between(from time.Time, to time.Time) (days []Dailysummary, missing []string) {
+  for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+    if summary, ok := a[d.Format(archiveDate)]; ok {
+      days = append(days, summary)
+    } else {
+      missing = append(missing, d.Format(archiveDate))
+    }
+  }
+  return days, missing
+}
+
+// ArchiveHistory adds a day's history, as fetched for --history or
+// --yesterday, to a station's archive.  Today's (or a later day's)
+// summary isn't complete yet, so it isn't kept.
+func ArchiveHistory(station string, obs *HistoryConditions) {
+  key := archiveKey(obs.History.Date)
+  if key == "" || key >= time.Now().Format(archiveDate) || len(obs.History.Dailysummary) == 0 {
+    return
+  }
+  archive := LoadArchive(station)
+  archive[key] = obs.History.Dailysummary[0]
+  if err := archive.Save(station); err != nil {
+    fmt.Fprintln(os.Stderr, "Can't archive history: "+err.Error())
+  }
+}
+
+// archiveKey returns the date (YYYYMMDD) of a day's history
+func archiveKey(d Date) string {
+  year, err1 := strconv.Atoi(d.Year)
+  month, err2 := strconv.Atoi(d.Mon)
+  day, err3 := strconv.Atoi(d.Mday)
+  if err1 != nil || err2 != nil || err3 != nil {
+    return ""
+  }
+  return fmt.Sprintf("%04d%02d%02d", year, month, day)
+}
+
+// parseDay reads a date given as YYYY-MM-DD or YYYYMMDD
+func parseDay(s string) (time.Time, error) {
+  if t, err := time.Parse("2006-01-02", s); err == nil {
+    return t, nil
+  }
+  t, err := time.Parse(archiveDate, s)
+  if err != nil {
+    return t, fmt.Errorf("%q is not a date (YYYY-MM-DD)", s)
+  }
+  return t, nil
+}
+
+// Backfill runs "wu backfill", which fetches the history for each day
+// in a range that isn't already archived
+func Backfill(args []string) {
+  ReadConf()
+  flags := flag.NewFlagSet("backfill", flag.ExitOnError)
+  stationFlag := flags.String("s", "", "Weather station (defaults to the one in .condrc)")
+  fromFlag := flags.String("from", "", "First day to fetch (YYYY-MM-DD)")
+  toFlag := flags.String("to", "", "Last day to fetch (YYYY-MM-DD; defaults to yesterday)")
+  every := flags.Duration("every", 6*time.Second, "How long to wait between requests (the free API allows 10 a minute)")
+  flags.Parse(args)
+
+  if *fromFlag == "" {
+    fmt.Println("Usage: wu backfill --from YYYY-MM-DD [--to YYYY-MM-DD] [-s station] [--every 6s]")
+    os.Exit(2)
+  }
+  from, err := parseDay(*fromFlag)
+  CheckError(err)
+  // today's history isn't complete until it's over
+  y := time.Now().AddDate(0, 0, -1)
+  yesterday := time.Date(y.Year(), y.Month(), y.Day(), 0, 0, 0, 0, time.UTC)
+  to := yesterday
+  if *toFlag != "" {
+    to, err = parseDay(*toFlag)
+    CheckError(err)
+    if to.After(yesterday) {
+      fmt.Fprintf(os.Stderr, "Fetching up to yesterday (%s): later days have no complete history yet\n", yesterday.Format("2006-01-02"))
+      to = yesterday
+    }
+  }
+  station := CommandStation(*stationFlag)
+
+  archive := LoadArchive(station)
+  _, missing := archive.Between(from, to)
+  if len(missing) == 0 {
+    fmt.Println("Nothing to fetch: those days are already archived.")
+    return
+  }
+  fmt.Fprintf(os.Stderr, "Fetching %d days for %s (about %s)\n", len(missing), station,
+    (time.Duration(len(missing)) * *every).Round(time.Minute))
+
+  ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+  defer stop()
+  fetchContext = ctx
+  fetched := 0
+  defer func() {
+    CheckError(archive.Save(station))
+    fmt.Fprintf(os.Stderr, "\nArchived %d days in %s\n", fetched, StateFile("history", station))
+  }()
+
+  for i, day := range missing {
+    if i > 0 {
+      select {
+      case <-ctx.Done():
+        return
+      case <-time.After(*every):
+      }
+    }
+    var obs HistoryConditions
+    // A day without data comes back empty; an error in the response
+    // (a bad key, an unknown station, the quota) would be the same for
+    // every day, so it ends the backfill.
+    b, err := Fetch(buildURL("history", day, station))
+    if err == nil {
+      err = responseError(b)
+    }
+    if err == nil {
+      err = json.Unmarshal(b, &obs)
+    }
+    if ctx.Err() != nil {
+      return
+    }
+    if err != nil {
+      fmt.Fprintf(os.Stderr, "\n%s: %v", day, err)
+      return
+    }
+    if len(obs.History.Dailysummary) == 0 {
+      fmt.Fprintf(os.Stderr, "\n%s: no data\n", day)
+      continue
+    }
+    archive[day] = obs.History.Dailysummary[0]
+    fetched++
+    fmt.Fprintf(os.Stderr, "\r%d/%d %s", i+1, len(missing), day)
+    if fetched%30 == 0 {
+      CheckError(archive.Save(station))
+    }
+  }
+}
@@ -7,7 +7,7 @@
 * Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
 * and Anthony Starks.
 *
-* Last Modified: Wed Dec 18 16:11:00 CST 2013
+* Last Modified: Mon Oct 19 00:17:12 CDT 2026
 *
 * Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
 *
@@ -28,11 +28,6 @@
 
 package main
 
-import (
-  "fmt"
-  "strconv"
-)
-
 type AstroConditions struct {
   Moon_phase Moon_phase
   Sunrise    Sunrise
@@ -56,14 +51,11 @@ type Sunset struct {
   Minute string
 }
 
-// printAstro prints the lunar and solar informtion for a given station to standard out
-func PrintAstro(obs *AstroConditions, stationId string) {
-
-  var age, _ = strconv.Atoi(obs.Moon_phase.AgeOfMoon)
+// MoonPhase returns the traditional description of the lunar
+// phase for the age of the moon in days
+func MoonPhase(age int) string {
   var moonDesc string
 
-  // Calculate traditional description of lunar phase
-
   switch {
   case age < 2:
     moonDesc = "New moon"
@@ -82,10 +74,16 @@ func PrintAstro(obs *AstroConditions, stationId string) {
   case age < 28:
     moonDesc = "Waning crescent"
   }
-  sr := obs.Moon_phase.Sunrise
-  ss := obs.Moon_phase.Sunset
-  percent := obs.Moon_phase.PercentIlluminated
-  fmt.Printf("Moon Phase: %s (%s%% illuminated)\n", moonDesc, percent)
-  fmt.Printf("Sunrise   : %s:%s\n", sr.Hour, sr.Minute)
-  fmt.Printf("Sunset    : %s:%s\n", ss.Hour, ss.Minute)
+  return T(moonDesc)
+}
+
+const astroTemplate = `{{with .Moon_phase -}}
+{{pad 10 (T "Moon Phase")}}: {{moon .AgeOfMoon}} ({{.PercentIlluminated}}% {{T "illuminated"}})
+{{pad 10 (T "Sunrise")}}: {{.Sunrise.Hour}}:{{.Sunrise.Minute}}
+{{pad 10 (T "Sunset")}}: {{.Sunset.Hour}}:{{.Sunset.Minute}}
+{{end}}`
+
+// printAstro prints the lunar and solar informtion for a given station to standard out
+func PrintAstro(obs *AstroConditions, stationId string) {
+  Render("astro", stationId, conf.Degrees, obs)
 }
@@ -0,0 +1,250 @@
+/*
+* cache.go
+*
+* This file is part of wu.  It contains the response cache and
+* rate limiter used by the long-running modes (wu serve), so that
+* many readers don't multiply calls to the API.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 03:05:43 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "crypto/sha1"
+  "encoding/hex"
+  "encoding/json"
+  "fmt"
+  "sort"
+  "sync"
+  "time"
+)
+
+// The Weather Underground developer plan allows 10 calls a minute
+// and 500 a day.
+const (
+  callsPerMinute = 10
+  callsPerDay    = 500
+)
+
+// maxCacheEntries is the most responses the cache keeps
+const maxCacheEntries = 1000
+
+// CacheEntry is a cached API response
+type CacheEntry struct {
+  Body    []byte
+  Fetched time.Time
+  ETag    string
+}
+
+// FetchStats records what the cache has asked of the API, per
+// endpoint
+type FetchStats struct {
+  Requests    int
+  Errors      int
+  LastLatency time.Duration
+  Latency     time.Duration // total, for averaging
+}
+
+// Cache holds API responses for ttl, and spaces out and counts the
+// calls it makes so that we stay inside the API's quota
+type Cache struct {
+  ttl      time.Duration
+  mu       sync.Mutex
+  entries  map[string]*CacheEntry
+  inflight map[string]*sync.WaitGroup
+  stats    map[string]*FetchStats
+  last     time.Time // time reserved for the latest API call
+  day      string    // date the daily count refers to
+  today    int       // API calls made today
+}
+
+func NewCache(ttl time.Duration) *Cache {
+  return &Cache{
+    ttl:      ttl,
+    entries:  map[string]*CacheEntry{},
+    inflight: map[string]*sync.WaitGroup{},
+    stats:    map[string]*FetchStats{},
+  }
+}
+
+// Get returns the response for a data stream, from the cache if it
+// is fresh enough, otherwise from the API.  Concurrent requests for
+// the same URL share a single API call.
+func (c *Cache) Get(operation string, date string, station string) (*CacheEntry, error) {
+  url := buildURL(operation, date, station)
+
+  c.mu.Lock()
+  for {
+    if e, ok := c.entries[url]; ok && time.Since(e.Fetched) < c.ttl {
+      c.mu.Unlock()
+      return e, nil
+    }
+    wg, busy := c.inflight[url]
+    if !busy {
+      break
+    }
+    c.mu.Unlock()
+    wg.Wait()
+    c.mu.Lock()
+    if _, ok := c.entries[url]; !ok {
+      break // the other request failed; try ourselves
+    }
+  }
+  wg := &sync.WaitGroup{}
+  wg.Add(1)
+  c.inflight[url] = wg
+  c.mu.Unlock()
+
+  defer func() {
+    c.mu.Lock()
+    delete(c.inflight, url)
+    c.mu.Unlock()
+    wg.Done()
+  }()
+
+  if err := c.reserve(); err != nil {
+    c.record(operation, 0, err)
+    return nil, err
+  }
+  start := time.Now()
+  b, err := Fetch(url)
+  if err == nil {
+    err = responseError(b)
+  }
+  c.record(operation, time.Since(start), err)
+  if err != nil {
+    return nil, err
+  }
+
+  sum := sha1.Sum(b)
+  e := &CacheEntry{Body: b, Fetched: time.Now(), ETag: `"` + hex.EncodeToString(sum[:8]) + `"`}
+  c.mu.Lock()
+  c.store(url, e)
+  c.mu.Unlock()
+  return e, nil
+}
+
+// store adds an entry to the cache, first dropping those that have
+// expired and then, if it is still full, the oldest.  /v1/history
+// can ask for any day, so without a limit the cache would grow for
+// as long as the server runs.  c.mu must be held.
+func (c *Cache) store(url string, e *CacheEntry) {
+  delete(c.entries, url)
+  for u, old := range c.entries {
+    if time.Since(old.Fetched) >= c.ttl {
+      delete(c.entries, u)
+    }
+  }
+  for len(c.entries) >= maxCacheEntries {
+    var oldest string
+    for u, old := range c.entries {
+      if oldest == "" || old.Fetched.Before(c.entries[oldest].Fetched) {
+        oldest = u
+      }
+    }
+    delete(c.entries, oldest)
+  }
+  c.entries[url] = e
+}
+
+// responseError returns the error in an API response, if any.  The
+// API reports errors (an unknown station, a bad key) in a response
+// that is otherwise empty but comes with an HTTP status of 200.
+func responseError(b []byte) error {
+  var r struct {
+    Response struct {
+      Error *struct {
+        Type        string
+        Description string
+      }
+    }
+  }
+  if json.Unmarshal(b, &r) != nil || r.Response.Error == nil {
+    return nil
+  }
+  if r.Response.Error.Description != "" {
+    return fmt.Errorf("API error: %s", r.Response.Error.Description)
+  }
+  return fmt.Errorf("API error: %s", r.Response.Error.Type)
+}
+
+// reserve waits until another API call is allowed under the
+// per-minute limit, and fails if the daily quota is used up.  Each
+// caller books the next free slot under the lock and then sleeps
+// without it, so cache hits and stats aren't held up meanwhile.
+func (c *Cache) reserve() error {
+  c.mu.Lock()
+  now := time.Now()
+  if today := now.Format("20060102"); today != c.day {
+    c.day, c.today = today, 0
+  }
+  if c.today >= callsPerDay {
+    c.mu.Unlock()
+    return fmt.Errorf("daily quota of %d API calls used up", callsPerDay)
+  }
+  slot := c.last.Add(time.Minute / callsPerMinute)
+  if slot.Before(now) {
+    slot = now
+  }
+  c.last = slot
+  c.today++
+  c.mu.Unlock()
+
+  time.Sleep(slot.Sub(now))
+  return nil
+}
+
+func (c *Cache) record(operation string, latency time.Duration, err error) {
+  c.mu.Lock()
+  defer c.mu.Unlock()
+  s, ok := c.stats[operation]
+  if !ok {
+    s = &FetchStats{}
+    c.stats[operation] = s
+  }
+  s.Requests++
+  if err != nil {
+    s.Errors++
+    return
+  }
+  s.LastLatency = latency
+  s.Latency += latency
+}
+
+// Stats returns a copy of the per-endpoint statistics, sorted by
+// endpoint, along with the number of API calls made today
+func (c *Cache) Stats() (endpoints []string, stats []FetchStats, today int) {
+  c.mu.Lock()
+  defer c.mu.Unlock()
+  for name := range c.stats {
+    endpoints = append(endpoints, name)
+  }
+  sort.Strings(endpoints)
+  for _, name := range endpoints {
+    stats = append(stats, *c.stats[name])
+  }
+  if c.day == time.Now().Format("20060102") {
+    today = c.today
+  }
+  return endpoints, stats, today
+}
@@ -0,0 +1,63 @@
+/*
+* cache_test.go
+*
+* This file is part of wu.  It contains tests for the server's cache
+* of API responses.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 03:05:43 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "testing"
+  "time"
+)
+
+func TestCacheStore(t *testing.T) {
+  c := NewCache(10 * time.Minute)
+  now := time.Now()
+  c.store("stale", &CacheEntry{Fetched: now.Add(-time.Hour)})
+  c.store("fresh", &CacheEntry{Fetched: now.Add(-time.Minute)})
+  c.store("new", &CacheEntry{Fetched: now})
+  if _, ok := c.entries["stale"]; ok || len(c.entries) != 2 {
+    t.Errorf("after an expired entry: %d entries, stale kept %v; want 2, false", len(c.entries), ok)
+  }
+
+  // when full of fresh entries, the oldest goes
+  c = NewCache(time.Hour)
+  for i := 0; i < maxCacheEntries; i++ {
+    c.store(fmt.Sprint(i), &CacheEntry{Fetched: now.Add(time.Duration(i-maxCacheEntries) * time.Second)})
+  }
+  c.store("0", &CacheEntry{Fetched: now}) // a refresh takes no more room
+  if len(c.entries) != maxCacheEntries {
+    t.Errorf("after a refresh: %d entries; want %d", len(c.entries), maxCacheEntries)
+  }
+  c.store("new", &CacheEntry{Fetched: now})
+  if _, ok := c.entries["1"]; ok || len(c.entries) != maxCacheEntries {
+    t.Errorf("when full: %d entries, oldest kept %v; want %d, false", len(c.entries), ok, maxCacheEntries)
+  }
+  if _, ok := c.entries["0"]; !ok {
+    t.Errorf("the refreshed entry was dropped")
+  }
+}
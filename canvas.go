@@ -0,0 +1,189 @@
+/*
+* canvas.go
+*
+* This file is part of wu.  It contains the surface that the
+* charts of "wu chart" are drawn on, and its SVG form.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 02:18:37 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bufio"
+  "fmt"
+  "image/color"
+  "io"
+  "strings"
+)
+
+// Point is a position on a canvas, in pixels from the top left
+type Point struct {
+  X, Y float64
+}
+
+// Canvas is what charts are drawn on.  Colors with an alpha below
+// 255 are drawn translucent; text is placed by its baseline and
+// anchored at its "start", "middle" or "end".
+type Canvas interface {
+  Line(points []Point, c color.RGBA, width float64, dashed bool)
+  Fill(points []Point, c color.RGBA)
+  Text(x float64, y float64, s string, size float64, anchor string, c color.RGBA)
+  Encode(w io.Writer) error
+}
+
+// textWidth works out the width of text in a font size from the
+// widths of Helvetica's characters, which the SVG asks for first (or
+// Arial's, which are the same) and the PNG's font is drawn to
+func textWidth(s string, size float64) float64 {
+  width := 0
+  for _, ch := range s {
+    width += charWidth(ch)
+  }
+  return float64(width) * size / 1000
+}
+
+// charWidth returns the width of a character in thousandths of the
+// font size.  Accented letters are as wide as their plain ones, and
+// characters the PNG's font lacks as what it draws instead.
+func charWidth(ch rune) int {
+  if w, ok := helvetica[ch]; ok {
+    return w
+  }
+  for _, m := range markedLetters {
+    if i := strings.IndexRune(m.letters, ch); i >= 0 {
+      return helvetica[[]rune(m.bases)[len([]rune(m.letters[:i]))]]
+    }
+  }
+  if plain := plainText(string(ch)); plain != string(ch) {
+    width := 0
+    for _, p := range plain {
+      width += charWidth(p)
+    }
+    return width
+  }
+  return helvetica['?']
+}
+
+// helvetica is the widths of the characters in Helvetica, in
+// thousandths of the font size
+var helvetica = func() map[rune]int {
+  widths := map[rune]int{}
+  for _, w := range []struct {
+    chars string
+    width int
+  }{
+    {"'", 191},
+    {"ijl", 222},
+    {"|", 260},
+    {" !,./:;I[\\]ftı·", 278},
+    {"()-`r", 333},
+    {"{}", 334},
+    {"\"", 355},
+    {"*", 389},
+    {"°", 400},
+    {"^", 469},
+    {"Jcksvxyz", 500},
+    {"#$0123456789?_abdeghnopquL–", 556},
+    {"+<=>~×", 584},
+    {"FTZß", 611},
+    {"&ABEKPSVXY", 667},
+    {"CDHNRUw", 722},
+    {"GOQ", 778},
+    {"Mm", 833},
+    {"%", 889},
+    {"W", 944},
+    {"@", 1015},
+  } {
+    for _, ch := range w.chars {
+      widths[ch] = w.width
+    }
+  }
+  return widths
+}()
+
+// svgCanvas builds an SVG document
+type svgCanvas struct {
+  width, height int
+  elements      []string
+}
+
+func NewSVGCanvas(width int, height int) Canvas {
+  return &svgCanvas{width: width, height: height}
+}
+
+func svgColor(c color.RGBA) string {
+  return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func svgOpacity(attr string, c color.RGBA) string {
+  if c.A == 255 {
+    return ""
+  }
+  return fmt.Sprintf(` %s="%.2f"`, attr, float64(c.A)/255)
+}
+
+func svgPoints(points []Point) string {
+  var s []string
+  for _, p := range points {
+    s = append(s, fmt.Sprintf("%.1f,%.1f", p.X, p.Y))
+  }
+  return strings.Join(s, " ")
+}
+
+func (s *svgCanvas) Line(points []Point, c color.RGBA, width float64, dashed bool) {
+  if len(points) < 2 {
+    return
+  }
+  dash := ""
+  if dashed {
+    dash = ` stroke-dasharray="6,4"`
+  }
+  s.elements = append(s.elements, fmt.Sprintf(`<polyline points="%s" fill="none" stroke="%s"%s stroke-width="%g" stroke-linejoin="round" stroke-linecap="round"%s/>`,
+    svgPoints(points), svgColor(c), svgOpacity("stroke-opacity", c), width, dash))
+}
+
+func (s *svgCanvas) Fill(points []Point, c color.RGBA) {
+  if len(points) < 3 {
+    return
+  }
+  s.elements = append(s.elements, fmt.Sprintf(`<polygon points="%s" fill="%s"%s/>`,
+    svgPoints(points), svgColor(c), svgOpacity("fill-opacity", c)))
+}
+
+func (s *svgCanvas) Text(x float64, y float64, text string, size float64, anchor string, c color.RGBA) {
+  s.elements = append(s.elements, fmt.Sprintf(`<text x="%.1f" y="%.1f" font-size="%g" text-anchor="%s" fill="%s">%s</text>`,
+    x, y, size, anchor, svgColor(c), xmlEscape(text)))
+}
+
+func (s *svgCanvas) Encode(w io.Writer) error {
+  b := bufio.NewWriter(w)
+  fmt.Fprintf(b, `<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Helvetica, Arial, sans-serif">
+<rect width="100%%" height="100%%" fill="#ffffff"/>
+`, s.width, s.height, s.width, s.height)
+  for _, e := range s.elements {
+    fmt.Fprintln(b, e)
+  }
+  fmt.Fprintln(b, "</svg>")
+  return b.Flush()
+}
@@ -0,0 +1,329 @@
+/*
+* catalog_de.go
+*
+* This file is part of wu.  It contains the German message catalog.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 03:13:25 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+var catalogDE = map[string]string{
+  "January":   "Januar",
+  "February":  "Februar",
+  "March":     "März",
+  "April":     "April",
+  "May":       "Mai",
+  "June":      "Juni",
+  "July":      "Juli",
+  "August":    "August",
+  "September": "September",
+  "October":   "Oktober",
+  "November":  "November",
+  "December":  "Dezember",
+
+  "New moon":        "Neumond",
+  "Waxing crescent": "Zunehmende Sichel",
+  "First quarter":   "Erstes Viertel",
+  "Waxing gibbous":  "Zunehmender Mond",
+  "Full moon":       "Vollmond",
+  "Waning gibbous":  "Abnehmender Mond",
+  "Last quarter":    "Letztes Viertel",
+  "Waning crescent": "Abnehmende Sichel",
+
+  "dry":                    "trocken",
+  "very comfortable":       "sehr angenehm",
+  "comfortable":            "angenehm",
+  "okay for most":          "für die meisten in Ordnung",
+  "somewhat uncomfortable": "etwas unangenehm",
+  "very humid":             "sehr schwül",
+  "oppressive":             "drückend",
+  "dangerously high":       "gefährlich hoch",
+
+  "Current conditions at %s (%s)": "Aktuelles Wetter in %s (%s)",
+  "Temperature":                   "Temperatur",
+  "Heat Index":                    "Hitzeindex",
+  "Sky Conditions":                "Himmel",
+  "Wind":                          "Wind",
+  "Pressure":                      "Luftdruck",
+  "and rising":                    "und steigend",
+  "and falling":                   "und fallend",
+  "and holding steady":            "und gleichbleibend",
+  "Relative humidity":             "Relative Luftfeuchtigkeit",
+  "Dewpoint":                      "Taupunkt",
+  "Windchill":                     "Windchill",
+  "Visibility":                    "Sichtweite",
+  "miles":                         "Meilen",
+  "Precipitation today":           "Niederschlag heute",
+
+  "No active alerts": "Keine aktiven Warnungen",
+  "Station":          "Station",
+  "Issued at":        "Ausgegeben um",
+  "Expires at":       "Gültig bis",
+
+  "Normal high": "Normales Maximum",
+  "Record high": "Rekordmaximum",
+  "Normal low":  "Normales Minimum",
+  "Record low":  "Rekordminimum",
+
+  "Moon Phase":  "Mondphase",
+  "illuminated": "beleuchtet",
+  "Sunrise":     "Sonnenaufgang",
+  "Sunset":      "Sonnenuntergang",
+
+  "Forecast for":             "Vorhersage für",
+  "No area stations":         "Keine Stationen in der Umgebung",
+  "Tidal data for":           "Gezeiten für",
+  "at":                       "um",
+  "No tidal data available.": "Keine Gezeitendaten verfügbar.",
+
+  "No data available for specified date": "Keine Daten für das angegebene Datum verfügbar",
+  "Weather summary for":                  "Wetterübersicht für",
+  "fog":                                  "Nebel",
+  "rain":                                 "Regen",
+  "snow":                                 "Schnee",
+  "hail":                                 "Hagel",
+  "tornado":                              "Tornado",
+  "Snow":                                 "Schnee",
+  "trace":                                "Spuren",
+  "Snow depth":                           "Schneehöhe",
+  "Month to date":                        "Monat bisher",
+  "Since July 1st":                       "Seit 1. Juli",
+  "Precipitation":                        "Niederschlag",
+  "Mean Temperature":                     "Mitteltemperatur",
+  "Max Temperature":                      "Höchsttemperatur",
+  "Min Temperature":                      "Tiefsttemperatur",
+  "Degree Days":                          "Gradtage",
+  "Heating Degree Days":                  "Heizgradtage",
+  "days normal":                          "Tage normal",
+  "HDG month to date":                    "HGT Monat bisher",
+  "HDG since Sept 1st":                   "HGT seit 1. September",
+  "HDG since July 1st":                   "HGT seit 1. Juli",
+  "Cooling Degree Days":                  "Kühlgradtage",
+  "CDG month to date":                    "KGT Monat bisher",
+  "CDG since Sept 1st":                   "KGT seit 1. September",
+  "CDG since Jan 1st":                    "KGT seit 1. Januar",
+  "Moisture":                             "Feuchte",
+  "Mean Dew Point":                       "Mittlerer Taupunkt",
+  "Max Dew Point":                        "Höchster Taupunkt",
+  "Min Dew Point":                        "Tiefster Taupunkt",
+  "Humidity":                             "Luftfeuchtigkeit",
+  "Max Humidity":                         "Höchste Luftfeuchtigkeit",
+  "Min Humidity":                         "Niedrigste Luftfeuchtigkeit",
+  "Mean Pressure":                        "Mittlerer Luftdruck",
+  "Max Pressure":                         "Höchster Luftdruck",
+  "Min Pressure":                         "Niedrigster Luftdruck",
+  "Mean Wind Speed":                      "Mittlere Windgeschwindigkeit",
+  "Max Wind Speed":                       "Höchste Windgeschwindigkeit",
+  "Min Wind Speed":                       "Niedrigste Windgeschwindigkeit",
+  "Mean Wind Direction":                  "Mittlere Windrichtung",
+  "Mean Visibility":                      "Mittlere Sichtweite",
+  "Max Visibility":                       "Höchste Sichtweite",
+  "Min Visibility":                       "Niedrigste Sichtweite",
+
+  "little or no discomfort": "kaum Beschwerden",
+  "some discomfort":         "leichte Beschwerden",
+  "great discomfort":        "starke Beschwerden",
+  "dangerous":               "gefährlich",
+
+  "Pressure tendency":                        "Luftdrucktendenz",
+  "in 3 hours":                               "in 3 Stunden",
+  "Pressure falling rapidly: storm possible": "Luftdruck fällt rasch: Sturm möglich",
+  "Zambretti forecast":                       "Zambretti-Vorhersage",
+
+  "rising, then falling":                                    "steigend, dann fallend",
+  "rising, then steady or rising more slowly":               "steigend, dann gleichbleibend oder langsamer steigend",
+  "rising steadily":                                         "gleichmäßig steigend",
+  "falling or steady, then rising; or rising more quickly":  "fallend oder gleichbleibend, dann steigend; oder schneller steigend",
+  "steady":                                                  "gleichbleibend",
+  "falling, then rising":                                    "fallend, dann steigend",
+  "falling, then steady or falling more slowly":             "fallend, dann gleichbleibend oder langsamer fallend",
+  "falling steadily":                                        "gleichmäßig fallend",
+  "rising or steady, then falling; or falling more quickly": "steigend oder gleichbleibend, dann fallend; oder schneller fallend",
+
+  "Settled fine":                        "Beständig schön",
+  "Fine weather":                        "Schönes Wetter",
+  "Becoming fine":                       "Wird schön",
+  "Fine, becoming less settled":         "Schön, zunehmend unbeständig",
+  "Fine, possible showers":              "Schön, mögliche Schauer",
+  "Fairly fine, improving":              "Ziemlich schön, Besserung",
+  "Fairly fine, possible showers early": "Ziemlich schön, anfangs mögliche Schauer",
+  "Fairly fine, showery later":          "Ziemlich schön, später Schauer",
+  "Showery early, improving":            "Anfangs Schauer, Besserung",
+  "Changeable, mending":                 "Wechselhaft, Besserung",
+  "Fairly fine, showers likely":         "Ziemlich schön, Schauer wahrscheinlich",
+  "Rather unsettled, clearing later":    "Eher unbeständig, später aufklarend",
+  "Unsettled, probably improving":       "Unbeständig, wahrscheinlich Besserung",
+  "Showery, bright intervals":           "Schauer, sonnige Abschnitte",
+  "Showery, becoming less settled":      "Schauer, zunehmend unbeständig",
+  "Changeable, some rain":               "Wechselhaft, etwas Regen",
+  "Unsettled, short fine intervals":     "Unbeständig, kurze schöne Abschnitte",
+  "Unsettled, rain later":               "Unbeständig, später Regen",
+  "Unsettled, some rain":                "Unbeständig, etwas Regen",
+  "Mostly very unsettled":               "Meist sehr unbeständig",
+  "Occasional rain, worsening":          "Zeitweise Regen, Verschlechterung",
+  "Rain at times, very unsettled":       "Zeitweise Regen, sehr unbeständig",
+  "Rain at frequent intervals":          "Häufig Regen",
+  "Rain, very unsettled":                "Regen, sehr unbeständig",
+  "Stormy, may improve":                 "Stürmisch, vielleicht Besserung",
+  "Stormy, much rain":                   "Stürmisch, viel Regen",
+
+  "Frost risk for %s":                          "Frostgefahr für %s",
+  "Tonight":                                    "Heute Nacht",
+  "chance of frost":                            "Frostwahrscheinlichkeit",
+  "chance of a freeze":                         "Wahrscheinlichkeit von Temperaturen unter 0 °C",
+  "Night":                                      "Nacht",
+  "Frost":                                      "Frost",
+  "Freeze":                                     "Gefrieren",
+  "Chance of frost in the next week":           "Frostwahrscheinlichkeit in der nächsten Woche",
+  "Last spring frost":                          "Letzter Frost im Frühjahr",
+  "First fall frost":                           "Erster Frost im Herbst",
+  "median of %d years; earliest %s, latest %s": "Median aus %d Jahren; frühestens %s, spätestens %s",
+  "none in %d years":                           "keiner in %d Jahren",
+  "No archived history for frost dates: fill it with \"wu backfill --from YYYY-MM-DD\"": "Keine archivierten Daten für Frosttermine: mit \"wu backfill --from JJJJ-MM-TT\" ergänzen",
+
+  "Departures from normal at %s on %s":        "Abweichungen vom Normalwert in %s am %s",
+  "Mean":                                      "Mittel",
+  "normal":                                    "normal",
+  "New record high (the record was %s in %s)": "Neuer Rekord-Höchstwert (bisher %s im Jahr %s)",
+  "Ties the record high (%s in %s)":           "Erreicht den Rekord-Höchstwert (%s im Jahr %s)",
+  "New record low (the record was %s in %s)":  "Neuer Rekord-Tiefstwert (bisher %s im Jahr %s)",
+  "Ties the record low (%s in %s)":            "Erreicht den Rekord-Tiefstwert (%s im Jahr %s)",
+  "%s today: %s (the record was %s in %s)":    "%s heute: %s (bisher %s im Jahr %s)",
+  "Approximate: the normals are those for %s, and records are only compared on the day.": "Näherungsweise: die Normalwerte gelten für den %s, Rekorde werden nur am selben Tag verglichen.",
+
+  "Climate summary for %s %d at %s": "Klimaübersicht für %s %d in %s",
+  "Highest":                         "Höchstwert",
+  "Lowest":                          "Tiefstwert",
+  "Greatest in a day":               "Größte Tagesmenge",
+  "Snowfall":                        "Schneefall",
+  "Greatest depth":                  "Größte Schneehöhe",
+  "Average wind":                    "Mittlerer Wind",
+  "Highest wind":                    "Stärkster Wind",
+  "Heating degree days":             "Heizgradtage",
+  "Cooling degree days":             "Kühlgradtage",
+  "Days with":                       "Tage mit",
+  "Days with precipitation":         "Tage mit Niederschlag",
+  "max >= 90 F":                     "Max >= 90 F",
+  "max <= 32 F":                     "Max <= 32 F",
+  "min <= 32 F":                     "Min <= 32 F",
+  "min <= 0 F":                      "Min <= 0 F",
+  "snow >= 1 in":                    "Schnee >= 1 in",
+  "thunder":                         "Gewitter",
+  "missing":                         "fehlt",
+  "Wrote":                           "Geschrieben:",
+  "Climate summary for %d at %s":    "Klimaübersicht für %d in %s",
+  "Month":                           "Monat",
+  "Wettest day":                     "Nassester Tag",
+  "Longest dry spell":               "Längste Trockenperiode",
+  "%d day":                          "%d Tag",
+  "%d days":                         "%d Tage",
+  "Last freeze":                     "Letzter Frost",
+  "First freeze":                    "Erster Frost",
+  "none":                            "keiner",
+  "Snowfall %s season":              "Schneefall Saison %s",
+  "through":                         "bis",
+
+  "%d day is missing: fill it in with \"wu backfill --from %s --to %s\"": "%d Tag fehlt: mit \"wu backfill --from %s --to %s\" nachladen",
+  "%d days are missing: fill them in with \"wu backfill --from %s --to %s\"": "%d Tage fehlen: mit \"wu backfill --from %s --to %s\" nachladen",
+
+  "Hourly forecast for":           "Stündliche Vorhersage für",
+  "Time":                          "Zeit",
+  "Temp":                          "Temp.",
+  "High and low temperatures":     "Höchst- und Tiefsttemperaturen",
+  "Precipitation at %s, %s to %s": "Niederschlag in %s, %s bis %s",
+
+  "A range of days can only be charted (--chart); \"wu climate\" reports on them.": "Ein Zeitraum kann nur als Diagramm gezeigt werden (--chart); \"wu climate\" fasst ihn zusammen.",
+
+  "Temperature at %s, %s to %s": "Temperatur in %s, %s bis %s",
+  "Pressure at %s, %s to %s":    "Luftdruck in %s, %s bis %s",
+  "Wind at %s, %s to %s":        "Wind in %s, %s bis %s",
+  "Normal range":                "Normalbereich",
+  "Daily range":                 "Tagesspanne",
+  "Calm":                        "Windstille",
+
+  "Since %s:":                       "Seit %s:",
+  "up":                              "gestiegen",
+  "down":                            "gefallen",
+  "unchanged":                       "unverändert",
+  "rising":                          "steigend",
+  "falling":                         "fallend",
+  "NEW":                             "NEU",
+  "UPDATED":                         "AKTUALISIERT",
+  "EXPIRED":                         "ABGELAUFEN",
+  "Watching alerts for %s every %s": "Überwache Warnungen für %s alle %s",
+
+  "%d of %d":                    "%d von %d",
+  "updated":                     "aktualisiert",
+  "Forecast":                    "Vorhersage",
+  "10-Day":                      "10 Tage",
+  "Alerts":                      "Warnungen",
+  "Astronomy":                   "Astronomie",
+  "Tides":                       "Gezeiten",
+  "Couldn't fetch this report:": "Bericht konnte nicht abgerufen werden:",
+  "Couldn't show this report:":  "Bericht konnte nicht angezeigt werden:",
+  "Loading...":                  "Wird geladen...",
+
+  "Mon": "Mo",
+  "Tue": "Di",
+  "Wed": "Mi",
+  "Thu": "Do",
+  "Fri": "Fr",
+  "Sat": "Sa",
+  "Sun": "So",
+
+  "Last updated %s (every %s; Ctrl-C to quit)":               "Zuletzt aktualisiert %s (alle %s; Strg-C beendet)",
+  "Tab/←→ panes  n/p stations  ↑↓ scroll  r refresh  q quit": "Tab/←→ Seiten  n/p Stationen  ↑↓ blättern  r aktualisieren  q beenden",
+
+  "Derived":              "Abgeleitete Werte",
+  "Apparent temperature": "Gefühlte Temperatur",
+  "Wet-bulb temperature": "Feuchtkugeltemperatur",
+  "WBGT (shade)":         "WBGT (Schatten)",
+  "Absolute humidity":    "Absolute Feuchte",
+  "Cloud base":           "Wolkenuntergrenze",
+
+  "Day":        "Tag",
+  "High":       "Max",
+  "Low":        "Min",
+  "Conditions": "Wetter",
+  "POP":        "Wahr.",
+  "Precip":     "Nieder.",
+  "RH":         "rF",
+
+  "Chance of":                           "Wahrscheinlichkeit",
+  "Temps":                               "Temperaturen",
+  "Over 90 F (32 C)":                    "Über 90 F (32 C)",
+  "Between 60 F (15 C) and 90 F (32 C)": "Zwischen 60 F (15 C) und 90 F (32 C)",
+  "Between 32 F (0 C) and 60 F (16 C)":  "Zwischen 32 F (0 C) und 60 F (16 C)",
+  "Below 32 F (0 C)":                    "Unter 32 F (0 C)",
+  "Dewpoint above 70 F (21 C)":          "Taupunkt über 70 F (21 C)",
+  "Dewpoint above 60 F (15 C)":          "Taupunkt über 60 F (15 C)",
+  "Winds over 10 mph (15 km/h)":         "Wind über 10 mph (15 km/h)",
+  "Over 32 C (90 F)":                    "Über 32 C (90 F)",
+  "Between 15 C (60 F) and 32 C (90 F)": "Zwischen 15 C (60 F) und 32 C (90 F)",
+  "Between 0 C (32 F) and 16 C (60 F)":  "Zwischen 0 C (32 F) und 16 C (60 F)",
+  "Below 0 F (32 C)":                    "Unter 0 C (32 F)",
+  "Dewpoint above 21 C (70 F)":          "Taupunkt über 21 C (70 F)",
+  "Dewpoint above 15 C (60 F)":          "Taupunkt über 15 C (60 F)",
+  "Winds over 15 km/h (10 mph)":         "Wind über 15 km/h (10 mph)",
+  "%s day":                              "Tag: %s",
+}
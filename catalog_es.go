@@ -0,0 +1,329 @@
+/*
+* catalog_es.go
+*
+* This file is part of wu.  It contains the Spanish message catalog.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 03:13:25 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+var catalogES = map[string]string{
+  "January":   "Enero",
+  "February":  "Febrero",
+  "March":     "Marzo",
+  "April":     "Abril",
+  "May":       "Mayo",
+  "June":      "Junio",
+  "July":      "Julio",
+  "August":    "Agosto",
+  "September": "Septiembre",
+  "October":   "Octubre",
+  "November":  "Noviembre",
+  "December":  "Diciembre",
+
+  "New moon":        "Luna nueva",
+  "Waxing crescent": "Luna creciente",
+  "First quarter":   "Cuarto creciente",
+  "Waxing gibbous":  "Gibosa creciente",
+  "Full moon":       "Luna llena",
+  "Waning gibbous":  "Gibosa menguante",
+  "Last quarter":    "Cuarto menguante",
+  "Waning crescent": "Luna menguante",
+
+  "dry":                    "seco",
+  "very comfortable":       "muy agradable",
+  "comfortable":            "agradable",
+  "okay for most":          "aceptable para la mayoría",
+  "somewhat uncomfortable": "algo incómodo",
+  "very humid":             "muy húmedo",
+  "oppressive":             "agobiante",
+  "dangerously high":       "peligrosamente alto",
+
+  "Current conditions at %s (%s)": "Condiciones actuales en %s (%s)",
+  "Temperature":                   "Temperatura",
+  "Heat Index":                    "Índice de calor",
+  "Sky Conditions":                "Cielo",
+  "Wind":                          "Viento",
+  "Pressure":                      "Presión",
+  "and rising":                    "y subiendo",
+  "and falling":                   "y bajando",
+  "and holding steady":            "y estable",
+  "Relative humidity":             "Humedad relativa",
+  "Dewpoint":                      "Punto de rocío",
+  "Windchill":                     "Sensación térmica",
+  "Visibility":                    "Visibilidad",
+  "miles":                         "millas",
+  "Precipitation today":           "Precipitación hoy",
+
+  "No active alerts": "No hay alertas activas",
+  "Station":          "Estación",
+  "Issued at":        "Emitida a las",
+  "Expires at":       "Expira a las",
+
+  "Normal high": "Máxima normal",
+  "Record high": "Máxima récord",
+  "Normal low":  "Mínima normal",
+  "Record low":  "Mínima récord",
+
+  "Moon Phase":  "Fase lunar",
+  "illuminated": "iluminada",
+  "Sunrise":     "Salida del sol",
+  "Sunset":      "Puesta del sol",
+
+  "Forecast for":             "Pronóstico para",
+  "No area stations":         "No hay estaciones en la zona",
+  "Tidal data for":           "Mareas para",
+  "at":                       "a las",
+  "No tidal data available.": "No hay datos de mareas.",
+
+  "No data available for specified date": "No hay datos para la fecha indicada",
+  "Weather summary for":                  "Resumen del tiempo del",
+  "fog":                                  "niebla",
+  "rain":                                 "lluvia",
+  "snow":                                 "nieve",
+  "hail":                                 "granizo",
+  "tornado":                              "tornado",
+  "Snow":                                 "Nieve",
+  "trace":                                "inapreciable",
+  "Snow depth":                           "Espesor de nieve",
+  "Month to date":                        "En lo que va de mes",
+  "Since July 1st":                       "Desde el 1 de julio",
+  "Precipitation":                        "Precipitación",
+  "Mean Temperature":                     "Temperatura media",
+  "Max Temperature":                      "Temperatura máxima",
+  "Min Temperature":                      "Temperatura mínima",
+  "Degree Days":                          "Grados-día",
+  "Heating Degree Days":                  "Grados-día de calefacción",
+  "days normal":                          "días normal",
+  "HDG month to date":                    "GDC en lo que va de mes",
+  "HDG since Sept 1st":                   "GDC desde el 1 de septiembre",
+  "HDG since July 1st":                   "GDC desde el 1 de julio",
+  "Cooling Degree Days":                  "Grados-día de refrigeración",
+  "CDG month to date":                    "GDR en lo que va de mes",
+  "CDG since Sept 1st":                   "GDR desde el 1 de septiembre",
+  "CDG since Jan 1st":                    "GDR desde el 1 de enero",
+  "Moisture":                             "Humedad",
+  "Mean Dew Point":                       "Punto de rocío medio",
+  "Max Dew Point":                        "Punto de rocío máximo",
+  "Min Dew Point":                        "Punto de rocío mínimo",
+  "Humidity":                             "Humedad",
+  "Max Humidity":                         "Humedad máxima",
+  "Min Humidity":                         "Humedad mínima",
+  "Mean Pressure":                        "Presión media",
+  "Max Pressure":                         "Presión máxima",
+  "Min Pressure":                         "Presión mínima",
+  "Mean Wind Speed":                      "Velocidad media del viento",
+  "Max Wind Speed":                       "Velocidad máxima del viento",
+  "Min Wind Speed":                       "Velocidad mínima del viento",
+  "Mean Wind Direction":                  "Dirección media del viento",
+  "Mean Visibility":                      "Visibilidad media",
+  "Max Visibility":                       "Visibilidad máxima",
+  "Min Visibility":                       "Visibilidad mínima",
+
+  "little or no discomfort": "poca o ninguna molestia",
+  "some discomfort":         "algo de malestar",
+  "great discomfort":        "gran malestar",
+  "dangerous":               "peligroso",
+
+  "Pressure tendency":                        "Tendencia de la presión",
+  "in 3 hours":                               "en 3 horas",
+  "Pressure falling rapidly: storm possible": "La presión baja rápidamente: posible tormenta",
+  "Zambretti forecast":                       "Pronóstico Zambretti",
+
+  "rising, then falling":                                    "en ascenso, luego en descenso",
+  "rising, then steady or rising more slowly":               "en ascenso, luego estable o subiendo más despacio",
+  "rising steadily":                                         "en ascenso constante",
+  "falling or steady, then rising; or rising more quickly":  "en descenso o estable, luego en ascenso; o subiendo más deprisa",
+  "steady":                                                  "estable",
+  "falling, then rising":                                    "en descenso, luego en ascenso",
+  "falling, then steady or falling more slowly":             "en descenso, luego estable o bajando más despacio",
+  "falling steadily":                                        "en descenso constante",
+  "rising or steady, then falling; or falling more quickly": "en ascenso o estable, luego en descenso; o bajando más deprisa",
+
+  "Settled fine":                        "Buen tiempo estable",
+  "Fine weather":                        "Buen tiempo",
+  "Becoming fine":                       "Mejorando",
+  "Fine, becoming less settled":         "Bueno, volviéndose inestable",
+  "Fine, possible showers":              "Bueno, posibles chubascos",
+  "Fairly fine, improving":              "Bastante bueno, mejorando",
+  "Fairly fine, possible showers early": "Bastante bueno, posibles chubascos al principio",
+  "Fairly fine, showery later":          "Bastante bueno, chubascos más tarde",
+  "Showery early, improving":            "Chubascos al principio, mejorando",
+  "Changeable, mending":                 "Variable, mejorando",
+  "Fairly fine, showers likely":         "Bastante bueno, chubascos probables",
+  "Rather unsettled, clearing later":    "Algo inestable, despejando más tarde",
+  "Unsettled, probably improving":       "Inestable, probablemente mejorando",
+  "Showery, bright intervals":           "Chubascos, intervalos soleados",
+  "Showery, becoming less settled":      "Chubascos, volviéndose inestable",
+  "Changeable, some rain":               "Variable, algo de lluvia",
+  "Unsettled, short fine intervals":     "Inestable, breves intervalos de buen tiempo",
+  "Unsettled, rain later":               "Inestable, lluvia más tarde",
+  "Unsettled, some rain":                "Inestable, algo de lluvia",
+  "Mostly very unsettled":               "Mayormente muy inestable",
+  "Occasional rain, worsening":          "Lluvia ocasional, empeorando",
+  "Rain at times, very unsettled":       "Lluvia a ratos, muy inestable",
+  "Rain at frequent intervals":          "Lluvia frecuente",
+  "Rain, very unsettled":                "Lluvia, muy inestable",
+  "Stormy, may improve":                 "Tormentoso, puede mejorar",
+  "Stormy, much rain":                   "Tormentoso, mucha lluvia",
+
+  "Frost risk for %s":                          "Riesgo de helada en %s",
+  "Tonight":                                    "Esta noche",
+  "chance of frost":                            "probabilidad de escarcha",
+  "chance of a freeze":                         "probabilidad de helada",
+  "Night":                                      "Noche",
+  "Frost":                                      "Escarcha",
+  "Freeze":                                     "Helada",
+  "Chance of frost in the next week":           "Probabilidad de escarcha en la próxima semana",
+  "Last spring frost":                          "Última helada de primavera",
+  "First fall frost":                           "Primera helada de otoño",
+  "median of %d years; earliest %s, latest %s": "mediana de %d años; la más temprana %s, la más tardía %s",
+  "none in %d years":                           "ninguna en %d años",
+  "No archived history for frost dates: fill it with \"wu backfill --from YYYY-MM-DD\"": "No hay historial archivado para las fechas de helada: añádalo con \"wu backfill --from AAAA-MM-DD\"",
+
+  "Departures from normal at %s on %s":        "Desviaciones de lo normal en %s el %s",
+  "Mean":                                      "Media",
+  "normal":                                    "normal",
+  "New record high (the record was %s in %s)": "Nuevo récord de máxima (el récord era %s en %s)",
+  "Ties the record high (%s in %s)":           "Iguala el récord de máxima (%s en %s)",
+  "New record low (the record was %s in %s)":  "Nuevo récord de mínima (el récord era %s en %s)",
+  "Ties the record low (%s in %s)":            "Iguala el récord de mínima (%s en %s)",
+  "%s today: %s (the record was %s in %s)":    "%s hoy: %s (el récord era %s en %s)",
+  "Approximate: the normals are those for %s, and records are only compared on the day.": "Aproximado: los valores normales son los del %s, y los récords solo se comparan en el mismo día.",
+
+  "Climate summary for %s %d at %s": "Resumen climático de %s de %d en %s",
+  "Highest":                         "Máxima",
+  "Lowest":                          "Mínima",
+  "Greatest in a day":               "Máximo en un día",
+  "Snowfall":                        "Nevada",
+  "Greatest depth":                  "Espesor máximo",
+  "Average wind":                    "Viento medio",
+  "Highest wind":                    "Viento máximo",
+  "Heating degree days":             "Grados-día de calefacción",
+  "Cooling degree days":             "Grados-día de refrigeración",
+  "Days with":                       "Días con",
+  "Days with precipitation":         "Días con precipitación",
+  "max >= 90 F":                     "máx >= 90 F",
+  "max <= 32 F":                     "máx <= 32 F",
+  "min <= 32 F":                     "mín <= 32 F",
+  "min <= 0 F":                      "mín <= 0 F",
+  "snow >= 1 in":                    "nieve >= 1 in",
+  "thunder":                         "tormenta",
+  "missing":                         "falta",
+  "Wrote":                           "Escrito:",
+  "Climate summary for %d at %s":    "Resumen climático de %d en %s",
+  "Month":                           "Mes",
+  "Wettest day":                     "Día más lluvioso",
+  "Longest dry spell":               "Racha seca más larga",
+  "%d day":                          "%d día",
+  "%d days":                         "%d días",
+  "Last freeze":                     "Última helada",
+  "First freeze":                    "Primera helada",
+  "none":                            "ninguna",
+  "Snowfall %s season":              "Nevada de la temporada %s",
+  "through":                         "hasta",
+
+  "%d day is missing: fill it in with \"wu backfill --from %s --to %s\"": "falta %d día: complételo con \"wu backfill --from %s --to %s\"",
+  "%d days are missing: fill them in with \"wu backfill --from %s --to %s\"": "faltan %d días: complételos con \"wu backfill --from %s --to %s\"",
+
+  "Hourly forecast for":           "Pronóstico por horas para",
+  "Time":                          "Hora",
+  "Temp":                          "Temp.",
+  "High and low temperatures":     "Temperaturas máximas y mínimas",
+  "Precipitation at %s, %s to %s": "Precipitación en %s, del %s al %s",
+
+  "A range of days can only be charted (--chart); \"wu climate\" reports on them.": "Un intervalo de días solo se puede representar en un gráfico (--chart); \"wu climate\" lo resume.",
+
+  "Temperature at %s, %s to %s": "Temperatura en %s, del %s al %s",
+  "Pressure at %s, %s to %s":    "Presión en %s, del %s al %s",
+  "Wind at %s, %s to %s":        "Viento en %s, del %s al %s",
+  "Normal range":                "Rango normal",
+  "Daily range":                 "Rango diario",
+  "Calm":                        "Calma",
+
+  "Since %s:":                       "Desde %s:",
+  "up":                              "subió",
+  "down":                            "bajó",
+  "unchanged":                       "sin cambios",
+  "rising":                          "subiendo",
+  "falling":                         "bajando",
+  "NEW":                             "NUEVA",
+  "UPDATED":                         "ACTUALIZADA",
+  "EXPIRED":                         "EXPIRADA",
+  "Watching alerts for %s every %s": "Vigilando alertas para %s cada %s",
+
+  "%d of %d":                    "%d de %d",
+  "updated":                     "actualizado",
+  "Forecast":                    "Pronóstico",
+  "10-Day":                      "10 días",
+  "Alerts":                      "Alertas",
+  "Astronomy":                   "Astronomía",
+  "Tides":                       "Mareas",
+  "Couldn't fetch this report:": "No se pudo obtener este informe:",
+  "Couldn't show this report:":  "No se pudo mostrar este informe:",
+  "Loading...":                  "Cargando...",
+
+  "Mon": "lun",
+  "Tue": "mar",
+  "Wed": "mié",
+  "Thu": "jue",
+  "Fri": "vie",
+  "Sat": "sáb",
+  "Sun": "dom",
+
+  "Last updated %s (every %s; Ctrl-C to quit)":               "Última actualización %s (cada %s; Ctrl-C para salir)",
+  "Tab/←→ panes  n/p stations  ↑↓ scroll  r refresh  q quit": "Tab/←→ paneles  n/p estaciones  ↑↓ desplazar  r actualizar  q salir",
+
+  "Derived":              "Valores derivados",
+  "Apparent temperature": "Temperatura aparente",
+  "Wet-bulb temperature": "Temperatura de bulbo húmedo",
+  "WBGT (shade)":         "WBGT (sombra)",
+  "Absolute humidity":    "Humedad absoluta",
+  "Cloud base":           "Base de las nubes",
+
+  "Day":        "Día",
+  "High":       "Máx",
+  "Low":        "Mín",
+  "Conditions": "Condiciones",
+  "POP":        "Prob",
+  "Precip":     "Precip",
+  "RH":         "HR",
+
+  "Chance of":                           "Probabilidad de",
+  "Temps":                               "Temperaturas",
+  "Over 90 F (32 C)":                    "Más de 90 F (32 C)",
+  "Between 60 F (15 C) and 90 F (32 C)": "Entre 60 F (15 C) y 90 F (32 C)",
+  "Between 32 F (0 C) and 60 F (16 C)":  "Entre 32 F (0 C) y 60 F (16 C)",
+  "Below 32 F (0 C)":                    "Menos de 32 F (0 C)",
+  "Dewpoint above 70 F (21 C)":          "Punto de rocío sobre 70 F (21 C)",
+  "Dewpoint above 60 F (15 C)":          "Punto de rocío sobre 60 F (15 C)",
+  "Winds over 10 mph (15 km/h)":         "Viento de más de 10 mph (15 km/h)",
+  "Over 32 C (90 F)":                    "Más de 32 C (90 F)",
+  "Between 15 C (60 F) and 32 C (90 F)": "Entre 15 C (60 F) y 32 C (90 F)",
+  "Between 0 C (32 F) and 16 C (60 F)":  "Entre 0 C (32 F) y 16 C (60 F)",
+  "Below 0 F (32 C)":                    "Menos de 0 C (32 F)",
+  "Dewpoint above 21 C (70 F)":          "Punto de rocío sobre 21 C (70 F)",
+  "Dewpoint above 15 C (60 F)":          "Punto de rocío sobre 15 C (60 F)",
+  "Winds over 15 km/h (10 mph)":         "Viento de más de 15 km/h (10 mph)",
+  "%s day":                              "Día %s",
+}
@@ -0,0 +1,329 @@
+/*
+* catalog_fr.go
+*
+* This file is part of wu.  It contains the French message catalog.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 03:13:25 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+var catalogFR = map[string]string{
+  "January":   "Janvier",
+  "February":  "Février",
+  "March":     "Mars",
+  "April":     "Avril",
+  "May":       "Mai",
+  "June":      "Juin",
+  "July":      "Juillet",
+  "August":    "Août",
+  "September": "Septembre",
+  "October":   "Octobre",
+  "November":  "Novembre",
+  "December":  "Décembre",
+
+  "New moon":        "Nouvelle lune",
+  "Waxing crescent": "Premier croissant",
+  "First quarter":   "Premier quartier",
+  "Waxing gibbous":  "Gibbeuse croissante",
+  "Full moon":       "Pleine lune",
+  "Waning gibbous":  "Gibbeuse décroissante",
+  "Last quarter":    "Dernier quartier",
+  "Waning crescent": "Dernier croissant",
+
+  "dry":                    "sec",
+  "very comfortable":       "très agréable",
+  "comfortable":            "agréable",
+  "okay for most":          "correct pour la plupart",
+  "somewhat uncomfortable": "un peu inconfortable",
+  "very humid":             "très humide",
+  "oppressive":             "étouffant",
+  "dangerously high":       "dangereusement élevé",
+
+  "Current conditions at %s (%s)": "Conditions actuelles à %s (%s)",
+  "Temperature":                   "Température",
+  "Heat Index":                    "Indice de chaleur",
+  "Sky Conditions":                "Ciel",
+  "Wind":                          "Vent",
+  "Pressure":                      "Pression",
+  "and rising":                    "et en hausse",
+  "and falling":                   "et en baisse",
+  "and holding steady":            "et stable",
+  "Relative humidity":             "Humidité relative",
+  "Dewpoint":                      "Point de rosée",
+  "Windchill":                     "Refroidissement éolien",
+  "Visibility":                    "Visibilité",
+  "miles":                         "milles",
+  "Precipitation today":           "Précipitations aujourd'hui",
+
+  "No active alerts": "Aucune alerte en cours",
+  "Station":          "Station",
+  "Issued at":        "Émise à",
+  "Expires at":       "Expire à",
+
+  "Normal high": "Maximale normale",
+  "Record high": "Maximale record",
+  "Normal low":  "Minimale normale",
+  "Record low":  "Minimale record",
+
+  "Moon Phase":  "Phase lunaire",
+  "illuminated": "éclairée",
+  "Sunrise":     "Lever du soleil",
+  "Sunset":      "Coucher du soleil",
+
+  "Forecast for":             "Prévisions pour",
+  "No area stations":         "Aucune station dans la région",
+  "Tidal data for":           "Marées pour",
+  "at":                       "à",
+  "No tidal data available.": "Aucune donnée de marée disponible.",
+
+  "No data available for specified date": "Aucune donnée disponible pour cette date",
+  "Weather summary for":                  "Résumé météo du",
+  "fog":                                  "brouillard",
+  "rain":                                 "pluie",
+  "snow":                                 "neige",
+  "hail":                                 "grêle",
+  "tornado":                              "tornade",
+  "Snow":                                 "Neige",
+  "trace":                                "traces",
+  "Snow depth":                           "Hauteur de neige",
+  "Month to date":                        "Depuis le début du mois",
+  "Since July 1st":                       "Depuis le 1er juillet",
+  "Precipitation":                        "Précipitations",
+  "Mean Temperature":                     "Température moyenne",
+  "Max Temperature":                      "Température maximale",
+  "Min Temperature":                      "Température minimale",
+  "Degree Days":                          "Degrés-jours",
+  "Heating Degree Days":                  "Degrés-jours de chauffage",
+  "days normal":                          "jours normal",
+  "HDG month to date":                    "DJC depuis le début du mois",
+  "HDG since Sept 1st":                   "DJC depuis le 1er septembre",
+  "HDG since July 1st":                   "DJC depuis le 1er juillet",
+  "Cooling Degree Days":                  "Degrés-jours de climatisation",
+  "CDG month to date":                    "DJR depuis le début du mois",
+  "CDG since Sept 1st":                   "DJR depuis le 1er septembre",
+  "CDG since Jan 1st":                    "DJR depuis le 1er janvier",
+  "Moisture":                             "Humidité",
+  "Mean Dew Point":                       "Point de rosée moyen",
+  "Max Dew Point":                        "Point de rosée maximal",
+  "Min Dew Point":                        "Point de rosée minimal",
+  "Humidity":                             "Humidité",
+  "Max Humidity":                         "Humidité maximale",
+  "Min Humidity":                         "Humidité minimale",
+  "Mean Pressure":                        "Pression moyenne",
+  "Max Pressure":                         "Pression maximale",
+  "Min Pressure":                         "Pression minimale",
+  "Mean Wind Speed":                      "Vitesse moyenne du vent",
+  "Max Wind Speed":                       "Vitesse maximale du vent",
+  "Min Wind Speed":                       "Vitesse minimale du vent",
+  "Mean Wind Direction":                  "Direction moyenne du vent",
+  "Mean Visibility":                      "Visibilité moyenne",
+  "Max Visibility":                       "Visibilité maximale",
+  "Min Visibility":                       "Visibilité minimale",
+
+  "little or no discomfort": "peu ou pas d'inconfort",
+  "some discomfort":         "un certain inconfort",
+  "great discomfort":        "beaucoup d'inconfort",
+  "dangerous":               "dangereux",
+
+  "Pressure tendency":                        "Tendance de la pression",
+  "in 3 hours":                               "en 3 heures",
+  "Pressure falling rapidly: storm possible": "Pression en forte baisse : tempête possible",
+  "Zambretti forecast":                       "Prévision Zambretti",
+
+  "rising, then falling":                                    "en hausse, puis en baisse",
+  "rising, then steady or rising more slowly":               "en hausse, puis stable ou en hausse plus lente",
+  "rising steadily":                                         "en hausse régulière",
+  "falling or steady, then rising; or rising more quickly":  "en baisse ou stable, puis en hausse ; ou en hausse plus rapide",
+  "steady":                                                  "stable",
+  "falling, then rising":                                    "en baisse, puis en hausse",
+  "falling, then steady or falling more slowly":             "en baisse, puis stable ou en baisse plus lente",
+  "falling steadily":                                        "en baisse régulière",
+  "rising or steady, then falling; or falling more quickly": "en hausse ou stable, puis en baisse ; ou en baisse plus rapide",
+
+  "Settled fine":                        "Beau temps durable",
+  "Fine weather":                        "Beau temps",
+  "Becoming fine":                       "Devenant beau",
+  "Fine, becoming less settled":         "Beau, devenant moins stable",
+  "Fine, possible showers":              "Beau, averses possibles",
+  "Fairly fine, improving":              "Assez beau, s'améliorant",
+  "Fairly fine, possible showers early": "Assez beau, averses possibles au début",
+  "Fairly fine, showery later":          "Assez beau, averses plus tard",
+  "Showery early, improving":            "Averses au début, s'améliorant",
+  "Changeable, mending":                 "Variable, s'améliorant",
+  "Fairly fine, showers likely":         "Assez beau, averses probables",
+  "Rather unsettled, clearing later":    "Plutôt instable, éclaircies plus tard",
+  "Unsettled, probably improving":       "Instable, probablement en amélioration",
+  "Showery, bright intervals":           "Averses, belles éclaircies",
+  "Showery, becoming less settled":      "Averses, devenant moins stable",
+  "Changeable, some rain":               "Variable, un peu de pluie",
+  "Unsettled, short fine intervals":     "Instable, courtes périodes de beau temps",
+  "Unsettled, rain later":               "Instable, pluie plus tard",
+  "Unsettled, some rain":                "Instable, un peu de pluie",
+  "Mostly very unsettled":               "Généralement très instable",
+  "Occasional rain, worsening":          "Pluie par moments, se dégradant",
+  "Rain at times, very unsettled":       "Pluie par moments, très instable",
+  "Rain at frequent intervals":          "Pluie fréquente",
+  "Rain, very unsettled":                "Pluie, très instable",
+  "Stormy, may improve":                 "Orageux, amélioration possible",
+  "Stormy, much rain":                   "Orageux, beaucoup de pluie",
+
+  "Frost risk for %s":                          "Risque de gel à %s",
+  "Tonight":                                    "Cette nuit",
+  "chance of frost":                            "risque de gelée",
+  "chance of a freeze":                         "risque de gel",
+  "Night":                                      "Nuit",
+  "Frost":                                      "Gelée",
+  "Freeze":                                     "Gel",
+  "Chance of frost in the next week":           "Risque de gelée dans la semaine",
+  "Last spring frost":                          "Dernière gelée de printemps",
+  "First fall frost":                           "Première gelée d'automne",
+  "median of %d years; earliest %s, latest %s": "médiane sur %d ans ; au plus tôt %s, au plus tard %s",
+  "none in %d years":                           "aucune en %d ans",
+  "No archived history for frost dates: fill it with \"wu backfill --from YYYY-MM-DD\"": "Pas d'historique archivé pour les dates de gelée : complétez-le avec \"wu backfill --from AAAA-MM-JJ\"",
+
+  "Departures from normal at %s on %s":        "Écarts à la normale à %s le %s",
+  "Mean":                                      "Moyenne",
+  "normal":                                    "normale",
+  "New record high (the record was %s in %s)": "Nouveau record de chaleur (le record était de %s en %s)",
+  "Ties the record high (%s in %s)":           "Égale le record de chaleur (%s en %s)",
+  "New record low (the record was %s in %s)":  "Nouveau record de froid (le record était de %s en %s)",
+  "Ties the record low (%s in %s)":            "Égale le record de froid (%s en %s)",
+  "%s today: %s (the record was %s in %s)":    "%s aujourd'hui : %s (le record était de %s en %s)",
+  "Approximate: the normals are those for %s, and records are only compared on the day.": "Approximatif : les normales sont celles du %s, et les records ne sont comparés que le jour même.",
+
+  "Climate summary for %s %d at %s": "Résumé climatologique de %s %d à %s",
+  "Highest":                         "Maximum",
+  "Lowest":                          "Minimum",
+  "Greatest in a day":               "Maximum en un jour",
+  "Snowfall":                        "Chute de neige",
+  "Greatest depth":                  "Épaisseur maximale",
+  "Average wind":                    "Vent moyen",
+  "Highest wind":                    "Vent maximal",
+  "Heating degree days":             "Degrés-jours de chauffage",
+  "Cooling degree days":             "Degrés-jours de climatisation",
+  "Days with":                       "Jours avec",
+  "Days with precipitation":         "Jours de précipitations",
+  "max >= 90 F":                     "max >= 90 F",
+  "max <= 32 F":                     "max <= 32 F",
+  "min <= 32 F":                     "min <= 32 F",
+  "min <= 0 F":                      "min <= 0 F",
+  "snow >= 1 in":                    "neige >= 1 in",
+  "thunder":                         "orage",
+  "missing":                         "manquant",
+  "Wrote":                           "Écrit :",
+  "Climate summary for %d at %s":    "Résumé climatologique de %d à %s",
+  "Month":                           "Mois",
+  "Wettest day":                     "Jour le plus pluvieux",
+  "Longest dry spell":               "Plus longue période sèche",
+  "%d day":                          "%d jour",
+  "%d days":                         "%d jours",
+  "Last freeze":                     "Dernier gel",
+  "First freeze":                    "Premier gel",
+  "none":                            "aucun",
+  "Snowfall %s season":              "Chute de neige saison %s",
+  "through":                         "jusqu'au",
+
+  "%d day is missing: fill it in with \"wu backfill --from %s --to %s\"": "%d jour manque : complétez-le avec \"wu backfill --from %s --to %s\"",
+  "%d days are missing: fill them in with \"wu backfill --from %s --to %s\"": "%d jours manquent : complétez-les avec \"wu backfill --from %s --to %s\"",
+
+  "Hourly forecast for":           "Prévisions horaires pour",
+  "Time":                          "Heure",
+  "Temp":                          "Temp.",
+  "High and low temperatures":     "Températures maximales et minimales",
+  "Precipitation at %s, %s to %s": "Précipitations à %s, du %s au %s",
+
+  "A range of days can only be charted (--chart); \"wu climate\" reports on them.": "Une période ne peut être que représentée en graphique (--chart) ; \"wu climate\" la résume.",
+
+  "Temperature at %s, %s to %s": "Température à %s, du %s au %s",
+  "Pressure at %s, %s to %s":    "Pression à %s, du %s au %s",
+  "Wind at %s, %s to %s":        "Vent à %s, du %s au %s",
+  "Normal range":                "Plage normale",
+  "Daily range":                 "Amplitude journalière",
+  "Calm":                        "Calme",
+
+  "Since %s:":                       "Depuis %s :",
+  "up":                              "en hausse",
+  "down":                            "en baisse",
+  "unchanged":                       "inchangé",
+  "rising":                          "en hausse",
+  "falling":                         "en baisse",
+  "NEW":                             "NOUVELLE",
+  "UPDATED":                         "MISE À JOUR",
+  "EXPIRED":                         "EXPIRÉE",
+  "Watching alerts for %s every %s": "Surveillance des alertes pour %s toutes les %s",
+
+  "%d of %d":                    "%d sur %d",
+  "updated":                     "mis à jour",
+  "Forecast":                    "Prévisions",
+  "10-Day":                      "10 jours",
+  "Alerts":                      "Alertes",
+  "Astronomy":                   "Astronomie",
+  "Tides":                       "Marées",
+  "Couldn't fetch this report:": "Impossible d'obtenir ce bulletin :",
+  "Couldn't show this report:":  "Impossible d'afficher ce bulletin :",
+  "Loading...":                  "Chargement...",
+
+  "Mon": "lun",
+  "Tue": "mar",
+  "Wed": "mer",
+  "Thu": "jeu",
+  "Fri": "ven",
+  "Sat": "sam",
+  "Sun": "dim",
+
+  "Last updated %s (every %s; Ctrl-C to quit)":               "Dernière mise à jour %s (toutes les %s ; Ctrl-C pour quitter)",
+  "Tab/←→ panes  n/p stations  ↑↓ scroll  r refresh  q quit": "Tab/←→ volets  n/p stations  ↑↓ défiler  r actualiser  q quitter",
+
+  "Derived":              "Valeurs dérivées",
+  "Apparent temperature": "Température apparente",
+  "Wet-bulb temperature": "Température humide",
+  "WBGT (shade)":         "WBGT (ombre)",
+  "Absolute humidity":    "Humidité absolue",
+  "Cloud base":           "Base des nuages",
+
+  "Day":        "Jour",
+  "High":       "Max",
+  "Low":        "Min",
+  "Conditions": "Conditions",
+  "POP":        "Prob",
+  "Precip":     "Précip",
+  "RH":         "HR",
+
+  "Chance of":                           "Probabilité de",
+  "Temps":                               "Températures",
+  "Over 90 F (32 C)":                    "Plus de 90 F (32 C)",
+  "Between 60 F (15 C) and 90 F (32 C)": "Entre 60 F (15 C) et 90 F (32 C)",
+  "Between 32 F (0 C) and 60 F (16 C)":  "Entre 32 F (0 C) et 60 F (16 C)",
+  "Below 32 F (0 C)":                    "Moins de 32 F (0 C)",
+  "Dewpoint above 70 F (21 C)":          "Point de rosée au-dessus de 70 F (21 C)",
+  "Dewpoint above 60 F (15 C)":          "Point de rosée au-dessus de 60 F (15 C)",
+  "Winds over 10 mph (15 km/h)":         "Vent de plus de 10 mph (15 km/h)",
+  "Over 32 C (90 F)":                    "Plus de 32 C (90 F)",
+  "Between 15 C (60 F) and 32 C (90 F)": "Entre 15 C (60 F) et 32 C (90 F)",
+  "Between 0 C (32 F) and 16 C (60 F)":  "Entre 0 C (32 F) et 16 C (60 F)",
+  "Below 0 F (32 C)":                    "Moins de 0 C (32 F)",
+  "Dewpoint above 21 C (70 F)":          "Point de rosée au-dessus de 21 C (70 F)",
+  "Dewpoint above 15 C (60 F)":          "Point de rosée au-dessus de 15 C (60 F)",
+  "Winds over 15 km/h (10 mph)":         "Vent de plus de 15 km/h (10 mph)",
+  "%s day":                              "Journée %s",
+}
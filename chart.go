@@ -0,0 +1,322 @@
+/*
+* chart.go
+*
+* This file is part of wu.  It contains the functions that draw
+* charts in the terminal (the --chart switch).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 00:56:13 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "math"
+  "strings"
+)
+
+// eighths are the partial blocks for the tops of bars
+var eighths = []rune(" ▁▂▃▄▅▆▇█")
+
+// charting reports whether to add charts (--chart) to the reports,
+// which are only drawn with text output
+func charting() bool {
+  return chart && (outputFormat == "" || outputFormat == "text")
+}
+
+// tempFormat is the format for the temperature axis of charts
+func tempFormat() string {
+  if conf.Degrees == "C" {
+    return "%.0f°C"
+  }
+  return "%.0f°F"
+}
+
+// chartSize returns the height and width of a chart, from the size
+// of the terminal
+func chartSize() (height int, width int) {
+  rows, cols := TermSize()
+  height = rows / 3
+  if height < 6 {
+    height = 6
+  } else if height > 16 {
+    height = 16
+  }
+  return height, cols - 1
+}
+
+// valueRange returns the lowest and highest of the values given,
+// skipping missing ones, and widened if they are all the same
+func valueRange(values ...[]float64) (float64, float64) {
+  lo, hi := math.Inf(1), math.Inf(-1)
+  for _, vs := range values {
+    for _, v := range vs {
+      if !math.IsNaN(v) {
+        lo, hi = math.Min(lo, v), math.Max(hi, v)
+      }
+    }
+  }
+  if math.IsInf(lo, 1) {
+    return 0, 1
+  }
+  if lo == hi {
+    return lo - 1, hi + 1
+  }
+  return lo, hi
+}
+
+// yAxis returns the labels for the rows of a chart from the top down,
+// labelling every third row (and the top one)
+func yAxis(lo float64, hi float64, height int, format string) ([]string, int) {
+  labels := make([]string, height)
+  width := 0
+  for row := range labels {
+    if row == 0 || (height-1-row)%3 == 0 {
+      labels[row] = fmt.Sprintf(format, hi-(hi-lo)*float64(row)/float64(height-1))
+    }
+    if n := len([]rune(labels[row])); n > width {
+      width = n
+    }
+  }
+  return labels, width
+}
+
+// xAxis returns the line of labels under a chart, with each label
+// placed at its column if there's room for it
+func xAxis(labels []string, columns []int, indent int) string {
+  var line []rune
+  for i, label := range labels {
+    if label == "" {
+      continue
+    }
+    at := indent + columns[i]
+    if len(line) > at {
+      continue // overlaps the previous label
+    }
+    for len(line) < at {
+      line = append(line, ' ')
+    }
+    line = append(line, []rune(label+" ")...)
+  }
+  return strings.TrimRight(string(line), " ")
+}
+
+// frame puts a chart's grid of rows (top down) beside its y axis,
+// with a line along the bottom and the x axis labels
+func frame(grid [][]rune, lo float64, hi float64, format string, labels []string, columns []int) []string {
+  ylabels, ywidth := yAxis(lo, hi, len(grid), format)
+  var lines []string
+  for row, cells := range grid {
+    tick := "┤"
+    if ylabels[row] == "" {
+      tick = "│"
+    }
+    lines = append(lines, fmt.Sprintf("%*s %s%s", ywidth, ylabels[row], tick, strings.TrimRight(string(cells), " ")))
+  }
+  width := 0
+  if len(grid) > 0 {
+    width = len(grid[0])
+  }
+  lines = append(lines, strings.Repeat(" ", ywidth+1)+"└"+strings.Repeat("─", width))
+  lines = append(lines, xAxis(labels, columns, ywidth+2))
+  return lines
+}
+
+func newGrid(height int, width int) [][]rune {
+  grid := make([][]rune, height)
+  for row := range grid {
+    grid[row] = []rune(strings.Repeat(" ", width))
+  }
+  return grid
+}
+
+// LineChart draws values (NaN for gaps) as a line, stretched or
+// squeezed to fit the width.  Labels go under the values they name,
+// and format is used for the y axis (e.g. "%.0f°").
+func LineChart(values []float64, labels []string, height int, width int, format string) []string {
+  lo, hi := valueRange(values)
+  _, ywidth := yAxis(lo, hi, height, format)
+  plot := width - ywidth - 2
+  if plot < 2 || len(values) == 0 {
+    return nil
+  }
+
+  // Interpolate a value for each column
+  n := len(values)
+  points := make([]float64, plot)
+  for c := range points {
+    x := 0.0
+    if plot > 1 && n > 1 {
+      x = float64(c) * float64(n-1) / float64(plot-1)
+    }
+    i := int(x)
+    if i >= n-1 {
+      points[c] = values[n-1]
+      continue
+    }
+    f := x - float64(i)
+    points[c] = values[i] + (values[i+1]-values[i])*f // NaN if either is
+  }
+  columns := make([]int, n)
+  for i := range columns {
+    if n > 1 {
+      columns[i] = int(math.Round(float64(i) * float64(plot-1) / float64(n-1)))
+    }
+  }
+
+  // Rows are counted up from the bottom here, as values are
+  row := func(v float64) int {
+    return int(math.Round((v - lo) / (hi - lo) * float64(height-1)))
+  }
+  grid := newGrid(height, plot)
+  set := func(r int, c int, ch rune) { grid[height-1-r][c] = ch }
+  for c := 0; c < plot; c++ {
+    if math.IsNaN(points[c]) {
+      continue
+    }
+    y0 := row(points[c])
+    if c == plot-1 || math.IsNaN(points[c+1]) {
+      set(y0, c, '─')
+      continue
+    }
+    y1 := row(points[c+1])
+    switch {
+    case y0 == y1:
+      set(y0, c, '─')
+    case y1 > y0:
+      set(y0, c, '╯')
+      set(y1, c, '╭')
+    default:
+      set(y0, c, '╮')
+      set(y1, c, '╰')
+    }
+    for y := int(math.Min(float64(y0), float64(y1))) + 1; y < int(math.Max(float64(y0), float64(y1))); y++ {
+      set(y, c, '│')
+    }
+  }
+  return frame(grid, lo, hi, format, labels, columns)
+}
+
+// BarChart draws values as bars up from zero.  When there are more
+// values than columns, neighbouring values are added together (and
+// take the first one's label).
+func BarChart(values []float64, labels []string, height int, width int, format string) []string {
+  _, hi := valueRange(values, []float64{0})
+  _, ywidth := yAxis(0, hi, height, format)
+  plot := width - ywidth - 2
+  if plot < 1 || len(values) == 0 {
+    return nil
+  }
+
+  for len(values) > plot {
+    per := (len(values) + plot - 1) / plot
+    var summed []float64
+    var named []string
+    for i := 0; i < len(values); i += per {
+      total := 0.0
+      for j := i; j < i+per && j < len(values); j++ {
+        if !math.IsNaN(values[j]) {
+          total += values[j]
+        }
+      }
+      summed, named = append(summed, total), append(named, labels[i])
+    }
+    values, labels = summed, named
+    _, hi = valueRange(values, []float64{0})
+    _, ywidth = yAxis(0, hi, height, format)
+    plot = width - ywidth - 2
+  }
+
+  bar := plot / len(values)
+  gap := 0
+  if bar > 2 {
+    gap = 1
+  }
+  grid := newGrid(height, bar*len(values))
+  columns := make([]int, len(values))
+  for i, v := range values {
+    columns[i] = i * bar
+    if math.IsNaN(v) || v <= 0 {
+      continue
+    }
+    // The bar's height in eighths of a row
+    h := int(math.Round(v / hi * float64(height*8)))
+    for r := 0; r < height && h > 0; r++ {
+      ch := eighths[8]
+      if h < 8 {
+        ch = eighths[h]
+      }
+      for c := columns[i]; c < columns[i]+bar-gap; c++ {
+        grid[height-1-r][c] = ch
+      }
+      h -= 8
+    }
+  }
+  return frame(grid, 0, hi, format, labels, columns)
+}
+
+// RangeChart draws a horizontal band from each low to each high, one
+// row per label, on a shared scale
+func RangeChart(lows []float64, highs []float64, labels []string, width int, format string) []string {
+  lo, hi := valueRange(lows, highs)
+  lwidth, vwidth := 0, 0
+  for i := range labels {
+    lwidth = int(math.Max(float64(lwidth), float64(len([]rune(labels[i])))))
+    for _, v := range []float64{lows[i], highs[i]} {
+      vwidth = int(math.Max(float64(vwidth), float64(len(fmt.Sprintf(format, v)))))
+    }
+  }
+  plot := width - lwidth - 2*vwidth - 4
+  if plot < 2 {
+    return nil
+  }
+
+  column := func(v float64) int {
+    return int(math.Round((v - lo) / (hi - lo) * float64(plot-1)))
+  }
+  var lines []string
+  for i, label := range labels {
+    track := []rune(strings.Repeat(" ", plot))
+    low, high := fmt.Sprintf(format, lows[i]), fmt.Sprintf(format, highs[i])
+    if math.IsNaN(lows[i]) || math.IsNaN(highs[i]) {
+      low, high = "", ""
+    } else {
+      for c := column(lows[i]); c <= column(highs[i]); c++ {
+        track[c] = '█'
+      }
+    }
+    lines = append(lines, fmt.Sprintf("%-*s %*s %s %s", lwidth, label, vwidth, low, string(track), high))
+  }
+  return lines
+}
+
+// printChart writes a chart under a heading
+func printChart(title string, lines []string) {
+  if len(lines) == 0 {
+    return
+  }
+  fmt.Println()
+  fmt.Println(StyleHeading(title))
+  for _, line := range lines {
+    fmt.Println(line)
+  }
+}
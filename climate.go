@@ -0,0 +1,658 @@
+/*
+* climate.go
+*
+* This file is part of wu.  It contains functions related to
+* "wu climate", which summarizes a month of archived history in
+* the manner of the NWS monthly climate report (F-6).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 03:13:25 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "flag"
+  "fmt"
+  "math"
+  "os"
+  "strings"
+  "time"
+)
+
+// ClimateDay is a day's line of the climate report.  Weather holds
+// the F-6 codes: 1 fog, 3 thunder, 5 hail, X tornado.  Unlike the
+// F-6, it gives the day's average wind direction, not the peak's.
+type ClimateDay struct {
+  Date         string
+  Day          int
+  Missing      bool
+  Max_f        Number
+  Max_c        Number
+  Min_f        Number
+  Min_c        Number
+  Avg_f        Number
+  Avg_c        Number
+  Departure_f  Number
+  Departure_c  Number
+  Hdd          Number
+  Hdd_normal   Number
+  Cdd          Number
+  Cdd_normal   Number
+  Precip_in    Number
+  Precip_mm    Number
+  Precip_trace bool
+  Snow_in      Number
+  Snow_mm      Number
+  Snow_trace   bool
+  Depth_in     Number
+  Depth_mm     Number
+  Wind_mph     Number
+  Wind_kph     Number
+  Max_wind_mph Number
+  Max_wind_kph Number
+  Avg_wind_dir Number
+  Weather      string
+}
+
+// ClimateSummary is the totals, averages and extremes of a number of
+// days (the Period, a month or year).  Dates are YYYY-MM-DD.
+type ClimateSummary struct {
+  Period          string
+  Days            int
+  Missing         int
+  Max_f           Number
+  Max_c           Number
+  Max_date        string
+  Min_f           Number
+  Min_c           Number
+  Min_date        string
+  Avg_max_f       Number
+  Avg_max_c       Number
+  Avg_min_f       Number
+  Avg_min_c       Number
+  Avg_f           Number
+  Avg_c           Number
+  Departure_f     Number
+  Departure_c     Number
+  Hdd             Number
+  Hdd_normal      Number
+  Cdd             Number
+  Cdd_normal      Number
+  Precip_in       Number
+  Precip_mm       Number
+  Max_precip_in   Number
+  Max_precip_mm   Number
+  Max_precip_date string
+  Snow_in         Number
+  Snow_mm         Number
+  Max_depth_in    Number
+  Max_depth_mm    Number
+  Max_depth_date  string
+  Wind_mph        Number
+  Wind_kph        Number
+  Max_wind_mph    Number
+  Max_wind_kph    Number
+  Max_wind_date   string
+  Days_max_90     int
+  Days_max_32     int
+  Days_min_32     int
+  Days_min_0      int
+  Days_precip_01  int
+  Days_precip_10  int
+  Days_precip_100 int
+  Days_snow_1     int
+  Days_fog        int
+  Days_thunder    int
+  Days_hail       int
+}
+
+// ClimateMonth is the climate report for a month
+type ClimateMonth struct {
+  Year    int
+  Month   int
+  From    string
+  To      string
+  Days    []ClimateDay
+  Summary ClimateSummary
+}
+
+// NewClimateDay makes a day's line from its history.  The normal
+// mean temperature, which the history doesn't give, follows from the
+// normal degree days (taken from 65°F).
+func NewClimateDay(date time.Time, d Dailysummary) ClimateDay {
+  day := ClimateDay{
+    Date:         date.Format("2006-01-02"),
+    Day:          date.Day(),
+    Max_f:        ParseNumber(d.Maxtempi),
+    Max_c:        ParseNumber(d.Maxtempm),
+    Min_f:        ParseNumber(d.Mintempi),
+    Min_c:        ParseNumber(d.Mintempm),
+    Avg_f:        ParseNumber(d.Meantempi),
+    Avg_c:        ParseNumber(d.Meantempm),
+    Hdd:          ParseNumber(d.Heatingdegreedays),
+    Hdd_normal:   ParseNumber(d.Heatingdegreedaysnormal),
+    Cdd:          ParseNumber(d.Coolingdegreedays),
+    Cdd_normal:   ParseNumber(d.Coolingdegreedaysnormal),
+    Precip_in:    ParseNumber(d.Precipi),
+    Precip_mm:    ParseNumber(d.Precipm),
+    Precip_trace: d.Precipi == "T",
+    Snow_in:      ParseNumber(d.Snowfalli),
+    Snow_mm:      ParseNumber(d.Snowfallm),
+    Snow_trace:   d.Snowfalli == "T",
+    Depth_in:     ParseNumber(d.Snowdepthi),
+    Depth_mm:     ParseNumber(d.Snowdepthm),
+    Wind_mph:     ParseNumber(d.Meanwindspdi),
+    Wind_kph:     ParseNumber(d.Meanwindspdm),
+    Max_wind_mph: ParseNumber(d.Maxwspdi),
+    Max_wind_kph: ParseNumber(d.Maxwspdm),
+    Avg_wind_dir: ParseNumber(d.Meanwdird),
+  }
+  if !day.Avg_f.Valid() {
+    day.Avg_f = Number(math.Round(float64(day.Max_f+day.Min_f) / 2))
+    day.Avg_c = Number(ftoc(float64(day.Avg_f)))
+  }
+  if !day.Hdd.Valid() && day.Avg_f.Valid() {
+    day.Hdd = Number(math.Max(65-float64(day.Avg_f), 0))
+  }
+  if !day.Cdd.Valid() && day.Avg_f.Valid() {
+    day.Cdd = Number(math.Max(float64(day.Avg_f)-65, 0))
+  }
+  if day.Precip_trace {
+    day.Precip_in, day.Precip_mm = 0, 0
+  }
+  if day.Snow_trace {
+    day.Snow_in, day.Snow_mm = 0, 0
+  }
+  if day.Hdd_normal.Valid() || day.Cdd_normal.Valid() {
+    normal := 65 - zeroIfMissing(day.Hdd_normal) + zeroIfMissing(day.Cdd_normal)
+    day.Departure_f = day.Avg_f - normal
+  } else {
+    day.Departure_f = Number(math.NaN())
+  }
+  day.Departure_c = day.Departure_f * 5 / 9
+
+  var codes []string
+  for _, flag := range []struct{ set, code string }{
+    {d.Fog, "1"}, {d.Thunder, "3"}, {d.Hail, "5"}, {d.Tornado, "X"},
+  } {
+    if flag.set == "1" {
+      codes = append(codes, flag.code)
+    }
+  }
+  day.Weather = strings.Join(codes, "")
+  return day
+}
+
+// missingClimateDay makes the line for a day with no history
+func missingClimateDay(date time.Time) ClimateDay {
+  var day ClimateDay
+  missingNumbers(&day)
+  day.Date, day.Day, day.Missing = date.Format("2006-01-02"), date.Day(), true
+  return day
+}
+
+func zeroIfMissing(n Number) Number {
+  if !n.Valid() {
+    return 0
+  }
+  return n
+}
+
+// ClimateDays makes the report's lines for the days from one date to
+// another from a station's archive
+func ClimateDays(archive Archive, from time.Time, to time.Time) []ClimateDay {
+  var days []ClimateDay
+  for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+    if summary, ok := archive[d.Format(archiveDate)]; ok {
+      days = append(days, NewClimateDay(d, summary))
+    } else {
+      days = append(days, missingClimateDay(d))
+    }
+  }
+  return days
+}
+
+// mean accumulates an average, skipping missing values
+type mean struct {
+  sum float64
+  n   int
+}
+
+func (m *mean) add(n Number) {
+  if n.Valid() {
+    m.sum += float64(n)
+    m.n++
+  }
+}
+
+func (m mean) value() Number {
+  if m.n == 0 {
+    return Number(math.NaN())
+  }
+  return Number(m.sum / float64(m.n))
+}
+
+func (m mean) total() Number {
+  if m.n == 0 {
+    return Number(math.NaN())
+  }
+  return Number(m.sum)
+}
+
+// extreme keeps the highest (or lowest) value and its date
+type extreme struct {
+  value Number
+  other Number // the value in the other units
+  date  string
+  low   bool
+}
+
+func (e *extreme) add(n Number, other Number, date string) {
+  if !n.Valid() {
+    return
+  }
+  if e.date == "" || (!e.low && n > e.value) || (e.low && n < e.value) {
+    e.value, e.other, e.date = n, other, date
+  }
+}
+
+func (e extreme) get() (Number, Number, string) {
+  if e.date == "" {
+    return Number(math.NaN()), Number(math.NaN()), ""
+  }
+  return e.value, e.other, e.date
+}
+
+// Summarize works out the totals, averages and extremes of some days
+func Summarize(days []ClimateDay) ClimateSummary {
+  var s ClimateSummary
+  var maxes, mins, avgs, deps, winds mean
+  var hdd, hddNormal, cdd, cddNormal, precip, precipMm, snow, snowMm mean
+  var windsKph, maxesC, minsC, avgsC mean
+  hottest, coldest := extreme{}, extreme{low: true}
+  wettest, deepest, windiest := extreme{}, extreme{}, extreme{}
+
+  for _, d := range days {
+    s.Days++
+    if d.Missing {
+      s.Missing++
+      continue
+    }
+    maxes.add(d.Max_f)
+    maxesC.add(d.Max_c)
+    mins.add(d.Min_f)
+    minsC.add(d.Min_c)
+    avgs.add(d.Avg_f)
+    avgsC.add(d.Avg_c)
+    deps.add(d.Departure_f)
+    hdd.add(d.Hdd)
+    hddNormal.add(d.Hdd_normal)
+    cdd.add(d.Cdd)
+    cddNormal.add(d.Cdd_normal)
+    precip.add(d.Precip_in)
+    precipMm.add(d.Precip_mm)
+    snow.add(d.Snow_in)
+    snowMm.add(d.Snow_mm)
+    winds.add(d.Wind_mph)
+    windsKph.add(d.Wind_kph)
+    hottest.add(d.Max_f, d.Max_c, d.Date)
+    coldest.add(d.Min_f, d.Min_c, d.Date)
+    wettest.add(d.Precip_in, d.Precip_mm, d.Date)
+    deepest.add(d.Depth_in, d.Depth_mm, d.Date)
+    windiest.add(d.Max_wind_mph, d.Max_wind_kph, d.Date)
+
+    count := func(n *int, ok bool) {
+      if ok {
+        *n++
+      }
+    }
+    count(&s.Days_max_90, d.Max_f >= 90)
+    count(&s.Days_max_32, d.Max_f <= 32)
+    count(&s.Days_min_32, d.Min_f <= 32)
+    count(&s.Days_min_0, d.Min_f <= 0)
+    count(&s.Days_precip_01, d.Precip_in >= 0.01)
+    count(&s.Days_precip_10, d.Precip_in >= 0.10)
+    count(&s.Days_precip_100, d.Precip_in >= 1.00)
+    count(&s.Days_snow_1, d.Snow_in >= 1.0)
+    count(&s.Days_fog, strings.Contains(d.Weather, "1"))
+    count(&s.Days_thunder, strings.Contains(d.Weather, "3"))
+    count(&s.Days_hail, strings.Contains(d.Weather, "5"))
+  }
+
+  s.Max_f, s.Max_c, s.Max_date = hottest.get()
+  s.Min_f, s.Min_c, s.Min_date = coldest.get()
+  s.Avg_max_f, s.Avg_max_c = maxes.value(), maxesC.value()
+  s.Avg_min_f, s.Avg_min_c = mins.value(), minsC.value()
+  s.Avg_f, s.Avg_c = avgs.value(), avgsC.value()
+  s.Departure_f = deps.value()
+  s.Departure_c = s.Departure_f * 5 / 9
+  s.Hdd, s.Hdd_normal = hdd.total(), hddNormal.total()
+  s.Cdd, s.Cdd_normal = cdd.total(), cddNormal.total()
+  s.Precip_in, s.Precip_mm = precip.total(), precipMm.total()
+  s.Max_precip_in, s.Max_precip_mm, s.Max_precip_date = wettest.get()
+  s.Snow_in, s.Snow_mm = snow.total(), snowMm.total()
+  s.Max_depth_in, s.Max_depth_mm, s.Max_depth_date = deepest.get()
+  s.Wind_mph, s.Wind_kph = winds.value(), windsKph.value()
+  s.Max_wind_mph, s.Max_wind_kph, s.Max_wind_date = windiest.get()
+  return s
+}
+
+// NewClimateMonth makes the climate report for a month, up to
+// yesterday if it's the current month
+func NewClimateMonth(archive Archive, year int, month time.Month) ClimateMonth {
+  from := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+  to := untilYesterday(from.AddDate(0, 1, -1))
+  days := ClimateDays(archive, from, to)
+  summary := Summarize(days)
+  summary.Period = from.Format("2006-01")
+  return ClimateMonth{
+    Year:    year,
+    Month:   int(month),
+    From:    from.Format("2006-01-02"),
+    To:      to.Format("2006-01-02"),
+    Days:    days,
+    Summary: summary,
+  }
+}
+
+// untilYesterday limits a report's last day to yesterday, the last
+// with a full day's history
+func untilYesterday(to time.Time) time.Time {
+  now := time.Now()
+  if yesterday := time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, time.UTC); to.After(yesterday) {
+    return yesterday
+  }
+  return to
+}
+
+// ClimateYear is the annual climate report: a summary for each month
+// and the year, with the year's dry spell, freezes and snow seasons
+type ClimateYear struct {
+  Year              int
+  From              string
+  To                string
+  Months            []ClimateSummary
+  Summary           ClimateSummary
+  Longest_dry_spell int
+  Dry_spell_from    string
+  Dry_spell_to      string
+  Last_freeze       string
+  Last_freeze_f     Number
+  Last_freeze_c     Number
+  First_freeze      string
+  First_freeze_f    Number
+  First_freeze_c    Number
+  Seasons           []SnowSeason
+}
+
+// SnowSeason is the snowfall of a season (July to June) up to the
+// last day of it in the report
+type SnowSeason struct {
+  Season  string
+  Through string
+  Snow_in Number
+  Snow_mm Number
+}
+
+// NewClimateYear makes the climate report for a year, up to
+// yesterday if it's the current year
+func NewClimateYear(archive Archive, year int) ClimateYear {
+  from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+  to := untilYesterday(time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC))
+  report := ClimateYear{
+    Year: year,
+    From: from.Format("2006-01-02"),
+    To:   to.Format("2006-01-02"),
+  }
+  for m := from; !m.After(to); m = m.AddDate(0, 1, 0) {
+    report.Months = append(report.Months, NewClimateMonth(archive, year, m.Month()).Summary)
+  }
+  days := ClimateDays(archive, from, to)
+  report.Summary = Summarize(days)
+  report.Summary.Period = fmt.Sprint(year)
+  report.Longest_dry_spell, report.Dry_spell_from, report.Dry_spell_to = DrySpell(days)
+
+  // The last freeze of spring is the last before July, the first of
+  // the fall the first after
+  report.Last_freeze_f, report.First_freeze_f = Number(math.NaN()), Number(math.NaN())
+  report.Last_freeze_c, report.First_freeze_c = Number(math.NaN()), Number(math.NaN())
+  for _, d := range days {
+    if d.Missing || !d.Min_f.Valid() || d.Min_f > freezeLow {
+      continue
+    }
+    if d.Date[5:7] < "07" {
+      report.Last_freeze, report.Last_freeze_f, report.Last_freeze_c = d.Date, d.Min_f, d.Min_c
+    } else if report.First_freeze == "" {
+      report.First_freeze, report.First_freeze_f, report.First_freeze_c = d.Date, d.Min_f, d.Min_c
+    }
+  }
+
+  report.Seasons = append(report.Seasons, snowSeason(archive, year-1, from, to))
+  if to.Month() >= time.July {
+    report.Seasons = append(report.Seasons, snowSeason(archive, year, from, to))
+  }
+  return report
+}
+
+// DrySpell finds the longest run of days without measurable
+// precipitation (a trace counts as dry; a missing day ends a run)
+func DrySpell(days []ClimateDay) (int, string, string) {
+  longest, from, to := 0, "", ""
+  run := 0
+  for i, d := range days {
+    if d.Missing || !d.Precip_in.Valid() || d.Precip_in >= 0.01 {
+      run = 0
+      continue
+    }
+    run++
+    if run > longest {
+      longest, from, to = run, days[i-run+1].Date, d.Date
+    }
+  }
+  return longest, from, to
+}
+
+// snowSeason reports the snowfall of the season starting in July of
+// a year from the "since July 1st" total of the last day archived in
+// it (and in the report), else by adding up the days
+func snowSeason(archive Archive, start int, from time.Time, to time.Time) SnowSeason {
+  first := time.Date(start, time.July, 1, 0, 0, 0, 0, time.UTC)
+  last := time.Date(start+1, time.June, 30, 0, 0, 0, 0, time.UTC)
+  if from.After(first) {
+    first = from
+  }
+  if to.Before(last) {
+    last = to
+  }
+  season := SnowSeason{Season: fmt.Sprintf("%d-%02d", start, (start+1)%100)}
+  var total, totalMm mean
+  for d := last; !d.Before(first); d = d.AddDate(0, 0, -1) {
+    day, ok := archive[d.Format(archiveDate)]
+    if !ok {
+      continue
+    }
+    if season.Through == "" {
+      season.Through = d.Format("2006-01-02")
+    }
+    if snow := ParseNumber(day.Since1julsnowfalli); snow.Valid() || day.Since1julsnowfalli == "T" {
+      season.Snow_in, season.Snow_mm = zeroIfMissing(snow), zeroIfMissing(ParseNumber(day.Since1julsnowfallm))
+      return season
+    }
+    total.add(ParseNumber(day.Snowfalli))
+    totalMm.add(ParseNumber(day.Snowfallm))
+  }
+  season.Snow_in, season.Snow_mm = total.total(), totalMm.total()
+  return season
+}
+
+const climateYearTemplate = `{{heading (printf (T "Climate summary for %d at %s") .Year station)}}
+
+{{heading (printf "%-10s %5s %5s %5s %5s %5s %5s %6s %5s %4s %4s %4s" (T "Month") "MAX" "MIN" "AVG" "DEP" "HDD" "CDD" "WTR" "SNW" ">=90" "<=32" "M")}}
+{{if celsius -}}
+{{range .Months}}{{printf "%-10s %5s %5s %5s %5s %5s %5s %6s %5s %4d %4d %4d" (month (slice .Period 5)) (measured 1 .Avg_max_c) (measured 1 .Avg_min_c) (measured 1 .Avg_c) (signed 1 .Departure_c) (measured 0 .Hdd) (measured 0 .Cdd) (measured 1 .Precip_mm) (measured 0 .Snow_mm) .Days_max_90 .Days_min_32 .Missing}}
+{{end}}{{with .Summary}}{{printf "%-10s %5s %5s %5s %5s %5s %5s %6s %5s %4d %4d %4d" .Period (measured 1 .Avg_max_c) (measured 1 .Avg_min_c) (measured 1 .Avg_c) (signed 1 .Departure_c) (measured 0 .Hdd) (measured 0 .Cdd) (measured 1 .Precip_mm) (measured 0 .Snow_mm) .Days_max_90 .Days_min_32 .Missing}}
+
+{{T "Highest"}}: {{measured 0 .Max_c}} C{{with .Max_date}} ({{.}}){{end}}    {{T "Lowest"}}: {{measured 0 .Min_c}} C{{with .Min_date}} ({{.}}){{end}}
+{{T "Wettest day"}}: {{measured 1 .Max_precip_mm}} mm{{with .Max_precip_date}} ({{.}}){{end}}
+{{- end}}
+{{- else -}}
+{{range .Months}}{{printf "%-10s %5s %5s %5s %5s %5s %5s %6s %5s %4d %4d %4d" (month (slice .Period 5)) (measured 1 .Avg_max_f) (measured 1 .Avg_min_f) (measured 1 .Avg_f) (signed 1 .Departure_f) (measured 0 .Hdd) (measured 0 .Cdd) (measured 2 .Precip_in) (measured 1 .Snow_in) .Days_max_90 .Days_min_32 .Missing}}
+{{end}}{{with .Summary}}{{printf "%-10s %5s %5s %5s %5s %5s %5s %6s %5s %4d %4d %4d" .Period (measured 1 .Avg_max_f) (measured 1 .Avg_min_f) (measured 1 .Avg_f) (signed 1 .Departure_f) (measured 0 .Hdd) (measured 0 .Cdd) (measured 2 .Precip_in) (measured 1 .Snow_in) .Days_max_90 .Days_min_32 .Missing}}
+
+{{T "Highest"}}: {{measured 0 .Max_f}} F{{with .Max_date}} ({{.}}){{end}}    {{T "Lowest"}}: {{measured 0 .Min_f}} F{{with .Min_date}} ({{.}}){{end}}
+{{T "Wettest day"}}: {{measured 2 .Max_precip_in}} in{{with .Max_precip_date}} ({{.}}){{end}}
+{{- end}}
+{{- end}}
+{{T "Longest dry spell"}}: {{printf (plural .Longest_dry_spell "%d day" "%d days") .Longest_dry_spell}}{{if .Dry_spell_from}} ({{.Dry_spell_from}} - {{.Dry_spell_to}}){{end}}
+{{T "Last freeze"}}: {{if .Last_freeze}}{{.Last_freeze}} ({{if celsius}}{{measured 0 .Last_freeze_c}} C{{else}}{{measured 0 .Last_freeze_f}} F{{end}}){{else}}{{T "none"}}{{end}}    {{T "First freeze"}}: {{if .First_freeze}}{{.First_freeze}} ({{if celsius}}{{measured 0 .First_freeze_c}} C{{else}}{{measured 0 .First_freeze_f}} F{{end}}){{else}}{{T "none"}}{{end}}
+{{range .Seasons}}{{printf (T "Snowfall %s season") .Season}}: {{if celsius}}{{measured 0 .Snow_mm}} mm{{else}}{{measured 1 .Snow_in}} in{{end}}{{with .Through}} ({{T "through"}} {{.}}){{end}}
+{{end}}{{if .Summary.Missing}}{{printf (plural .Summary.Missing "%d day is missing: fill it in with \"wu backfill --from %s --to %s\"" "%d days are missing: fill them in with \"wu backfill --from %s --to %s\"") .Summary.Missing .From .To}}
+{{end}}`
+
+const climateTemplate = `{{heading (printf (T "Climate summary for %s %d at %s") (month .Month) .Year station)}}
+
+{{heading (printf "%2s %3s %3s %3s %4s %3s %3s %5s %4s %4s %4s %3s %4s %s" "DY" "MAX" "MIN" "AVG" "DEP" "HDD" "CDD" "WTR" "SNW" "DPTH" "SPD" "MX" "ADIR" "WX")}}
+{{if celsius -}}
+{{range .Days}}{{printf "%2d %3s %3s %3s %4s %3s %3s %5s %4s %4s %4s %3s %4s %s" .Day (measured 0 .Max_c) (measured 0 .Min_c) (measured 0 .Avg_c) (signed 1 .Departure_c) (measured 0 .Hdd) (measured 0 .Cdd) (amount 1 .Precip_mm .Precip_trace) (amount 0 .Snow_mm .Snow_trace) (measured 0 .Depth_mm) (measured 0 .Wind_kph) (measured 0 .Max_wind_kph) (measured 0 .Avg_wind_dir) .Weather}}
+{{end}}{{with .Summary}}{{printf "%2s %3s %3s %3s %4s %3s %3s %5s %4s" "SM" "" "" "" "" (measured 0 .Hdd) (measured 0 .Cdd) (measured 1 .Precip_mm) (measured 0 .Snow_mm)}}
+{{printf "%2s %3s %3s %3s %4s %28s" "AV" (measured 0 .Avg_max_c) (measured 0 .Avg_min_c) (measured 0 .Avg_c) (signed 1 .Departure_c) (measured 1 .Wind_kph)}}
+
+{{T "Highest"}}: {{measured 0 .Max_c}} C{{with .Max_date}} ({{.}}){{end}}    {{T "Lowest"}}: {{measured 0 .Min_c}} C{{with .Min_date}} ({{.}}){{end}}
+{{T "Precipitation"}}: {{measured 1 .Precip_mm}} mm    {{T "Greatest in a day"}}: {{measured 1 .Max_precip_mm}} mm{{with .Max_precip_date}} ({{.}}){{end}}
+{{T "Snowfall"}}: {{measured 0 .Snow_mm}} mm    {{T "Greatest depth"}}: {{measured 0 .Max_depth_mm}} mm{{with .Max_depth_date}} ({{.}}){{end}}
+{{T "Average wind"}}: {{measured 1 .Wind_kph}} km/h    {{T "Highest wind"}}: {{measured 0 .Max_wind_kph}} km/h{{with .Max_wind_date}} ({{.}}){{end}}
+{{- end}}
+{{- else -}}
+{{range .Days}}{{printf "%2d %3s %3s %3s %4s %3s %3s %5s %4s %4s %4s %3s %4s %s" .Day (measured 0 .Max_f) (measured 0 .Min_f) (measured 0 .Avg_f) (signed 0 .Departure_f) (measured 0 .Hdd) (measured 0 .Cdd) (amount 2 .Precip_in .Precip_trace) (amount 1 .Snow_in .Snow_trace) (measured 0 .Depth_in) (measured 1 .Wind_mph) (measured 0 .Max_wind_mph) (measured 0 .Avg_wind_dir) .Weather}}
+{{end}}{{with .Summary}}{{printf "%2s %3s %3s %3s %4s %3s %3s %5s %4s" "SM" "" "" "" "" (measured 0 .Hdd) (measured 0 .Cdd) (measured 2 .Precip_in) (measured 1 .Snow_in)}}
+{{printf "%2s %3s %3s %3s %4s %28s" "AV" (measured 0 .Avg_max_f) (measured 0 .Avg_min_f) (measured 0 .Avg_f) (signed 1 .Departure_f) (measured 1 .Wind_mph)}}
+
+{{T "Highest"}}: {{measured 0 .Max_f}} F{{with .Max_date}} ({{.}}){{end}}    {{T "Lowest"}}: {{measured 0 .Min_f}} F{{with .Min_date}} ({{.}}){{end}}
+{{T "Precipitation"}}: {{measured 2 .Precip_in}} in    {{T "Greatest in a day"}}: {{measured 2 .Max_precip_in}} in{{with .Max_precip_date}} ({{.}}){{end}}
+{{T "Snowfall"}}: {{measured 1 .Snow_in}} in    {{T "Greatest depth"}}: {{measured 0 .Max_depth_in}} in{{with .Max_depth_date}} ({{.}}){{end}}
+{{T "Average wind"}}: {{measured 1 .Wind_mph}} mph    {{T "Highest wind"}}: {{measured 0 .Max_wind_mph}} mph{{with .Max_wind_date}} ({{.}}){{end}}
+{{- end}}
+{{- end}}
+{{with .Summary -}}
+{{T "Heating degree days"}}: {{measured 0 .Hdd}} ({{T "normal"}} {{measured 0 .Hdd_normal}})    {{T "Cooling degree days"}}: {{measured 0 .Cdd}} ({{T "normal"}} {{measured 0 .Cdd_normal}})
+{{T "Days with"}}: {{T "max >= 90 F"}} {{.Days_max_90}}, {{T "max <= 32 F"}} {{.Days_max_32}}, {{T "min <= 32 F"}} {{.Days_min_32}}, {{T "min <= 0 F"}} {{.Days_min_0}}
+{{T "Days with precipitation"}}: >= 0.01 in {{.Days_precip_01}}, >= 0.10 in {{.Days_precip_10}}, >= 1.00 in {{.Days_precip_100}}; {{T "snow >= 1 in"}} {{.Days_snow_1}}
+{{T "Days with"}}: {{T "fog"}} {{.Days_fog}}, {{T "thunder"}} {{.Days_thunder}}, {{T "hail"}} {{.Days_hail}}
+WX: 1 = {{T "fog"}}, 3 = {{T "thunder"}}, 5 = {{T "hail"}}, X = {{T "tornado"}}; M = {{T "missing"}}, T = {{T "trace"}}
+{{if .Missing}}{{printf (plural .Missing "%d day is missing: fill it in with \"wu backfill --from %s --to %s\"" "%d days are missing: fill them in with \"wu backfill --from %s --to %s\"") .Missing $.From $.To}}
+{{end}}{{end}}`
+
+// parseMonth reads a month as YYYY-MM
+func parseMonth(s string) (int, time.Month, error) {
+  t, err := time.Parse("2006-01", s)
+  if err != nil {
+    return 0, 0, fmt.Errorf("%q is not a month (YYYY-MM)", s)
+  }
+  return t.Year(), t.Month(), nil
+}
+
+// writeWorkbook writes sheets to a file (or a default name)
+func writeWorkbook(path string, sheets []Sheet) {
+  f, err := os.Create(path)
+  CheckError(err)
+  CheckError(WriteXLSX(f, sheets))
+  CheckError(f.Close())
+  fmt.Println(T("Wrote") + " " + path)
+}
+
+// Climate runs "wu climate", which reports a month's or a year's
+// climate from the station's archived history
+func Climate(args []string) {
+  ReadConf()
+  flags := flag.NewFlagSet("climate", flag.ExitOnError)
+  stationFlag := flags.String("s", "", "Weather station (defaults to the one in .condrc)")
+  monthFlag := flags.String("month", "", "The month to report (YYYY-MM)")
+  yearFlag := flags.Int("year", 0, "The year to report")
+  out := flags.String("out", "", "With --format=xlsx, the file to write (defaults to climate-MONTH.xlsx or climate-YEAR.xlsx)")
+  flags.StringVar(&outputFormat, "format", "text", "Output format: text, json, csv, or xlsx")
+  flags.Parse(args)
+  if outputFormat != "xlsx" {
+    checkFormat()
+  }
+
+  if (*monthFlag == "") == (*yearFlag == 0) {
+    fmt.Println("Usage: wu climate --month YYYY-MM | --year YYYY [-s station] [--format text|json|csv|xlsx] [--out FILE]")
+    os.Exit(2)
+  }
+  station := CommandStation(*stationFlag)
+  if *yearFlag != 0 {
+    climateYear(station, *yearFlag, *out)
+    return
+  }
+  year, month, err := parseMonth(*monthFlag)
+  CheckError(err)
+  climateMonth(station, year, month, *out)
+}
+
+// climateMonth prints or writes the climate report for a month
+func climateMonth(station string, year int, month time.Month, out string) {
+  if time.Date(year, month, 2, 0, 0, 0, 0, time.UTC).After(time.Now()) {
+    CheckError(fmt.Errorf("%04d-%02d hasn't happened yet", year, int(month)))
+  }
+  report := NewClimateMonth(LoadArchive(station), year, month)
+
+  if outputFormat == "xlsx" {
+    if out == "" {
+      out = fmt.Sprintf("climate-%04d-%02d.xlsx", year, int(month))
+    }
+    writeWorkbook(out, []Sheet{
+      TableSheet(report.Summary.Period, report.Days),
+      FieldSheet("Summary", report.Summary),
+    })
+    return
+  }
+  Render("climate", station, conf.Degrees, &report)
+}
+
+// climateYear prints or writes the climate report for a year.  The
+// workbook has sheets for the months, the year, the snow seasons and
+// each month's days.
+func climateYear(station string, year int, out string) {
+  if time.Date(year, time.January, 2, 0, 0, 0, 0, time.UTC).After(time.Now()) {
+    CheckError(fmt.Errorf("%d hasn't happened yet", year))
+  }
+  archive := LoadArchive(station)
+  report := NewClimateYear(archive, year)
+
+  if outputFormat == "xlsx" {
+    if out == "" {
+      out = fmt.Sprintf("climate-%d.xlsx", year)
+    }
+    sheets := []Sheet{
+      TableSheet("Months", report.Months),
+      FieldSheet("Year", report),
+      TableSheet("Snow seasons", report.Seasons),
+    }
+    for m := range report.Months {
+      month := NewClimateMonth(archive, year, time.Month(m+1))
+      sheets = append(sheets, TableSheet(month.Summary.Period, month.Days))
+    }
+    writeWorkbook(out, sheets)
+    return
+  }
+  Render("climateyear", station, conf.Degrees, &report)
+}
@@ -0,0 +1,191 @@
+/*
+* climate_test.go
+*
+* This file is part of wu.  It contains tests for the monthly and
+* annual climate reports.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 02:10:57 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "math"
+  "testing"
+  "time"
+)
+
+// climateArchive makes an archive of days from 2020-01-01 on, one for
+// each summary; a nil summary leaves its day out
+func climateArchive(days ...*Dailysummary) Archive {
+  archive := Archive{}
+  for i, d := range days {
+    if d != nil {
+      archive[time.Date(2020, 1, 1+i, 0, 0, 0, 0, time.UTC).Format(archiveDate)] = *d
+    }
+  }
+  return archive
+}
+
+// near reports whether a Number is within 0.001 of a value
+func near(n Number, want float64) bool {
+  return math.Abs(float64(n)-want) < 0.001
+}
+
+func TestSummarize(t *testing.T) {
+  archive := climateArchive(
+    &Dailysummary{Maxtempi: "91", Mintempi: "60", Precipi: "0.50", Thunder: "1"},
+    &Dailysummary{Maxtempi: "30", Mintempi: "-2", Precipi: "T", Snowfalli: "1.5", Fog: "1"},
+    nil,
+    &Dailysummary{Maxtempi: "50", Mintempi: "33", Precipi: "0.05", Snowfalli: "T"},
+  )
+  from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+  s := Summarize(ClimateDays(archive, from, from.AddDate(0, 0, 3)))
+
+  if s.Days != 4 || s.Missing != 1 {
+    t.Errorf("%d days, %d missing; want 4, 1", s.Days, s.Missing)
+  }
+  if s.Max_f != 91 || s.Max_date != "2020-01-01" || s.Min_f != -2 || s.Min_date != "2020-01-02" {
+    t.Errorf("extremes %v on %s and %v on %s; want 91 on 2020-01-01 and -2 on 2020-01-02",
+      s.Max_f, s.Max_date, s.Min_f, s.Min_date)
+  }
+  if !near(s.Avg_max_f, 57) || !near(s.Avg_min_f, 91.0/3) {
+    t.Errorf("average high %v and low %v; want 57 and 30.333", s.Avg_max_f, s.Avg_min_f)
+  }
+  // a trace counts as no precipitation or snow
+  if !near(s.Precip_in, 0.55) || !near(s.Snow_in, 1.5) || s.Max_precip_date != "2020-01-01" {
+    t.Errorf("precipitation %v (most on %s), snow %v; want 0.55 (2020-01-01), 1.5",
+      s.Precip_in, s.Max_precip_date, s.Snow_in)
+  }
+  counts := []struct {
+    name      string
+    got, want int
+  }{
+    {"highs >= 90", s.Days_max_90, 1},
+    {"highs <= 32", s.Days_max_32, 1},
+    {"lows <= 32", s.Days_min_32, 1},
+    {"lows <= 0", s.Days_min_0, 1},
+    {"precipitation >= 0.01", s.Days_precip_01, 2},
+    {"precipitation >= 0.10", s.Days_precip_10, 1},
+    {"precipitation >= 1.00", s.Days_precip_100, 0},
+    {"snow >= 1.0", s.Days_snow_1, 1},
+    {"fog", s.Days_fog, 1},
+    {"thunder", s.Days_thunder, 1},
+    {"hail", s.Days_hail, 0},
+  }
+  for _, c := range counts {
+    if c.got != c.want {
+      t.Errorf("days with %s: %d; want %d", c.name, c.got, c.want)
+    }
+  }
+
+  if empty := Summarize(nil); empty.Max_f.Valid() || empty.Precip_in.Valid() || empty.Max_date != "" {
+    t.Errorf("no days: extremes %v on %q, precipitation %v; want none", empty.Max_f, empty.Max_date, empty.Precip_in)
+  }
+}
+
+func TestDrySpell(t *testing.T) {
+  // dry, trace, wet, three dry, missing, dry
+  archive := climateArchive(
+    &Dailysummary{Precipi: "0.00"},
+    &Dailysummary{Precipi: "T"},
+    &Dailysummary{Precipi: "0.05"},
+    &Dailysummary{Precipi: "0"},
+    &Dailysummary{Precipi: "0.00"},
+    &Dailysummary{Precipi: "T"},
+    nil,
+    &Dailysummary{Precipi: "0"},
+  )
+  from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+  n, first, last := DrySpell(ClimateDays(archive, from, from.AddDate(0, 0, 7)))
+  if n != 3 || first != "2020-01-04" || last != "2020-01-06" {
+    t.Errorf("longest dry spell %d days, %s to %s; want 3, 2020-01-04 to 2020-01-06", n, first, last)
+  }
+  if n, _, _ := DrySpell(ClimateDays(archive, from.AddDate(0, 0, 2), from.AddDate(0, 0, 2))); n != 0 {
+    t.Errorf("a wet day: dry spell of %d days; want 0", n)
+  }
+}
+
+func TestSnowSeason(t *testing.T) {
+  day := func(month time.Month, d int) string {
+    return time.Date(2020, month, d, 0, 0, 0, 0, time.UTC).Format(archiveDate)
+  }
+  from := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+  to := time.Date(2020, time.December, 31, 0, 0, 0, 0, time.UTC)
+  tests := []struct {
+    name    string
+    archive Archive
+    start   int
+    through string
+    snow    float64 // NaN for none
+  }{
+    {"season total", Archive{
+      day(time.January, 10): {Snowfalli: "2.0", Since1julsnowfalli: "10.0"},
+      day(time.March, 2):    {Snowfalli: "1.0", Since1julsnowfalli: "14.5"},
+    }, 2019, "2020-03-02", 14.5},
+    {"added up", Archive{
+      day(time.January, 10): {Snowfalli: "2.0"},
+      day(time.March, 2):    {Snowfalli: "1.0"},
+      day(time.March, 3):    {Snowfalli: "T"},
+    }, 2019, "2020-03-03", 3.0},
+    {"a trace", Archive{
+      day(time.November, 20): {Snowfalli: "T", Since1julsnowfalli: "T"},
+    }, 2020, "2020-11-20", 0},
+    // the fall's snow belongs to the next season
+    {"another season", Archive{
+      day(time.November, 20): {Snowfalli: "4.0", Since1julsnowfalli: "4.0"},
+    }, 2019, "", math.NaN()},
+  }
+  for _, test := range tests {
+    s := snowSeason(test.archive, test.start, from, to)
+    if s.Through != test.through {
+      t.Errorf("%s: through %q; want %q", test.name, s.Through, test.through)
+    }
+    if math.IsNaN(test.snow) && s.Snow_in.Valid() || !math.IsNaN(test.snow) && !near(s.Snow_in, test.snow) {
+      t.Errorf("%s: %v inches; want %v", test.name, s.Snow_in, test.snow)
+    }
+  }
+  if s := snowSeason(Archive{}, 2019, from, to); s.Season != "2019-20" {
+    t.Errorf("season %q; want 2019-20", s.Season)
+  }
+}
+
+func TestClimateYearFreezes(t *testing.T) {
+  day := func(month time.Month, d int) string {
+    return time.Date(2020, month, d, 0, 0, 0, 0, time.UTC).Format(archiveDate)
+  }
+  // a day without a low isn't a freeze
+  report := NewClimateYear(Archive{
+    day(time.March, 1):    {Mintempi: "20"},
+    day(time.April, 12):   {Mintempi: "31"},
+    day(time.May, 5):      {Maxtempi: "70"},
+    day(time.October, 1):  {Maxtempi: "60"},
+    day(time.October, 20): {Mintempi: "32"},
+    day(time.November, 2): {Mintempi: "25"},
+  }, 2020)
+  if report.Last_freeze != "2020-04-12" || report.Last_freeze_f != 31 {
+    t.Errorf("last freeze %s (%v); want 2020-04-12 (31)", report.Last_freeze, report.Last_freeze_f)
+  }
+  if report.First_freeze != "2020-10-20" || report.First_freeze_f != 32 {
+    t.Errorf("first freeze %s (%v); want 2020-10-20 (32)", report.First_freeze, report.First_freeze_f)
+  }
+}
@@ -0,0 +1,234 @@
+/*
+* colors.go
+*
+* This file is part of wu.  It contains functions for styling
+* output with color and weather glyphs (the --color switch and
+* the "theme" section of .condrc).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 01:47:31 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "os"
+  "regexp"
+  "strconv"
+  "strings"
+)
+
+// Theme sets the colors used for styled output.  Each is a list of
+// color names ("red", "bright-blue", "bold", ...) or raw ANSI SGR
+// codes ("38;5;208"); an empty string leaves the default.
+type Theme struct {
+  Freezing string // temperatures below 32 F
+  Cold     string // 32-49 F
+  Mild     string // 50-69 F
+  Warm     string // 70-84 F
+  Hot      string // 85 F and above
+  Alert    string
+  Precip   string
+  Heading  string
+  Icons    *bool // weather glyphs beside the sky conditions (default true)
+}
+
+var defaultTheme = Theme{
+  Freezing: "blue",
+  Cold:     "cyan",
+  Mild:     "green",
+  Warm:     "yellow",
+  Hot:      "red",
+  Alert:    "bold red",
+  Precip:   "blue",
+  Heading:  "bold",
+}
+
+var sgrNames = map[string]string{
+  "bold": "1", "dim": "2", "italic": "3", "underline": "4",
+  "black": "30", "red": "31", "green": "32", "yellow": "33",
+  "blue": "34", "magenta": "35", "cyan": "36", "white": "37",
+  "bright-black": "90", "bright-red": "91", "bright-green": "92", "bright-yellow": "93",
+  "bright-blue": "94", "bright-magenta": "95", "bright-cyan": "96", "bright-white": "97",
+}
+
+var sgrCode = regexp.MustCompile(`^[0-9;]+$`)
+
+// colorEnabled is decided once, from --color, $NO_COLOR and whether
+// standard out is a terminal
+var colorEnabled *bool
+
+// checkColor exits if --color isn't one wu knows
+func checkColor() {
+  if colorMode != "auto" && colorMode != "always" && colorMode != "never" {
+    fmt.Fprintln(os.Stderr, "--color must be auto, always, or never")
+    os.Exit(1)
+  }
+}
+
+// UseColor reports whether output should be styled
+func UseColor() bool {
+  if colorEnabled == nil {
+    enabled := false
+    switch colorMode {
+    case "always":
+      enabled = true
+    case "never":
+    default:
+      enabled = os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb" && IsTerminal(os.Stdout)
+    }
+    colorEnabled = &enabled
+  }
+  return *colorEnabled
+}
+
+// sgr turns a theme color into an ANSI escape sequence
+func sgr(color string) string {
+  var codes []string
+  for _, word := range strings.FieldsFunc(strings.ToLower(color), func(r rune) bool { return r == ' ' || r == ',' }) {
+    if code, ok := sgrNames[word]; ok {
+      codes = append(codes, code)
+    } else if sgrCode.MatchString(word) {
+      codes = append(codes, word)
+    }
+  }
+  if len(codes) == 0 {
+    return ""
+  }
+  return "\033[" + strings.Join(codes, ";") + "m"
+}
+
+// themeColor returns the configured color for a role, or the default
+func themeColor(configured string, fallback string) string {
+  if configured != "" {
+    return configured
+  }
+  return fallback
+}
+
+// Style wraps text in a color if output is being styled
+func Style(color string, text string) string {
+  if !UseColor() || text == "" {
+    return text
+  }
+  code := sgr(color)
+  if code == "" {
+    return text
+  }
+  return code + text + "\033[0m"
+}
+
+// StyleTemp colors a temperature string ("72 F (22 C)", "22 C (72 F)",
+// "-2") along a cold-to-hot gradient.  Bare numbers are taken to be
+// in the user's preferred degrees.
+func StyleTemp(temp string) string {
+  if !UseColor() {
+    return temp
+  }
+  fields := strings.Fields(temp)
+  if len(fields) == 0 {
+    return temp
+  }
+  t, err := strconv.ParseFloat(strings.TrimSuffix(fields[0], "°"), 64)
+  if err != nil {
+    return temp
+  }
+  celsius := conf.Degrees == "C"
+  if len(fields) > 1 {
+    celsius = strings.HasPrefix(fields[1], "C") || strings.HasPrefix(fields[1], "°C")
+  }
+  if celsius {
+    t = t*9/5 + 32
+  }
+
+  theme := conf.Theme
+  var color string
+  switch {
+  case t < 32:
+    color = themeColor(theme.Freezing, defaultTheme.Freezing)
+  case t < 50:
+    color = themeColor(theme.Cold, defaultTheme.Cold)
+  case t < 70:
+    color = themeColor(theme.Mild, defaultTheme.Mild)
+  case t < 85:
+    color = themeColor(theme.Warm, defaultTheme.Warm)
+  default:
+    color = themeColor(theme.Hot, defaultTheme.Hot)
+  }
+  return Style(color, temp)
+}
+
+// StyleAlert, StylePrecip and StyleHeading color text in the
+// theme's alert, precipitation and heading colors
+func StyleAlert(text string) string {
+  return Style(themeColor(conf.Theme.Alert, defaultTheme.Alert), text)
+}
+
+func StylePrecip(text string) string {
+  return Style(themeColor(conf.Theme.Precip, defaultTheme.Precip), text)
+}
+
+func StyleHeading(text string) string {
+  return Style(themeColor(conf.Theme.Heading, defaultTheme.Heading), text)
+}
+
+// skyGlyphs pairs words in the sky conditions with a glyph; the
+// first match wins, so the more severe conditions come first
+var skyGlyphs = []struct {
+  word  string
+  glyph string
+}{
+  {"thunder", "⚡"},
+  {"snow", "❄"},
+  {"sleet", "❄"},
+  {"ice", "❄"},
+  {"hail", "❄"},
+  {"rain", "☂"},
+  {"drizzle", "☂"},
+  {"shower", "☂"},
+  {"fog", "≡"},
+  {"haze", "≡"},
+  {"mist", "≡"},
+  {"smoke", "≡"},
+  {"partly", "⛅"},
+  {"scattered", "⛅"},
+  {"mostly", "☁"},
+  {"cloud", "☁"},
+  {"overcast", "☁"},
+  {"clear", "☀"},
+  {"sunny", "☀"},
+}
+
+// StyleSky puts a weather glyph before the sky conditions when
+// output is being styled
+func StyleSky(sky string) string {
+  if !UseColor() || (conf.Theme.Icons != nil && !*conf.Theme.Icons) {
+    return sky
+  }
+  lower := strings.ToLower(sky)
+  for _, g := range skyGlyphs {
+    if strings.Contains(lower, g.word) {
+      return g.glyph + " " + sky
+    }
+  }
+  return sky
+}
@@ -0,0 +1,214 @@
+/*
+* comfort.go
+*
+* This file is part of wu.  It contains the comfort scales that
+* describe how muggy the air feels (the "comfort" setting in
+* .condrc).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 00:37:09 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "encoding/json"
+  "fmt"
+  "math"
+  "regexp"
+  "strconv"
+  "strings"
+)
+
+// Temperature is a temperature in degrees Celsius; DegreesF and
+// DegreesC make one from a reading in either scale
+type Temperature float64
+
+func DegreesF(f float64) Temperature { return Temperature((f - 32) * 5 / 9) }
+func DegreesC(c float64) Temperature { return Temperature(c) }
+
+// In returns the temperature in Fahrenheit ("F") or Celsius (any
+// other unit)
+func (t Temperature) In(unit string) float64 {
+  if unit == "F" {
+    return float64(t)*9/5 + 32
+  }
+  return float64(t)
+}
+
+var temperaturePattern = regexp.MustCompile(`^\s*(-?[0-9]+(?:\.[0-9]+)?)\s*°?\s*([FCfc]?)`)
+
+// ParseTemperature reads a temperature such as "62 F (17 C)",
+// "16.5 C" or "62°F"; the API's readings are in Fahrenheit unless
+// they say otherwise.  It returns NaN if there isn't one.
+func ParseTemperature(s string) Temperature {
+  m := temperaturePattern.FindStringSubmatch(s)
+  if m == nil {
+    return Temperature(math.NaN())
+  }
+  value, _ := strconv.ParseFloat(m[1], 64)
+  if strings.ToUpper(m[2]) == "C" {
+    return DegreesC(value)
+  }
+  return DegreesF(value)
+}
+
+// ComfortBand is one band of a comfort scale: readings below Below
+// (or any reading, if Below is left out) get its label
+type ComfortBand struct {
+  Below Number
+  Label string
+}
+
+func (b *ComfortBand) UnmarshalJSON(data []byte) error {
+  type band ComfortBand // without this method
+  missingNumbers(b)
+  return json.Unmarshal(data, (*band)(b))
+}
+
+// ComfortScale classifies either the dewpoint (in Units, "F" or
+// "C") or the humidex by a table of bands, lowest first
+type ComfortScale struct {
+  Measure string
+  Units   string
+  Bands   []ComfortBand
+}
+
+// comfortScales are the built-in scales.  "dewpoint" is the one wu
+// has always used; "humidex" is Environment Canada's.  Others can be
+// defined under "comfort_scales" in .condrc.
+var comfortScales = map[string]ComfortScale{
+  "dewpoint": {"dewpoint", "F", []ComfortBand{
+    {50, "dry"},
+    {55, "very comfortable"},
+    {60, "comfortable"},
+    {65, "okay for most"},
+    {70, "somewhat uncomfortable"},
+    {75, "very humid"},
+    {80, "oppressive"},
+    {Number(math.NaN()), "dangerously high"},
+  }},
+  "humidex": {"humidex", "", []ComfortBand{
+    {30, "little or no discomfort"},
+    {40, "some discomfort"},
+    {46, "great discomfort"},
+    {Number(math.NaN()), "dangerous"},
+  }},
+}
+
+// Comfort is how the air feels on a comfort scale: the reading the
+// scale goes by and the label of its band
+type Comfort struct {
+  Scale string
+  Value Number
+  Label string
+}
+
+// lookupComfortScale returns the scale with a name, defined in the
+// configuration or built in
+func lookupComfortScale(name string) (ComfortScale, bool) {
+  if s, ok := conf.Comfort_scales[name]; ok {
+    return s, true
+  }
+  s, ok := comfortScales[name]
+  return s, ok
+}
+
+// comfortScale returns the scale the configuration calls for
+// ("comfort"), or the dewpoint scale
+func comfortScale() (string, ComfortScale) {
+  if s, ok := lookupComfortScale(conf.Comfort); ok {
+    return conf.Comfort, s
+  }
+  return "dewpoint", comfortScales["dewpoint"]
+}
+
+// Classify returns the label of the band a reading falls in, or ""
+// if there is no reading
+func (s ComfortScale) Classify(value float64) string {
+  if math.IsNaN(value) || len(s.Bands) == 0 {
+    return ""
+  }
+  for _, b := range s.Bands {
+    if !b.Below.Valid() || value < float64(b.Below) {
+      return b.Label
+    }
+  }
+  return s.Bands[len(s.Bands)-1].Label
+}
+
+// ComfortOf classifies the air at a temperature and dewpoint on the
+// configured comfort scale
+func ComfortOf(t Temperature, dewpoint Temperature) Comfort {
+  name, s := comfortScale()
+  var value float64
+  if s.Measure == "humidex" {
+    value = Humidex(t, dewpoint)
+  } else {
+    value = dewpoint.In(s.Units)
+  }
+  return Comfort{name, Number(value), s.Classify(value)}
+}
+
+// DewpointComfort describes how a dewpoint (e.g. "62 F (17 C)") feels,
+// on the configured scale if it goes by the dewpoint
+func DewpointComfort(dewpoint string) string {
+  _, s := comfortScale()
+  if s.Measure != "dewpoint" {
+    s = comfortScales["dewpoint"]
+  }
+  return T(s.Classify(ParseTemperature(dewpoint).In(s.Units)))
+}
+
+// Humidex returns Environment Canada's humidex for a temperature and
+// dewpoint
+func Humidex(t Temperature, dewpoint Temperature) float64 {
+  e := 6.11 * math.Exp(5417.7530*(1/273.16-1/(273.15+float64(dewpoint))))
+  return float64(t) + 0.5555*(e-10)
+}
+
+// validateComfort returns the problems with the comfort settings
+func validateComfort(c *Config) []string {
+  var problems []string
+  if _, ok := c.Comfort_scales[c.Comfort]; !ok && c.Comfort != "" {
+    if _, ok := comfortScales[c.Comfort]; !ok {
+      problems = append(problems, fmt.Sprintf("comfort: no scale named %q", c.Comfort))
+    }
+  }
+  for name, s := range c.Comfort_scales {
+    switch {
+    case s.Measure != "dewpoint" && s.Measure != "humidex":
+      problems = append(problems, fmt.Sprintf("comfort_scales: %s: measure must be \"dewpoint\" or \"humidex\", not %q", name, s.Measure))
+    case s.Measure == "dewpoint" && s.Units != "F" && s.Units != "C":
+      problems = append(problems, fmt.Sprintf("comfort_scales: %s: units must be \"F\" or \"C\", not %q", name, s.Units))
+    case len(s.Bands) == 0:
+      problems = append(problems, fmt.Sprintf("comfort_scales: %s: no bands", name))
+    }
+    for i := 1; i < len(s.Bands); i++ {
+      prev, b := s.Bands[i-1].Below, s.Bands[i].Below
+      if !prev.Valid() || (b.Valid() && b <= prev) {
+        problems = append(problems, fmt.Sprintf("comfort_scales: %s: bands must be in increasing order, with only the last open-ended", name))
+        break
+      }
+    }
+  }
+  return problems
+}
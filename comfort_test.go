@@ -0,0 +1,102 @@
+/*
+* comfort_test.go
+*
+* This file is part of wu.  It contains tests for the comfort
+* scales.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 02:33:11 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "math"
+  "testing"
+)
+
+func TestHumidex(t *testing.T) {
+  // from Environment Canada's table
+  tests := []struct{ t, dewpoint, humidex float64 }{
+    {30, 15, 34},
+    {35, 25, 47},
+    {25, 20, 33},
+  }
+  for _, test := range tests {
+    if got := Humidex(DegreesC(test.t), DegreesC(test.dewpoint)); math.Round(got) != test.humidex {
+      t.Errorf("Humidex(%v, %v) = %.1f; want %v", test.t, test.dewpoint, got, test.humidex)
+    }
+  }
+}
+
+func TestClassify(t *testing.T) {
+  dewpoint := comfortScales["dewpoint"]
+  tests := []struct {
+    value float64
+    label string
+  }{
+    {20, "dry"},
+    {49.9, "dry"},
+    {50, "very comfortable"},
+    {72, "very humid"},
+    {79.9, "oppressive"},
+    {85, "dangerously high"},
+    {math.NaN(), ""},
+  }
+  for _, test := range tests {
+    if got := dewpoint.Classify(test.value); got != test.label {
+      t.Errorf("Classify(%v) = %q; want %q", test.value, got, test.label)
+    }
+  }
+  if got := (ComfortScale{}).Classify(60); got != "" {
+    t.Errorf("a scale without bands: %q; want none", got)
+  }
+  // a scale whose last band has a limit still labels readings above it
+  capped := ComfortScale{"dewpoint", "C", []ComfortBand{{10, "fresh"}, {20, "muggy"}}}
+  if got := capped.Classify(25); got != "muggy" {
+    t.Errorf("above the last limit: %q; want muggy", got)
+  }
+}
+
+func TestComfortOf(t *testing.T) {
+  saved := conf
+  defer func() { conf = saved }()
+
+  tests := []struct {
+    comfort string
+    scales  map[string]ComfortScale
+    want    Comfort
+  }{
+    {"", nil, Comfort{"dewpoint", 59, "comfortable"}},
+    {"humidex", nil, Comfort{"humidex", 34, "some discomfort"}},
+    {"nosuch", nil, Comfort{"dewpoint", 59, "comfortable"}},
+    {"celsius", map[string]ComfortScale{
+      "celsius": {"dewpoint", "C", []ComfortBand{{10, "fresh"}, {Number(math.NaN()), "sticky"}}},
+    }, Comfort{"celsius", 15, "sticky"}},
+  }
+  for _, test := range tests {
+    conf.Comfort, conf.Comfort_scales = test.comfort, test.scales
+    got := ComfortOf(DegreesC(30), DegreesC(15))
+    if got.Scale != test.want.Scale || got.Label != test.want.Label || math.Round(float64(got.Value)) != float64(test.want.Value) {
+      t.Errorf("%q: %+v; want %+v", test.comfort, got, test.want)
+    }
+  }
+}
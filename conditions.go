@@ -7,7 +7,7 @@
 * Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
 * and Anthony Starks.
 *
-* Last Modified: Sat Aug 13 13:16:18 CDT 2016
+* Last Modified: Mon Oct 19 02:39:20 CDT 2026
 *
 * Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
 *
@@ -29,10 +29,7 @@
 package main
 
 import (
-	"fmt"
-	"regexp"
-	"strconv"
-	"strings"
+	"encoding/json"
 )
 
 type Conditions struct {
@@ -41,88 +38,79 @@ type Conditions struct {
 
 type Current struct {
 	Observation_time     string
-	Observation_location Location
+	Observation_epoch    string
+	Observation_location ObsLocation
 	Station_id           string
+	Local_epoch          string
 	Weather              string
 	Temperature_string   string
+	Temp_f               Number
+	Temp_c               Number
 	Relative_humidity    string
 	Wind_string          string
+	Wind_degrees         Number
+	Wind_mph             Number
+	Wind_gust_mph        Number
+	Wind_kph             Number
+	Wind_gust_kph        Number
 	Pressure_mb          string
 	Pressure_in          string
 	Pressure_trend       string
 	Dewpoint_string      string
+	Dewpoint_f           Number
+	Dewpoint_c           Number
 	Heat_index_string    string
 	Windchill_string     string
+	Feelslike_f          Number
+	Feelslike_c          Number
 	Visibility_mi        string
+	Visibility_km        string
+	Precip_1hr_in        Number
 	Precip_today_string  string
+	Precip_today_in      Number
+	Precip_today_metric  Number
+	Uv                   Number
+	Tendency             Tendency
 }
 
-type Location struct {
-	Full string
+type ObsLocation struct {
+	Full     string
+	Latitude Number
 }
 
-// printConditions prints the conditions to standard output
-func PrintConditions(obs *Conditions, degrees string) {
-	current := obs.Current_observation
-	fmt.Printf("Current conditions at %s (%s)\n%s\n",
-		current.Observation_location.Full, current.Station_id, current.Observation_time)
-	if degrees == "C" {
-		fmt.Println("   Temperature:", Convert(current.Temperature_string))
-	} else {
-		fmt.Println("   Temperature:", current.Temperature_string)
-	}
-	if current.Heat_index_string != "NA" {
-		fmt.Println("   Heat Index: ", current.Heat_index_string)
-	}
-	fmt.Println("   Sky Conditions:", current.Weather)
-	fmt.Println("   Wind:", current.Wind_string)
-	var pstring = ""
-	if degrees == "C" {
-		pstring = fmt.Sprintf("   Pressure: %s mb (%s in) and", current.Pressure_mb, current.Pressure_in)
-	} else {
-		pstring = fmt.Sprintf("   Pressure: %s in (%s mb) and", current.Pressure_in, current.Pressure_mb)
-	}
-	switch current.Pressure_trend {
-	case "+":
-		fmt.Println(pstring, "rising")
-	case "-":
-		fmt.Println(pstring, "falling")
-	case "0":
-		fmt.Println(pstring, "holding steady")
-	}
-
-	fmt.Println("   Relative humidity:", current.Relative_humidity)
+func (c *Current) UnmarshalJSON(b []byte) error {
+	type current Current // without this method
+	missingNumbers(c)
+	return json.Unmarshal(b, (*current)(c))
+}
 
-	if degrees == "C" {
-		fmt.Print("   Dewpoint: ", Convert(current.Dewpoint_string))
-	} else {
-		fmt.Print("   Dewpoint: ", current.Dewpoint_string)
-	}
+const conditionsTemplate = `{{with .Current_observation -}}
+{{heading (printf (T "Current conditions at %s (%s)") .Observation_location.Full .Station_id)}}
+{{.Observation_time}}
+   {{T "Temperature"}}: {{if celsius}}{{temp (convert .Temperature_string)}}{{else}}{{temp .Temperature_string}}{{end}}
+{{if ne .Heat_index "NA"}}   {{T "Heat Index"}}:  {{temp .Heat_index}}
+{{end}}   {{T "Sky Conditions"}}: {{sky .Weather}}
+   {{T "Wind"}}: {{.Wind_string}}
+{{with trend .Pressure_trend}}{{$c := $.Current_observation}}   {{T "Pressure"}}: {{if celsius}}{{$c.Pressure_mb}} mb ({{$c.Pressure_in}} in){{else}}{{$c.Pressure_in}} in ({{$c.Pressure_mb}} mb){{end}} {{if eq . "steady"}}{{T "and holding steady"}}{{else}}{{T (printf "and %s" .)}}{{end}}
+{{end}}{{with .Tendency}}{{if .Change.Valid}}   {{T "Pressure tendency"}}: {{if celsius}}{{printf "%+.1f" (num .Change)}} mb{{else}}{{printf "%+.2f" (mbtoin .Change)}} in ({{printf "%+.1f" (num .Change)}} mb){{end}} {{T "in 3 hours"}}, {{T .Description}} (WMO {{.Code}})
+{{if .Rapid}}   {{alert (T "Pressure falling rapidly: storm possible")}}
+{{end}}{{if zambretti}}   {{T "Zambretti forecast"}}: {{T .Zambretti}}
+{{end}}{{end}}{{end}}   {{T "Relative humidity"}}: {{.Relative_humidity}}
+   {{T "Dewpoint"}}: {{if celsius}}{{convert .Dewpoint_string}}{{else}}{{.Dewpoint_string}}{{end}} ({{T .Comfort.Label}})
+{{if ne .Windchill "NA"}}   {{T "Windchill"}}:  {{temp .Windchill}}
+{{end}}   {{T "Visibility"}}: {{.Visibility_mi}} {{T "miles"}}
+{{if not (matches "0.0" .Precip_today_string)}}   {{T "Precipitation today"}}:  {{precip .Precip_today_string}}
+{{end}}{{if derived}}{{with .Derived}}   {{T "Derived"}}:
+{{if .Heat_index_f.Valid}}      {{T "Heat Index"}}: {{temp (temps .Heat_index_f .Heat_index_c)}}
+{{end}}{{if .Windchill_f.Valid}}      {{T "Windchill"}}: {{temp (temps .Windchill_f .Windchill_c)}}
+{{end}}{{if .Apparent_f.Valid}}      {{T "Apparent temperature"}}: {{temp (temps .Apparent_f .Apparent_c)}}
+{{end}}{{if .Wetbulb_f.Valid}}      {{T "Wet-bulb temperature"}}: {{temp (temps .Wetbulb_f .Wetbulb_c)}}
+{{end}}{{if .Wbgt_f.Valid}}      {{T "WBGT (shade)"}}: {{temp (temps .Wbgt_f .Wbgt_c)}}
+{{end}}{{if .Absolute_humidity.Valid}}      {{T "Absolute humidity"}}: {{fixed 1 .Absolute_humidity}} g/m³
+{{end}}{{if .Cloud_base_m.Valid}}      {{T "Cloud base"}}: {{if celsius}}{{fixed 0 .Cloud_base_m}} m ({{fixed 0 .Cloud_base_ft}} ft){{else}}{{fixed 0 .Cloud_base_ft}} ft ({{fixed 0 .Cloud_base_m}} m){{end}}
+{{end}}{{end}}{{end}}{{end}}`
 
-	dp_components := strings.Split(current.Dewpoint_string, " ")
-	dp, _ := strconv.Atoi(dp_components[0])
-	if dp < 50 {
-		fmt.Println(" (dry)")
-	} else if dp >= 50 && dp <= 54 {
-		fmt.Println(" (very comfortable)")
-	} else if dp >= 55 && dp <= 59 {
-		fmt.Println(" (comfortable)")
-	} else if dp >= 60 && dp <= 64 {
-		fmt.Println(" (okay for most)")
-	} else if dp >= 65 && dp <= 69 {
-		fmt.Println(" (somewhat uncomfortable)")
-	} else if dp >= 70 && dp <= 74 {
-		fmt.Println(" (very humid)")
-	} else if dp >= 75 && dp <= 80 {
-		fmt.Println(" (oppressive)")
-	} else if dp >= 80 {
-		fmt.Println(" (dangerously high)")
-	}
-	if current.Windchill_string != "NA" {
-		fmt.Println("   Windchill: ", current.Windchill_string)
-	}
-	fmt.Printf("   Visibility: %s miles\n", current.Visibility_mi)
-	if m, _ := regexp.MatchString("0.0", current.Precip_today_string); !m {
-		fmt.Println("   Precipitation today: ", current.Precip_today_string)
-	}
+// printConditions prints the conditions to standard output
+func PrintConditions(obs *Conditions, degrees string) {
+	Render("conditions", obs.Current_observation.Station_id, degrees, obs)
 }
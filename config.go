@@ -0,0 +1,598 @@
+/*
+* config.go
+*
+* This file is part of wu.  It contains functions for finding,
+* reading and checking the configuration file, and the "wu config"
+* command.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 01:54:14 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bufio"
+  "bytes"
+  "encoding/json"
+  "errors"
+  "flag"
+  "fmt"
+  "io/ioutil"
+  "os"
+  "path/filepath"
+  "regexp"
+  "sort"
+  "strings"
+)
+
+// configSource is the file the configuration was read from, and
+// configEnv the environment variables that override it
+var (
+  configSource string
+  configEnv    []string
+)
+
+// DefaultConfigPath returns where a new configuration file goes:
+// $WU_CONFIG, else $XDG_CONFIG_HOME/wu/config (XDG_CONFIG_HOME
+// defaults to $HOME/.config)
+func DefaultConfigPath() string {
+  if path := os.Getenv("WU_CONFIG"); path != "" {
+    return path
+  }
+  dir := os.Getenv("XDG_CONFIG_HOME")
+  if dir == "" {
+    dir = filepath.Join(os.Getenv("HOME"), ".config")
+  }
+  return filepath.Join(dir, "wu", "config")
+}
+
+// ConfigPath returns the configuration file to read: $WU_CONFIG,
+// $XDG_CONFIG_HOME/wu/config or $HOME/.condrc, whichever is found
+// first, or "" if there is none
+func ConfigPath() (string, error) {
+  if path := os.Getenv("WU_CONFIG"); path != "" {
+    if _, err := os.Stat(path); err != nil {
+      return "", fmt.Errorf("WU_CONFIG names %s, which can't be read", path)
+    }
+    return path, nil
+  }
+  for _, path := range []string{DefaultConfigPath(), filepath.Join(os.Getenv("HOME"), ".condrc")} {
+    if _, err := os.Stat(path); err == nil {
+      return path, nil
+    }
+  }
+  return "", nil
+}
+
+// LoadConfig reads a configuration file into c.  The file is JSON,
+// except that lines may end in // comments.
+func LoadConfig(path string, c *Config) error {
+  b, err := ioutil.ReadFile(path)
+  if err != nil {
+    return err
+  }
+  b = stripComments(b)
+  if err := json.Unmarshal(b, c); err != nil {
+    return configError(path, b, err)
+  }
+  return nil
+}
+
+// ApplyEnv overrides the configuration with $WU_KEY, $WU_STATION
+// and $WU_UNITS
+func ApplyEnv(c *Config) {
+  configEnv = nil
+  for _, v := range []struct {
+    name  string
+    field *string
+  }{
+    {"WU_KEY", &c.Key},
+    {"WU_STATION", &c.Station},
+    {"WU_UNITS", &c.Degrees},
+  } {
+    if value := os.Getenv(v.name); value != "" {
+      if v.name == "WU_UNITS" {
+        value = units(value)
+      }
+      *v.field = value
+      configEnv = append(configEnv, v.name)
+    }
+  }
+}
+
+// units accepts "metric" and "imperial" (or "us") as well as "C"
+// and "F"
+func units(value string) string {
+  switch strings.ToLower(value) {
+  case "metric", "c":
+    return "C"
+  case "imperial", "us", "f":
+    return "F"
+  }
+  return value
+}
+
+// stripComments blanks out // comments (outside of strings), leaving
+// the line and column of everything else unchanged for error messages
+func stripComments(b []byte) []byte {
+  out := make([]byte, len(b))
+  copy(out, b)
+  inString, escaped := false, false
+  for i := 0; i < len(out); i++ {
+    c := out[i]
+    switch {
+    case inString:
+      if escaped {
+        escaped = false
+      } else if c == '\\' {
+        escaped = true
+      } else if c == '"' {
+        inString = false
+      }
+    case c == '"':
+      inString = true
+    case c == '/' && i+1 < len(out) && out[i+1] == '/':
+      for ; i < len(out) && out[i] != '\n'; i++ {
+        out[i] = ' '
+      }
+    }
+  }
+  return out
+}
+
+// configError explains a JSON error in terms of the line and column
+// of the configuration file where it occurred
+func configError(path string, b []byte, err error) error {
+  var offset int64
+  var message string
+  switch e := err.(type) {
+  case *json.SyntaxError:
+    offset, message = e.Offset, e.Error()
+    if offset > 0 && bytes.HasSuffix(bytes.TrimSpace(b[:offset-1]), []byte(",")) {
+      message += " (is there a comma after the last item?)"
+    }
+  case *json.UnmarshalTypeError:
+    offset = e.Offset
+    message = fmt.Sprintf("%q should be %s, not %s", e.Field, jsonKind(e.Type.Kind().String()), e.Value)
+  default:
+    return fmt.Errorf("%s: %v", path, err)
+  }
+  line, col := 1, 1
+  if offset > 0 {
+    offset--
+  }
+  for _, c := range b[:offset] {
+    if c == '\n' {
+      line, col = line+1, 1
+    } else {
+      col++
+    }
+  }
+  return fmt.Errorf("%s:%d:%d: %s", path, line, col, message)
+}
+
+// jsonKind describes a Go kind in JSON terms
+func jsonKind(kind string) string {
+  switch kind {
+  case "string":
+    return "a string"
+  case "slice", "array":
+    return "a list"
+  case "struct", "map":
+    return "an object"
+  case "bool":
+    return "true or false"
+  }
+  return "a number"
+}
+
+var keyPattern = regexp.MustCompile("^[0-9a-fA-F]{16}$")
+
+// validateField checks a single setting, returning a description
+// of what's wrong with it, or ""
+func validateField(name string, value string) string {
+  switch name {
+  case "key":
+    if value == "" {
+      return "no API key: set key, key_env, key_file or key_command (get a key from http://www.wunderground.com/weather/api/)"
+    }
+    if !keyPattern.MatchString(value) {
+      return "the API key should be 16 hexadecimal digits"
+    }
+  case "station":
+    if value != "" {
+      if _, err := NormalizeStation(value); err != nil {
+        return "station: " + err.Error()
+      }
+    }
+  case "degrees":
+    if value != "" && value != "F" && value != "C" {
+      return fmt.Sprintf("degrees (units) must be \"F\" or \"C\", not %q", value)
+    }
+  case "language":
+    if _, ok := catalogs[strings.ToLower(value)]; value != "" && !strings.EqualFold(value, "en") && !ok {
+      return fmt.Sprintf("language must be one of en, es, fr, de, not %q", value)
+    }
+  case "comfort":
+    if _, ok := lookupComfortScale(value); value != "" && !ok {
+      return fmt.Sprintf("comfort: no scale named %q", value)
+    }
+  }
+  return ""
+}
+
+// ValidateConfig returns a list of the problems with a configuration
+func ValidateConfig(c *Config) []string {
+  var problems []string
+  for _, field := range []struct{ name, value string }{
+    {"key", c.Key},
+    {"station", c.Station},
+    {"degrees", c.Degrees},
+    {"language", c.Language},
+  } {
+    if problem := validateField(field.name, field.value); problem != "" {
+      problems = append(problems, problem)
+    }
+  }
+  problems = append(problems, validateComfort(c)...)
+  for _, station := range c.Stations {
+    if problem := validateField("station", station); problem != "" {
+      problems = append(problems, "stations: "+problem)
+    }
+  }
+  for _, r := range c.Rules {
+    if _, err := CompileRule(r); err != nil {
+      problems = append(problems, err.Error())
+    }
+  }
+  for _, w := range c.Webhooks {
+    if !strings.HasPrefix(w.Url, "http://") && !strings.HasPrefix(w.Url, "https://") {
+      problems = append(problems, fmt.Sprintf("webhook URL %q should begin with http:// or https://", w.Url))
+    }
+    if !webhookFormats[w.Format] {
+      problems = append(problems, fmt.Sprintf("webhook format %q should be json, slack or discord", w.Format))
+    }
+  }
+  return problems
+}
+
+// configCommands are the subcommands of "wu config"
+var configCommands = map[string]func(args []string){
+  "init":     ConfigInit,
+  "show":     ConfigShow,
+  "validate": ConfigValidate,
+  "set":      ConfigSet,
+}
+
+// Configure runs "wu config init|show|validate|set"
+func Configure(args []string) {
+  if len(args) > 0 {
+    if command, ok := configCommands[args[0]]; ok {
+      command(args[1:])
+      return
+    }
+  }
+  fmt.Println("Usage: wu config init|show|validate|set")
+  fmt.Println("  init                 write a new configuration file, asking for the settings")
+  fmt.Println("  show                 print the configuration in effect")
+  fmt.Println("  validate             check the configuration file for mistakes")
+  fmt.Println("  set SETTING VALUE    change key, station, degrees (or units) or language")
+  os.Exit(2)
+}
+
+const configTemplate = `// Configuration for wu.  This is JSON, except that lines may end
+// in comments like this one.
+{
+  // Your Weather Underground API key
+  // (see http://www.wunderground.com/weather/api/)
+  "key": %q,
+
+  // The default station: "City, ST", a zip code, an airport code,
+  // "lat,long", or a personal weather station ("pws:KNYLONGE2")
+  "station": %q,
+
+  // "F" for Fahrenheit or "C" for Celsius
+  "degrees": %q
+
+  // Webhooks, rules, stations, templates, theme and language
+  // settings are described in the README.
+}
+`
+
+// ConfigInit writes a new configuration file, asking for any of the
+// key, station and degrees not given as switches
+func ConfigInit(args []string) {
+  flags := flag.NewFlagSet("config init", flag.ExitOnError)
+  key := flags.String("key", "", "Weather Underground API key")
+  station := flags.String("station", "", "Default weather station")
+  degrees := flags.String("degrees", "", "F or C")
+  force := flags.Bool("force", false, "Replace an existing configuration file")
+  flags.Parse(args)
+
+  path := DefaultConfigPath()
+  if _, err := os.Stat(path); err == nil && !*force {
+    fmt.Printf("%s already exists (use --force to replace it, or \"wu config set\" to change it)\n", path)
+    os.Exit(1)
+  }
+
+  in := bufio.NewReader(os.Stdin)
+  *key = ask(in, "Weather Underground API key", *key, "", "key")
+  *station = ask(in, "Default station", *station, defaultStation, "station")
+  *degrees = ask(in, "Degrees (F or C)", units(*degrees), "F", "degrees")
+
+  CheckError(os.MkdirAll(filepath.Dir(path), 0700))
+  CheckError(ioutil.WriteFile(path, []byte(fmt.Sprintf(configTemplate, *key, *station, *degrees)), 0600))
+  fmt.Println("Wrote " + path)
+}
+
+// ask prompts for a setting until it gets a valid answer.  A value
+// already given on the command line is checked but not asked for.
+func ask(in *bufio.Reader, prompt string, value string, def string, field string) string {
+  for {
+    if value == "" {
+      if def != "" {
+        fmt.Printf("%s [%s]: ", prompt, def)
+      } else {
+        fmt.Printf("%s: ", prompt)
+      }
+      line, err := in.ReadString('\n')
+      if value = strings.TrimSpace(line); value == "" {
+        value = def
+      }
+      if err != nil && value == "" {
+        fmt.Println()
+        os.Exit(1)
+      }
+    }
+    if field == "degrees" {
+      value = units(value)
+    }
+    problem := validateField(field, value)
+    if problem == "" {
+      return value
+    }
+    fmt.Println(problem)
+    value = ""
+  }
+}
+
+// ConfigShow prints the configuration in effect, noting where it
+// came from
+func ConfigShow(args []string) {
+  ReadConf()
+  if configSource != "" {
+    fmt.Println("Configuration file: " + configSource)
+  } else {
+    fmt.Println("Configuration file: none")
+  }
+  if len(configEnv) > 0 {
+    fmt.Println("Overridden by:      " + strings.Join(configEnv, ", "))
+  }
+  fmt.Println()
+  show := func(name string, value interface{}) {
+    fmt.Printf("%-10s %v\n", name+":", value)
+  }
+  switch {
+  case os.Getenv("WU_KEY") != "":
+    show("key", MaskKey(conf.Key)+" (from $WU_KEY)")
+  case conf.Key_env != "":
+    show("key", MaskKey(conf.Key)+" (from $"+conf.Key_env+")")
+  case conf.Key_file != "":
+    show("key", MaskKey(conf.Key)+" (from "+conf.Key_file+")")
+  case conf.Key_command != "":
+    show("key", MaskKey(conf.Key)+" (from \""+conf.Key_command+"\")")
+  default:
+    show("key", MaskKey(conf.Key))
+  }
+  show("station", conf.Station)
+  show("degrees", conf.Degrees)
+  show("language", Language())
+  name, _ := comfortScale()
+  show("comfort", name)
+  if len(conf.Stations) > 0 {
+    show("stations", strings.Join(conf.Stations, "; "))
+  }
+  for _, w := range conf.Webhooks {
+    show("webhook", w.Url)
+  }
+  for _, r := range conf.Rules {
+    show("rule", r.Name+": "+r.When)
+  }
+  var names []string
+  for name := range conf.Templates {
+    names = append(names, name)
+  }
+  sort.Strings(names)
+  for _, name := range names {
+    show("template", name+" = "+conf.Templates[name])
+  }
+}
+
+// ConfigValidate checks the configuration and reports any problems
+func ConfigValidate(args []string) {
+  path, err := ConfigPath()
+  CheckError(err)
+  if path == "" {
+    fmt.Println("There is no configuration file; \"wu config init\" will create one.")
+    os.Exit(1)
+  }
+  var c Config
+  if err := LoadConfig(path, &c); err != nil {
+    fmt.Println(err)
+    os.Exit(1)
+  }
+  ApplyEnv(&c)
+  var problems []string
+  if err := ResolveKey(&c); err != nil {
+    problems = append(problems, err.Error())
+  }
+  if info, err := os.Stat(path); err == nil && c.Key_env == "" && c.Key_file == "" && c.Key_command == "" && info.Mode().Perm()&0077 != 0 {
+    problems = append(problems, "the file holds your API key and can be read by other users (chmod 600 "+path+")")
+  }
+  problems = append(problems, ValidateConfig(&c)...)
+  for _, problem := range problems {
+    fmt.Printf("%s: %s\n", path, problem)
+  }
+  if len(problems) > 0 {
+    os.Exit(1)
+  }
+  fmt.Println(path + ": OK")
+}
+
+// ConfigSet changes a single setting in the configuration file,
+// leaving the rest of it (and its comments) alone
+func ConfigSet(args []string) {
+  if len(args) != 2 {
+    fmt.Println("Usage: wu config set key|station|degrees|units|language|comfort VALUE")
+    os.Exit(2)
+  }
+  name, value := strings.ToLower(args[0]), args[1]
+  if name == "units" {
+    name = "degrees"
+  }
+  if name == "degrees" {
+    value = units(value)
+  }
+  switch name {
+  case "key", "station", "degrees", "language", "comfort":
+  default:
+    fmt.Printf("Unknown setting %q: use key, station, degrees (or units), language or comfort\n", name)
+    os.Exit(2)
+  }
+  if problem := validateField(name, value); problem != "" {
+    fmt.Println(problem)
+    os.Exit(1)
+  }
+  if name == "key" && setKeyElsewhere(value) {
+    return
+  }
+
+  path, err := SaveSetting(name, value)
+  CheckError(err)
+  fmt.Printf("%s: %s set to %q\n", path, name, value)
+}
+
+// setKeyElsewhere stores a new API key in the key_file, if the
+// configuration keeps the key in one, and reports whether it did.
+// A key that comes from key_env or key_command can't be set from
+// here, and writing it into .condrc would only leave a plaintext
+// copy that is never used.
+func setKeyElsewhere(key string) bool {
+  path, err := ConfigPath()
+  CheckError(err)
+  if path == "" {
+    return false
+  }
+  var c Config
+  CheckError(LoadConfig(path, &c))
+  switch {
+  case c.Key_env != "":
+    fmt.Fprintf(os.Stderr, "%s takes the key from $%s (key_env): set it there\n", path, c.Key_env)
+    os.Exit(1)
+  case c.Key_file != "":
+    keyPath := ExpandHome(c.Key_file)
+    CheckError(ioutil.WriteFile(keyPath, []byte(key+"\n"), 0600))
+    CheckError(os.Chmod(keyPath, 0600))
+    fmt.Printf("%s: key set (key_file)\n", keyPath)
+    return true
+  case c.Key_command != "":
+    fmt.Fprintf(os.Stderr, "%s takes the key from key_command: store it where the command finds it\n", path)
+    os.Exit(1)
+  }
+  return false
+}
+
+// SaveSetting changes a setting in the configuration file, creating
+// the file if there isn't one, and returns the file's path
+func SaveSetting(name string, value string) (string, error) {
+  path, err := ConfigPath()
+  if err != nil {
+    return "", err
+  }
+  if path == "" {
+    path = DefaultConfigPath()
+    if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+      return "", err
+    }
+    if err := ioutil.WriteFile(path, []byte("{\n}\n"), 0600); err != nil {
+      return "", err
+    }
+  }
+  return path, setConfigValue(path, name, value)
+}
+
+// setConfigValue replaces the value of a setting in a configuration
+// file, or adds the setting if it isn't there.  The file is decoded
+// to find the setting among the top-level members, so that a field
+// of the same name in a rule or webhook is left alone; only the
+// setting's value is re-encoded, keeping the rest of the file (and
+// its comments) as it was.
+func setConfigValue(path string, name string, value string) error {
+  b, err := ioutil.ReadFile(path)
+  if err != nil {
+    return err
+  }
+  var c Config
+  if err := json.Unmarshal(stripComments(b), &c); err != nil {
+    return configError(path, stripComments(b), err)
+  }
+  quoted, _ := json.Marshal(value)
+  if start, end, ok := memberValue(stripComments(b), name); ok {
+    b = append(b[:start:start], append(quoted, b[end:]...)...)
+  } else {
+    brace := bytes.IndexByte(stripComments(b), '{')
+    if brace < 0 {
+      return errors.New(path + ": the configuration should be a JSON object ({ ... })")
+    }
+    entry := fmt.Sprintf("\n  %q: %s", name, quoted)
+    if len(bytes.TrimSpace(stripComments(b)[brace+1:])) > 1 {
+      entry += ","
+    }
+    b = append(b[:brace+1:brace+1], append([]byte(entry), b[brace+1:]...)...)
+  }
+  return ioutil.WriteFile(path, b, 0600)
+}
+
+// memberValue finds a member of the top-level JSON object by name,
+// ignoring case as encoding/json does (and, like it, taking the last
+// of any duplicates), and returns the offsets of its value
+func memberValue(b []byte, name string) (start int, end int, ok bool) {
+  dec := json.NewDecoder(bytes.NewReader(b))
+  if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+    return 0, 0, false
+  }
+  for dec.More() {
+    t, err := dec.Token()
+    if err != nil {
+      return start, end, ok
+    }
+    var raw json.RawMessage
+    if err := dec.Decode(&raw); err != nil {
+      return start, end, ok
+    }
+    if key, _ := t.(string); strings.EqualFold(key, name) {
+      end = int(dec.InputOffset())
+      start, ok = end-len(raw), true
+    }
+  }
+  return start, end, ok
+}
@@ -0,0 +1,279 @@
+/*
+* derived.go
+*
+* This file is part of wu.  It contains the measures wu works out
+* for itself from the temperature, dewpoint, humidity and wind (the
+* --derived switch).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 02:30:21 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "encoding/json"
+  "math"
+)
+
+// Derived holds the measures wu works out from the current
+// conditions.  Those that don't apply (the heat index in cool
+// weather, for instance) or can't be worked out are missing.
+type Derived struct {
+  Heat_index_f      Number
+  Heat_index_c      Number
+  Windchill_f       Number
+  Windchill_c       Number
+  Apparent_f        Number
+  Apparent_c        Number
+  Wetbulb_f         Number
+  Wetbulb_c         Number
+  Wbgt_f            Number
+  Wbgt_c            Number
+  Absolute_humidity Number // g/m³
+  Cloud_base_ft     Number
+  Cloud_base_m      Number
+}
+
+// Derived works out the derived measures for the current conditions
+func (c Current) Derived() Derived {
+  t, td, rh, wind := c.readings()
+  d := Derived{}
+
+  tf := ctof(t)
+  if tf >= 80 {
+    d.Heat_index_f, d.Heat_index_c = fahrenheit(HeatIndex(tf, rh))
+  } else {
+    d.Heat_index_f, d.Heat_index_c = fahrenheit(math.NaN())
+  }
+  if mph := wind / 1.609344; tf <= 50 && mph >= 3 {
+    d.Windchill_f, d.Windchill_c = fahrenheit(WindChill(tf, mph))
+  } else {
+    d.Windchill_f, d.Windchill_c = fahrenheit(math.NaN())
+  }
+  d.Apparent_f, d.Apparent_c = celsius(ApparentTemp(t, rh, wind/3.6))
+  d.Wetbulb_f, d.Wetbulb_c = celsius(WetBulb(t, rh))
+  d.Wbgt_f, d.Wbgt_c = celsius(WBGT(t, td))
+  d.Absolute_humidity = Number(AbsoluteHumidity(t, td))
+  base := CloudBase(t, td)
+  d.Cloud_base_m, d.Cloud_base_ft = Number(base), Number(base/0.3048)
+  return d
+}
+
+// readings returns the temperature and dewpoint (°C), relative
+// humidity (%) and wind speed (km/h), falling back on the report's
+// strings, or working out the dewpoint or humidity from the other,
+// when the numbers are missing
+func (c Current) readings() (t, td, rh, wind float64) {
+  t = firstOf(float64(c.Temp_c), ftoc(float64(c.Temp_f)),
+    float64(ParseTemperature(c.Temperature_string)))
+  td = firstOf(float64(c.Dewpoint_c), ftoc(float64(c.Dewpoint_f)),
+    float64(ParseTemperature(c.Dewpoint_string)))
+  rh = float64(ParseNumber(c.Relative_humidity))
+  wind = firstOf(float64(c.Wind_kph), float64(c.Wind_mph)*1.609344)
+
+  if math.IsNaN(rh) {
+    rh = 100 * VapourPressure(td) / VapourPressure(t)
+  }
+  if math.IsNaN(td) {
+    td = DewpointOf(t, rh)
+  }
+  return t, td, rh, wind
+}
+
+// The methods below are what the reports show in place of the API's
+// heat index, wind chill and "feels like" temperature: the API's own
+// when it gives them, else wu's.  They are worked out when a report
+// asks for them, leaving the API's fields as it sent them.
+
+// Heat_index returns the heat index as the API writes it ("95 F
+// (35 C)"), or "NA"
+func (c Current) Heat_index() string {
+  if c.Heat_index_string != "" && c.Heat_index_string != "NA" {
+    return c.Heat_index_string
+  }
+  d := c.Derived()
+  return tempString(d.Heat_index_f, d.Heat_index_c)
+}
+
+// Windchill returns the wind chill as the API writes it, or "NA"
+func (c Current) Windchill() string {
+  if c.Windchill_string != "" && c.Windchill_string != "NA" {
+    return c.Windchill_string
+  }
+  d := c.Derived()
+  return tempString(d.Windchill_f, d.Windchill_c)
+}
+
+// Feels_like_c returns the "feels like" temperature (°C): the heat
+// index or wind chill if either applies, else the temperature
+func (c Current) Feels_like_c() Number {
+  if c.Feelslike_c.Valid() {
+    return c.Feelslike_c
+  }
+  if c.Feelslike_f.Valid() {
+    return Number(ftoc(float64(c.Feelslike_f)))
+  }
+  d := c.Derived()
+  t, _, _, _ := c.readings()
+  return Number(firstOf(float64(d.Heat_index_c), float64(d.Windchill_c), t))
+}
+
+// Feels_like_f returns the "feels like" temperature (°F)
+func (c Current) Feels_like_f() Number {
+  if c.Feelslike_f.Valid() {
+    return c.Feelslike_f
+  }
+  return Number(ctof(float64(c.Feels_like_c())))
+}
+
+// Comfort classifies the air on the configured comfort scale
+func (c Current) Comfort() Comfort {
+  t, td, _, _ := c.readings()
+  return ComfortOf(Temperature(t), Temperature(td))
+}
+
+// APICurrent is Current as the API sends it, without its methods
+type APICurrent Current
+
+// currentExport is the current conditions as they're exported: the
+// API's fields, with the heat index, wind chill and "feels like"
+// temperature the reports show, and the comfort of the air
+type currentExport struct {
+  APICurrent
+  Comfort Comfort
+}
+
+// exported returns the current conditions as they're exported, for
+// JSON and for the CSV writer
+func (c Current) exported() interface{} {
+  e := currentExport{APICurrent: APICurrent(c), Comfort: c.Comfort()}
+  e.Heat_index_string, e.Windchill_string = c.Heat_index(), c.Windchill()
+  e.Feelslike_f, e.Feelslike_c = c.Feels_like_f(), c.Feels_like_c()
+  return e
+}
+
+func (c Current) MarshalJSON() ([]byte, error) {
+  return json.Marshal(c.exported())
+}
+
+// tempString formats a temperature as the API does ("95 F (35 C)"),
+// or "NA" if it is missing
+func tempString(f Number, c Number) string {
+  if !f.Valid() {
+    return "NA"
+  }
+  return formatNumber(f, 0) + " F (" + formatNumber(c, 0) + " C)"
+}
+
+// HeatIndex returns the NWS heat index (°F) for a temperature (°F)
+// and relative humidity (%): the Rothfusz regression, with its
+// adjustments for low and high humidity, or Steadman's simpler
+// formula where that gives less than 80°F.
+func HeatIndex(t float64, rh float64) float64 {
+  hi := 0.5 * (t + 61 + (t-68)*1.2 + rh*0.094)
+  if (hi+t)/2 < 80 {
+    return hi
+  }
+  hi = -42.379 + 2.04901523*t + 10.14333127*rh - 0.22475541*t*rh -
+    0.00683783*t*t - 0.05481717*rh*rh + 0.00122874*t*t*rh +
+    0.00085282*t*rh*rh - 0.00000199*t*t*rh*rh
+  if rh < 13 && t >= 80 && t <= 112 {
+    hi -= (13 - rh) / 4 * math.Sqrt((17-math.Abs(t-95))/17)
+  } else if rh > 85 && t >= 80 && t <= 87 {
+    hi += (rh - 85) / 10 * (87 - t) / 5
+  }
+  return hi
+}
+
+// WindChill returns the NWS (2001) wind chill (°F) for a temperature
+// (°F) and wind speed (mph).  It is defined for temperatures of 50°F
+// and below and winds of at least 3 mph.
+func WindChill(t float64, mph float64) float64 {
+  v := math.Pow(mph, 0.16)
+  return 35.74 + 0.6215*t - 35.75*v + 0.4275*t*v
+}
+
+// ApparentTemp returns Steadman's apparent temperature (°C) in the
+// shade, as used by the Australian Bureau of Meteorology, for a
+// temperature (°C), relative humidity (%) and wind speed (m/s)
+func ApparentTemp(t float64, rh float64, wind float64) float64 {
+  e := rh / 100 * VapourPressure(t)
+  return t + 0.33*e - 0.70*wind - 4.00
+}
+
+// WetBulb estimates the wet-bulb temperature (°C) from the
+// temperature (°C) and relative humidity (%), after Stull (2011)
+func WetBulb(t float64, rh float64) float64 {
+  return t*math.Atan(0.151977*math.Sqrt(rh+8.313659)) +
+    math.Atan(t+rh) - math.Atan(rh-1.676331) +
+    0.00391838*math.Pow(rh, 1.5)*math.Atan(0.023101*rh) - 4.686035
+}
+
+// WBGT estimates the wet-bulb globe temperature (°C) in the shade
+// from the temperature and dewpoint (°C), by the Bureau of
+// Meteorology's approximation.  It does not allow for sunshine.
+func WBGT(t float64, td float64) float64 {
+  return 0.567*t + 0.393*VapourPressure(td) + 3.94
+}
+
+// AbsoluteHumidity returns the water vapour content of the air
+// (g/m³) for a temperature and dewpoint (°C)
+func AbsoluteHumidity(t float64, td float64) float64 {
+  return 216.7 * VapourPressure(td) / (t + 273.15)
+}
+
+// CloudBase estimates the height (m) of the base of cumulus cloud
+// from the spread between the temperature and dewpoint (°C)
+func CloudBase(t float64, td float64) float64 {
+  return 125 * math.Max(t-td, 0)
+}
+
+// VapourPressure returns the saturation vapour pressure (hPa) at a
+// temperature (°C), by the Magnus formula; at the dewpoint, it is
+// the actual vapour pressure
+func VapourPressure(t float64) float64 {
+  return 6.112 * math.Exp(17.67*t/(t+243.5))
+}
+
+// DewpointOf returns the dewpoint (°C) for a temperature (°C) and
+// relative humidity (%)
+func DewpointOf(t float64, rh float64) float64 {
+  g := math.Log(rh/100) + 17.67*t/(t+243.5)
+  return 243.5 * g / (17.67 - g)
+}
+
+func ftoc(f float64) float64 { return (f - 32) * 5 / 9 }
+func ctof(c float64) float64 { return c*9/5 + 32 }
+
+// fahrenheit and celsius return a temperature in both scales
+func fahrenheit(f float64) (Number, Number) { return Number(f), Number(ftoc(f)) }
+func celsius(c float64) (Number, Number)    { return Number(ctof(c)), Number(c) }
+
+// firstOf returns the first of the values that isn't NaN
+func firstOf(values ...float64) float64 {
+  for _, v := range values {
+    if !math.IsNaN(v) {
+      return v
+    }
+  }
+  return math.NaN()
+}
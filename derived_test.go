@@ -0,0 +1,126 @@
+/*
+* derived_test.go
+*
+* This file is part of wu.  It contains tests for the measures wu
+* works out from the current conditions, and their export.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 02:30:21 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "encoding/csv"
+  "encoding/json"
+  "io"
+  "math"
+  "os"
+  "strings"
+  "testing"
+)
+
+// hotConditions is the current conditions on a hot, humid afternoon,
+// with the API leaving out the heat index and "feels like"
+const hotConditions = `{"current_observation":{"station_id":"KLNK","weather":"Clear",
+  "temperature_string":"90 F (32 C)","temp_f":90,"temp_c":32.2,"relative_humidity":"55%",
+  "dewpoint_string":"72 F (22 C)","dewpoint_f":72,"dewpoint_c":22.2222,"wind_mph":5,"wind_kph":8,
+  "heat_index_string":"NA","windchill_string":"NA"}}`
+
+// stdout returns what a function writes to standard output
+func stdout(t *testing.T, f func()) string {
+  r, w, err := os.Pipe()
+  if err != nil {
+    t.Fatal(err)
+  }
+  saved := os.Stdout
+  os.Stdout = w
+  defer func() { os.Stdout = saved }()
+  f()
+  w.Close()
+  b, _ := io.ReadAll(r)
+  return string(b)
+}
+
+func TestCurrentExport(t *testing.T) {
+  var cond Conditions
+  if err := json.Unmarshal([]byte(hotConditions), &cond); err != nil {
+    t.Fatal(err)
+  }
+  c := cond.Current_observation
+  if c.Heat_index_string != "NA" || c.Feelslike_f.Valid() {
+    t.Errorf("decoding filled in the heat index (%q) or feels like (%v)", c.Heat_index_string, c.Feelslike_f)
+  }
+
+  b, err := json.Marshal(&cond)
+  if err != nil {
+    t.Fatal(err)
+  }
+  var exported struct {
+    Current_observation struct {
+      Heat_index_string string
+      Feelslike_f       Number
+      Comfort           Comfort
+    }
+  }
+  if err := json.Unmarshal(b, &exported); err != nil {
+    t.Fatal(err)
+  }
+  e := exported.Current_observation
+  if e.Comfort.Scale != "dewpoint" || e.Comfort.Label != "very humid" || math.Abs(float64(e.Comfort.Value)-72) > 0.01 {
+    t.Errorf("JSON comfort %+v; want the dewpoint scale, 72, very humid", e.Comfort)
+  }
+  if e.Heat_index_string != c.Heat_index() || e.Heat_index_string == "NA" {
+    t.Errorf("JSON heat index %q; want %q", e.Heat_index_string, c.Heat_index())
+  }
+  if !e.Feelslike_f.Valid() || !near(e.Feelslike_f, float64(c.Feels_like_f())) {
+    t.Errorf("JSON feels like %v; want %v", e.Feelslike_f, c.Feels_like_f())
+  }
+
+  out := stdout(t, func() {
+    if err := WriteCSV("conditions", &cond); err != nil {
+      t.Error(err)
+    }
+  })
+  rows, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+  if err != nil || len(rows) != 2 {
+    t.Fatalf("CSV %q: %v", out, err)
+  }
+  columns := map[string]string{}
+  for i, name := range rows[0] {
+    columns[name] = rows[1][i]
+  }
+  want := map[string]string{
+    "Current_observation.Station_id":        "KLNK",
+    "Current_observation.Comfort.Scale":     "dewpoint",
+    "Current_observation.Comfort.Label":     "very humid",
+    "Current_observation.Heat_index_string": c.Heat_index(),
+  }
+  for name, value := range want {
+    if got, ok := columns[name]; !ok || got != value {
+      t.Errorf("CSV %s = %q; want %q", name, got, value)
+    }
+  }
+  if bytes.Contains([]byte(out), []byte("APICurrent")) {
+    t.Errorf("CSV names the embedded fields: %s", rows[0])
+  }
+}
@@ -0,0 +1,157 @@
+/*
+* export.go
+*
+* This file is part of wu.  It contains functions for writing
+* reports as JSON or CSV (--format) instead of text.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 02:30:21 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "encoding/csv"
+  "encoding/json"
+  "fmt"
+  "math"
+  "os"
+  "reflect"
+  "strconv"
+  "strings"
+)
+
+// csvRows names, for reports with one row per day, station, alert,
+// etc., the list in the report data holding the rows.  Other reports
+// are written as a single row.
+var csvRows = map[string]string{
+  "alerts":      "Alerts",
+  "climate":     "Days",
+  "climateyear": "Months",
+  "forecast":    "Forecast.Txt_forecast.Forecastday",
+  "forecast10":  "Forecast.Simpleforecast.Forecastday",
+  "frost":       "Nights",
+  "history":     "History.Dailysummary",
+  "hourly":      "Hourly_forecast",
+  "lookup":      "Stations",
+  "tides":       "Tide.Tidesummary",
+}
+
+// Export writes the data for a report in the --format given
+func Export(report string, data interface{}) {
+  switch outputFormat {
+  case "json":
+    b, err := json.MarshalIndent(data, "", "  ")
+    CheckError(err)
+    fmt.Println(string(b))
+  case "csv":
+    CheckError(WriteCSV(report, data))
+  }
+}
+
+// WriteCSV writes the data for a report as CSV with a header row.
+// Numbers use the decimal separator of the user's language; where
+// that is a comma, fields are separated by semicolons.
+func WriteCSV(report string, data interface{}) error {
+  v := reflect.Indirect(reflect.ValueOf(data))
+  var rows []reflect.Value
+  if path, ok := csvRows[report]; ok {
+    for _, name := range strings.Split(path, ".") {
+      v = v.FieldByName(name)
+    }
+    for i := 0; i < v.Len(); i++ {
+      rows = append(rows, v.Index(i))
+    }
+  } else {
+    rows = append(rows, v)
+  }
+
+  w := csv.NewWriter(os.Stdout)
+  if DecimalSeparator() == "," {
+    w.Comma = ';'
+  }
+  for i, row := range rows {
+    var header, record []string
+    flatten(row, "", &header, &record)
+    if i == 0 {
+      w.Write(header)
+    }
+    w.Write(record)
+  }
+  w.Flush()
+  return w.Error()
+}
+
+// exporter is data that is exported as something else, such as the
+// current conditions with what wu works out from them
+type exporter interface {
+  exported() interface{}
+}
+
+// flatten appends the fields of a struct to a CSV header and record,
+// naming nested fields by their path (e.g. "High.Fahrenheit"), and
+// embedded ones as if they were the struct's own.  Lists within a
+// row are left out.
+func flatten(v reflect.Value, prefix string, header *[]string, record *[]string) {
+  if v.CanInterface() {
+    if e, ok := v.Interface().(exporter); ok {
+      v = reflect.ValueOf(e.exported())
+    }
+  }
+  t := v.Type()
+  for i := 0; i < t.NumField(); i++ {
+    f, name := v.Field(i), prefix+t.Field(i).Name
+    switch f.Kind() {
+    case reflect.Struct:
+      if t.Field(i).Anonymous {
+        flatten(f, prefix, header, record)
+      } else {
+        flatten(f, name+".", header, record)
+      }
+    case reflect.Slice, reflect.Map:
+      continue
+    default:
+      *header = append(*header, name)
+      *record = append(*record, csvValue(f))
+    }
+  }
+}
+
+// csvValue formats a field for CSV, localizing the decimal separator
+// of numbers (including the many the API sends as strings)
+func csvValue(f reflect.Value) string {
+  var s string
+  switch f.Kind() {
+  case reflect.Float32, reflect.Float64:
+    if math.IsNaN(f.Float()) {
+      return ""
+    }
+    s = strconv.FormatFloat(f.Float(), 'f', -1, 64)
+  case reflect.String:
+    s = f.String()
+    if _, err := strconv.ParseFloat(s, 64); err != nil {
+      return s
+    }
+  default:
+    s = fmt.Sprint(f.Interface())
+  }
+  return strings.Replace(s, ".", DecimalSeparator(), 1)
+}
@@ -7,7 +7,7 @@
 * Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
 * and Anthony Starks.
 *
-* Last Modified: Wed Dec 18 16:11:20 CST 2013
+* Last Modified: Mon Oct 19 00:35:04 CDT 2026
 *
 * Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
 *
@@ -29,7 +29,7 @@
 package main
 
 import (
-  "fmt"
+  "encoding/json"
 )
 
 type ForecastConditions struct {
@@ -37,7 +37,8 @@ type ForecastConditions struct {
 }
 
 type Forecast struct {
-  Txt_forecast Txt_forecast
+  Txt_forecast   Txt_forecast
+  Simpleforecast Simpleforecast
 }
 
 type Txt_forecast struct {
@@ -50,12 +51,73 @@ type Forecastday struct {
   Fcttext string
 }
 
+// Simpleforecast is the structured (one entry per day) counterpart
+// of Txt_forecast
+type Simpleforecast struct {
+  Forecastday []Forecastdetail
+}
+
+type Forecastdetail struct {
+  Date        ForecastDate
+  Period      int
+  High        ForecastTemp
+  Low         ForecastTemp
+  Conditions  string
+  Pop         Number
+  Qpf_allday  Precip
+  Snow_allday Snow
+  Maxwind     Wind
+  Avewind     Wind
+  Avehumidity Number
+  Maxhumidity Number
+  Minhumidity Number
+}
+
+func (f *Forecastdetail) UnmarshalJSON(b []byte) error {
+  type forecastdetail Forecastdetail // without this method
+  missingNumbers(f)
+  return json.Unmarshal(b, (*forecastdetail)(f))
+}
+
+type ForecastDate struct {
+  Epoch         string
+  Pretty        string
+  Day           int
+  Month         int
+  Year          int
+  Weekday       string
+  Weekday_short string
+}
+
+type ForecastTemp struct {
+  Fahrenheit Number
+  Celsius    Number
+}
+
+type Precip struct {
+  In Number
+  Mm Number
+}
+
+type Snow struct {
+  In Number
+  Cm Number
+}
+
+type Wind struct {
+  Mph     Number
+  Kph     Number
+  Dir     string
+  Degrees Number
+}
+
+const forecastTemplate = `{{with .Forecast.Txt_forecast -}}
+{{T "Forecast for"}} {{station}}
+{{T "Issued at"}} {{.Date}}
+{{range .Forecastday}}{{.Title}}: {{.Fcttext}}
+{{end}}{{end}}`
+
 // printForecast prints the forecast for a given station to standard out
 func PrintForecast(obs *ForecastConditions, stationId string) {
-  t := obs.Forecast.Txt_forecast
-  fmt.Printf("Forecast for %s\n", stationId)
-  fmt.Printf("Issued at %s\n", t.Date)
-  for _, f := range t.Forecastday {
-    fmt.Printf("%s: %s\n", f.Title, f.Fcttext)
-  }
+  Render("forecast", stationId, conf.Degrees, obs)
 }
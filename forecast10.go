@@ -7,7 +7,7 @@
 * Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
 * and Anthony Starks.
 *
-* Last Modified: Wed Dec 18 16:11:30 CST 2013
+* Last Modified: Mon Oct 19 01:50:59 CDT 2026
 *
 * Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
 *
@@ -32,13 +32,42 @@ import (
   "fmt"
 )
 
-// printForecast prints the forecast for a given station to standard out
-// The dat structure on which it depends is in forecast.go.
+// forecast10Template lays out the simpleforecast as a table, one row
+// per day; with --verbose, the text forecast follows
+const forecast10Template = `{{T "Forecast for"}} {{station}}
+{{with .Forecast.Simpleforecast.Forecastday -}}
+{{heading (printf "%-6s  %4s %4s  %-24s %4s %8s %7s  %-14s %4s" (T "Day") (T "High") (T "Low") (T "Conditions") (T "POP") (T "Precip") (T "Snow") (T "Wind") (T "RH"))}}
+{{range .}}{{printf "%-3s %2d" (T .Date.Weekday_short) .Date.Day}}  {{if celsius -}}
+{{temp (printf "%3s°" (fixed 0 .High.Celsius))}} {{temp (printf "%3s°" (fixed 0 .Low.Celsius))}}  {{pad 24 .Conditions}} {{printf "%3s%%" (fixed 0 .Pop)}} {{precip (printf "%5s mm" (fixed 0 .Qpf_allday.Mm))}} {{printf "%4s cm" (fixed 1 .Snow_allday.Cm)}}  {{pad 14 (printf "%s %s/%s km/h" .Maxwind.Dir (fixed 0 .Maxwind.Kph) (fixed 0 .Avewind.Kph))}}
+{{- else -}}
+{{temp (printf "%3s°" (fixed 0 .High.Fahrenheit))}} {{temp (printf "%3s°" (fixed 0 .Low.Fahrenheit))}}  {{pad 24 .Conditions}} {{printf "%3s%%" (fixed 0 .Pop)}} {{precip (printf "%5s in" (fixed 2 .Qpf_allday.In))}} {{printf "%4s in" (fixed 1 .Snow_allday.In)}}  {{pad 14 (printf "%s %s/%s mph" .Maxwind.Dir (fixed 0 .Maxwind.Mph) (fixed 0 .Avewind.Mph))}}
+{{- end}} {{printf "%3s%%" (fixed 0 .Avehumidity)}}
+{{end}}{{end}}{{if verbose}}{{with .Forecast.Txt_forecast}}
+{{range .Forecastday}}{{.Title}}: {{.Fcttext}}
+{{end}}{{end}}{{end}}`
+
+// printForecast10 prints the 10-day forecast for a given station to
+// standard out.  The data structure on which it depends is in
+// forecast.go.
 func PrintForecast10(obs *ForecastConditions, stationId string) {
-  t := obs.Forecast.Txt_forecast
-  fmt.Printf("Forecast for %s\n", stationId)
-  fmt.Printf("Issued at %s\n", t.Date)
-  for _, f := range t.Forecastday {
-    fmt.Printf("%s: %s\n", f.Title, f.Fcttext)
+  Render("forecast10", stationId, conf.Degrees, obs)
+  if charting() {
+    printChart(T("High and low temperatures"), forecast10Chart(obs.Forecast.Simpleforecast.Forecastday))
+  }
+}
+
+// forecast10Chart draws each day's range of temperatures
+func forecast10Chart(days []Forecastdetail) []string {
+  var lows, highs []float64
+  var labels []string
+  for _, d := range days {
+    if conf.Degrees == "C" {
+      lows, highs = append(lows, float64(d.Low.Celsius)), append(highs, float64(d.High.Celsius))
+    } else {
+      lows, highs = append(lows, float64(d.Low.Fahrenheit)), append(highs, float64(d.High.Fahrenheit))
+    }
+    labels = append(labels, fmt.Sprintf("%-3s %2d", T(d.Date.Weekday_short), d.Date.Day))
   }
+  _, width := chartSize()
+  return RangeChart(lows, highs, labels, width, "%.0f°")
 }
@@ -0,0 +1,301 @@
+/*
+* frost.go
+*
+* This file is part of wu.  It contains functions related to
+* "wu frost", which estimates the risk of frost for gardeners.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 02:06:11 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "flag"
+  "fmt"
+  "math"
+  "sort"
+  "strings"
+  "time"
+)
+
+// FrostReport is the risk of frost over the coming nights, with the
+// station's frost dates from its archived history
+type FrostReport struct {
+  Nights      []FrostNight
+  Week        Number // chance (%) of frost on any of the next seven nights
+  Last_spring FrostDates
+  First_fall  FrostDates
+}
+
+// FrostNight is the risk of frost and of a freeze on one night
+type FrostNight struct {
+  Date       string
+  Weekday    string
+  Low_f      Number
+  Low_c      Number
+  Dewpoint_f Number
+  Wind_mph   Number
+  Conditions string
+  Frost      Number // %
+  Freeze     Number // %
+}
+
+// FrostDates are the earliest, median and latest dates (MM-DD) of
+// frost over a number of years; with no frost in any of them, the
+// dates are empty
+type FrostDates struct {
+  Years    int
+  Earliest string
+  Median   string
+  Latest   string
+}
+
+// frostLow is the air temperature (°F) at which frost forms on
+// plants on a clear, calm night, the ground being colder than the
+// air a few feet up
+const frostLow = 36
+
+// freezeLow is the air temperature (°F) of a freeze
+const freezeLow = 32
+
+// FrostChances estimates the chances (%) of frost and a freeze on a
+// night from its forecast low (°F), the dewpoint (°F, or NaN), the
+// wind (mph), the forecast conditions and how many days ahead it is.
+// Clear skies and calm air let the ground cool below the low; cloud,
+// wind and moist air hold it up.  The forecast is taken to be less
+// certain the further ahead it is.
+func FrostChances(low float64, dewpoint float64, wind float64, conditions string, ahead int) (frost float64, freeze float64) {
+  ground := low
+  switch skyCover(conditions) {
+  case "clear":
+    ground -= 2
+  case "cloudy":
+    ground += 2
+  }
+  switch {
+  case wind < 5:
+    ground -= 2
+  case wind > 12:
+    ground += 2
+  }
+  if dewpoint > low {
+    ground += (dewpoint - low) / 2
+  }
+  spread := 3 + 0.5*float64(ahead)
+  return 100 * normal((frostLow-ground)/spread), 100 * normal((freezeLow-low)/spread)
+}
+
+// normal is the standard normal cumulative distribution
+func normal(x float64) float64 {
+  return 0.5 * (1 + math.Erf(x/math.Sqrt2))
+}
+
+// skyCover reads a forecast's conditions as "clear", "cloudy" or
+// "partly"
+func skyCover(conditions string) string {
+  c := strings.ToLower(conditions)
+  switch {
+  case strings.Contains(c, "partly") || strings.Contains(c, "mostly sunny") || strings.Contains(c, "scattered"):
+    return "partly"
+  case strings.Contains(c, "clear") || strings.Contains(c, "sunny"):
+    return "clear"
+  case strings.Contains(c, "cloud") || strings.Contains(c, "overcast") || strings.Contains(c, "rain") ||
+    strings.Contains(c, "snow") || strings.Contains(c, "fog") || strings.Contains(c, "storm"):
+    return "cloudy"
+  }
+  return "partly"
+}
+
+// forecastFrost returns the chance (%) of frost on a forecast day's
+// night, for the rules
+func forecastFrost(f *Forecastdetail) Number {
+  if !f.Low.Fahrenheit.Valid() {
+    return Number(math.NaN())
+  }
+  frost, _ := FrostChances(float64(f.Low.Fahrenheit), float64(forecastDewpoint(f)), float64(f.Avewind.Mph), f.Conditions, f.Period-1)
+  return Number(frost)
+}
+
+// FrostRisk works out the risk of frost over the nights of a
+// forecast.  Tonight's takes the current dewpoint into account; the
+// later nights', the dewpoint of the day's mean temperature at its
+// average humidity.
+func FrostRisk(current *Current, forecast *ForecastConditions, archive Archive) FrostReport {
+  var report FrostReport
+  latitude := Number(math.NaN())
+  if current != nil {
+    latitude = current.Observation_location.Latitude
+  }
+  none := 1.0
+  for i, day := range forecast.Forecast.Simpleforecast.Forecastday {
+    night := FrostNight{
+      Date:       fmt.Sprintf("%04d-%02d-%02d", day.Date.Year, day.Date.Month, day.Date.Day),
+      Weekday:    day.Date.Weekday_short,
+      Low_f:      day.Low.Fahrenheit,
+      Low_c:      day.Low.Celsius,
+      Dewpoint_f: Number(math.NaN()),
+      Wind_mph:   day.Avewind.Mph,
+      Conditions: day.Conditions,
+      Frost:      Number(math.NaN()),
+      Freeze:     Number(math.NaN()),
+    }
+    if i == 0 && current != nil {
+      _, td, _, _ := current.readings()
+      night.Dewpoint_f = Number(ctof(td))
+    } else {
+      night.Dewpoint_f = forecastDewpoint(&day)
+    }
+    if night.Low_f.Valid() {
+      frost, freeze := FrostChances(float64(night.Low_f), float64(night.Dewpoint_f), float64(night.Wind_mph), night.Conditions, i)
+      night.Frost, night.Freeze = Number(math.Round(frost)), Number(math.Round(freeze))
+      if i < 7 {
+        none *= 1 - frost/100
+      }
+    }
+    report.Nights = append(report.Nights, night)
+  }
+  report.Week = Number(math.Round(100 * (1 - none)))
+  report.Last_spring, report.First_fall = archive.FrostDates(latitude)
+  return report
+}
+
+// forecastDewpoint estimates the dewpoint (°F) of a forecast day from
+// its mean temperature and average humidity, or NaN if the forecast
+// lacks them
+func forecastDewpoint(f *Forecastdetail) Number {
+  if !f.High.Fahrenheit.Valid() || !f.Low.Fahrenheit.Valid() || !f.Avehumidity.Valid() || f.Avehumidity <= 0 {
+    return Number(math.NaN())
+  }
+  mean := ftoc(float64(f.High.Fahrenheit+f.Low.Fahrenheit) / 2)
+  return Number(ctof(DewpointOf(mean, math.Min(float64(f.Avehumidity), 100))))
+}
+
+// FrostDates works out the dates of the last frost of spring and the
+// first of autumn, as the last day before July and the first from
+// July on with a low of 32°F or below, for each year the archive
+// covers well enough (four days in five of the half-year).  South of
+// the equator (a negative latitude), where the frosts come in the
+// middle of the year, the halves are the other way round: spring's
+// last frost is from July on and autumn's first before July.
+func (a Archive) FrostDates(latitude Number) (spring FrostDates, fall FrostDates) {
+  south := latitude.Valid() && latitude < 0
+  type half struct {
+    days  int
+    frost []int // days of the year, counted in 2001
+  }
+  halves := map[string]*half{}
+  for key, day := range a {
+    date, err := time.Parse(archiveDate, key)
+    if err != nil {
+      continue
+    }
+    low := ParseNumber(day.Mintempi)
+    if !low.Valid() {
+      low = Number(ctof(float64(ParseNumber(day.Mintempm))))
+    }
+    name := fmt.Sprintf("%d-spring", date.Year())
+    if (date.Month() >= time.July) != south {
+      name = fmt.Sprintf("%d-fall", date.Year())
+    }
+    h := halves[name]
+    if h == nil {
+      h = &half{}
+      halves[name] = h
+    }
+    h.days++
+    if low.Valid() && low <= freezeLow {
+      h.frost = append(h.frost, time.Date(2001, date.Month(), date.Day(), 0, 0, 0, 0, time.UTC).YearDay())
+    }
+  }
+
+  var springs, falls []int
+  for name, h := range halves {
+    if h.days < 145 {
+      continue
+    }
+    if strings.HasSuffix(name, "spring") {
+      spring.Years++
+      if len(h.frost) > 0 {
+        sort.Ints(h.frost)
+        springs = append(springs, h.frost[len(h.frost)-1])
+      }
+    } else {
+      fall.Years++
+      if len(h.frost) > 0 {
+        sort.Ints(h.frost)
+        falls = append(falls, h.frost[0])
+      }
+    }
+  }
+  spring.Earliest, spring.Median, spring.Latest = dateRange(springs)
+  fall.Earliest, fall.Median, fall.Latest = dateRange(falls)
+  return spring, fall
+}
+
+// dateRange returns the earliest, median and latest of some days of
+// the year (in 2001) as MM-DD
+func dateRange(days []int) (string, string, string) {
+  if len(days) == 0 {
+    return "", "", ""
+  }
+  sort.Ints(days)
+  monthDay := func(yday int) string {
+    return time.Date(2001, 1, yday, 0, 0, 0, 0, time.UTC).Format("01-02")
+  }
+  return monthDay(days[0]), monthDay(days[len(days)/2]), monthDay(days[len(days)-1])
+}
+
+const frostTemplate = `{{heading (printf (T "Frost risk for %s") station)}}
+{{with .Nights}}{{with index . 0}}{{T "Tonight"}}: {{printf "%.0f%%" (num .Frost)}} {{T "chance of frost"}}, {{printf "%.0f%%" (num .Freeze)}} {{T "chance of a freeze"}}
+{{end}}
+{{heading (printf "%-6s  %4s  %-24s %6s %6s" (T "Night") (T "Low") (T "Conditions") (T "Frost") (T "Freeze"))}}
+{{range .}}{{printf "%-3s %s" (T .Weekday) (slice .Date 8)}}  {{if celsius}}{{temp (printf "%3s°" (fixed 0 .Low_c))}}{{else}}{{temp (printf "%3s°" (fixed 0 .Low_f))}}{{end}}  {{pad 24 .Conditions}} {{printf "%5.0f%%" (num .Frost)}} {{printf "%5.0f%%" (num .Freeze)}}
+{{end}}
+{{T "Chance of frost in the next week"}}: {{printf "%.0f%%" (num $.Week)}}
+{{end}}{{with .Last_spring}}{{if .Median}}{{T "Last spring frost"}}: {{monthday .Median}} ({{printf (T "median of %d years; earliest %s, latest %s") .Years (monthday .Earliest) (monthday .Latest)}})
+{{else if .Years}}{{T "Last spring frost"}}: {{printf (T "none in %d years") .Years}}
+{{else}}{{T "No archived history for frost dates: fill it with \"wu backfill --from YYYY-MM-DD\""}}
+{{end}}{{end}}{{with .First_fall}}{{if .Median}}{{T "First fall frost"}}: {{monthday .Median}} ({{printf (T "median of %d years; earliest %s, latest %s") .Years (monthday .Earliest) (monthday .Latest)}})
+{{else if .Years}}{{T "First fall frost"}}: {{printf (T "none in %d years") .Years}}
+{{end}}{{end}}`
+
+// Frost runs "wu frost", which reports the risk of frost tonight and
+// over the coming week
+func Frost(args []string) {
+  ReadConf()
+  flags := flag.NewFlagSet("frost", flag.ExitOnError)
+  stationFlag := flags.String("s", "", "Weather station (defaults to the one in .condrc)")
+  flags.StringVar(&outputFormat, "format", "text", "Output format: text, json, or csv")
+  flags.Parse(args)
+  checkFormat()
+  station := CommandStation(*stationFlag)
+
+  var cond Conditions
+  CheckError(Get("conditions", station, &cond))
+  NoteObservation(station, &cond.Current_observation)
+  var forecast ForecastConditions
+  CheckError(Get("forecast10day", station, &forecast))
+
+  report := FrostRisk(&cond.Current_observation, &forecast, LoadArchive(station))
+  Render("frost", station, conf.Degrees, &report)
+  CheckRules(station, nil, &forecast)
+}
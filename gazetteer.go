@@ -0,0 +1,308 @@
+/*
+* gazetteer.go
+*
+* This file is part of wu.  It contains the built-in gazetteer
+* of places and airports, used to find stations without the API.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 02:42:48 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bufio"
+  "bytes"
+  "compress/gzip"
+  _ "embed"
+  "fmt"
+  "math"
+  "sort"
+  "strconv"
+  "strings"
+  "sync"
+  "unicode"
+)
+
+// The gazetteer is gazetteer.tsv.gz, compressed into the program: a
+// line for each place or airport, in tab-separated fields.  The copy
+// kept here is a small selection, mostly of North American places
+// and airports; "go generate" rebuilds it from the GeoNames and
+// OurAirports data, if they have been downloaded (see
+// gazetteer_gen.go).
+//
+//go:generate go run gazetteer_gen.go
+//go:embed gazetteer.tsv.gz
+var gazetteerData []byte
+
+// Place is a populated place or airport in the gazetteer
+type Place struct {
+  Kind       string // "place" or "airport"
+  Name       string
+  Alternates []string // other names or spellings
+  City       string   // for airports, the city served
+  Admin      string   // state, province or region
+  Country    string   // ISO 3166 code
+  Lat        float64
+  Lon        float64
+  Elevation  float64 // meters
+  ICAO       string
+  IATA       string
+  Timezone   string
+  Population int
+}
+
+var (
+  gazetteer     []Place
+  gazetteerOnce sync.Once
+)
+
+// Gazetteer returns the places and airports in the gazetteer
+func Gazetteer() []Place {
+  gazetteerOnce.Do(func() {
+    r, err := gzip.NewReader(bytes.NewReader(gazetteerData))
+    CheckError(err)
+    scanner := bufio.NewScanner(r)
+    for scanner.Scan() {
+      line := scanner.Text()
+      if line == "" || line[0] == '#' {
+        continue
+      }
+      f := strings.Split(line, "\t")
+      if len(f) != 13 {
+        continue
+      }
+      p := Place{Kind: f[0], Name: f[1], City: f[3], Admin: f[4], Country: f[5],
+        ICAO: f[9], IATA: f[10], Timezone: f[11]}
+      if f[2] != "" {
+        p.Alternates = strings.Split(f[2], ",")
+      }
+      p.Lat, _ = strconv.ParseFloat(f[6], 64)
+      p.Lon, _ = strconv.ParseFloat(f[7], 64)
+      p.Elevation, _ = strconv.ParseFloat(f[8], 64)
+      p.Population, _ = strconv.Atoi(f[12])
+      gazetteer = append(gazetteer, p)
+    }
+    CheckError(scanner.Err())
+  })
+  return gazetteer
+}
+
+// foldName reduces a name to a form for comparison: lower case,
+// without accents or punctuation, and with "Saint" as "st"
+func foldName(name string) string {
+  var b strings.Builder
+  for _, r := range strings.ToLower(name) {
+    if folded, ok := accents[r]; ok {
+      b.WriteString(folded)
+    } else if unicode.IsLetter(r) || unicode.IsDigit(r) {
+      b.WriteRune(r)
+    } else if r == ' ' || r == '-' || r == '/' {
+      b.WriteRune(' ')
+    }
+  }
+  words := strings.Fields(b.String())
+  for i, w := range words {
+    if w == "saint" {
+      words[i] = "st"
+    }
+  }
+  return strings.Join(words, " ")
+}
+
+var accents = map[rune]string{
+  'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'æ': "ae",
+  'ç': "c", 'č': "c", 'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ě': "e",
+  'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ı': "i", 'ñ': "n", 'ň': "n",
+  'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'œ': "oe",
+  'ř': "r", 'š': "s", 'ş': "s", 'ß': "ss", 'ù': "u", 'ú': "u", 'û': "u",
+  'ü': "u", 'ů': "u", 'ý': "y", 'ÿ': "y", 'ž': "z", 'ð': "d", 'þ': "th",
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a string, b string) int {
+  s, t := []rune(a), []rune(b)
+  row := make([]int, len(t)+1)
+  for j := range row {
+    row[j] = j
+  }
+  for i := 1; i <= len(s); i++ {
+    prev := row[0]
+    row[0] = i
+    for j := 1; j <= len(t); j++ {
+      cost := 1
+      if s[i-1] == t[j-1] {
+        cost = 0
+      }
+      cur := row[j]
+      row[j] = min(row[j]+1, row[j-1]+1, prev+cost)
+      prev = cur
+    }
+  }
+  return row[len(t)]
+}
+
+// nameScore rates how well a query matches a name: 0 for the same
+// name, 1 when the name begins with the query, 1 + the number of
+// typos for a close misspelling, or -1 for no match
+func nameScore(query string, name string) int {
+  name = foldName(name)
+  switch {
+  case query == name:
+    return 0
+  case strings.HasPrefix(name, query+" "):
+    return 1
+  }
+  if d := editDistance(query, name); d <= len([]rune(query))/4 {
+    return 1 + d
+  }
+  return -1
+}
+
+// PlaceMatch is a place found by SearchPlaces
+type PlaceMatch struct {
+  Place Place
+  Score int // lower is better
+}
+
+// SearchPlaces finds the populated places whose names match name,
+// allowing for accents, abbreviations and small misspellings.  The
+// region and country (either may be "") narrow the search.  The
+// best matches come first, the most populous among equals.
+func SearchPlaces(name string, region string, country string) []PlaceMatch {
+  query := foldName(name)
+  var matches []PlaceMatch
+  for _, p := range Gazetteer() {
+    if p.Kind != "place" || (country != "" && p.Country != country) {
+      continue
+    }
+    if region != "" && !strings.EqualFold(p.Admin, region) && foldName(p.Admin) != foldName(region) {
+      continue
+    }
+    best := -1
+    for _, n := range append([]string{p.Name}, p.Alternates...) {
+      if s := nameScore(query, n); s >= 0 && (best < 0 || s < best) {
+        best = s
+      }
+    }
+    if best >= 0 {
+      matches = append(matches, PlaceMatch{p, best})
+    }
+  }
+  sort.SliceStable(matches, func(i, j int) bool {
+    if matches[i].Score != matches[j].Score {
+      return matches[i].Score < matches[j].Score
+    }
+    return matches[i].Place.Population > matches[j].Place.Population
+  })
+  return matches
+}
+
+// FindAirport returns the airport with an ICAO or IATA code
+func FindAirport(code string) (Place, bool) {
+  code = strings.ToUpper(code)
+  for _, p := range Gazetteer() {
+    if p.Kind == "airport" && (p.ICAO == code || p.IATA == code) {
+      return p, true
+    }
+  }
+  return Place{}, false
+}
+
+// Geocode finds the coordinates of a location in the gazetteer.  A
+// place name that matches equally well in different places is an
+// error, unless one is much larger than the rest.
+func Geocode(loc Location) (Place, error) {
+  switch loc.Kind {
+  case CoordsLocation, GeohashLocation:
+    return Place{Kind: "place", Name: fmt.Sprintf("%.4f,%.4f", loc.Lat, loc.Lon), Lat: loc.Lat, Lon: loc.Lon}, nil
+  case ICAOLocation, IATALocation:
+    if p, ok := FindAirport(loc.Code); ok {
+      return p, nil
+    }
+    return Place{}, fmt.Errorf("airport %s isn't in the gazetteer", loc.Code)
+  case PlaceLocation:
+    matches := SearchPlaces(loc.Name, loc.Region, loc.Country)
+    if len(matches) == 0 {
+      return Place{}, fmt.Errorf("%s isn't in the gazetteer", loc.Name)
+    }
+    best := matches[0]
+    if len(matches) > 1 && matches[1].Score == best.Score && matches[1].Place.Population*10 > best.Place.Population {
+      var names []string
+      for _, m := range matches {
+        if m.Score == best.Score {
+          names = append(names, m.Place.Name+", "+m.Place.Admin)
+        }
+      }
+      return Place{}, fmt.Errorf("%q could be %s", loc.Name, strings.Join(names, "; "))
+    }
+    return best.Place, nil
+  }
+  return Place{}, fmt.Errorf("the gazetteer can't look up %s locations", loc.Kind)
+}
+
+// LocateStation parses a location like ParseLocation, but also
+// accepts a bare place name that the gazetteer knows only one of,
+// giving its coordinates
+func LocateStation(s string) (Location, error) {
+  loc, err := ParseLocation(s)
+  if err != nil && namePattern.MatchString(strings.TrimSpace(s)) {
+    if p, gerr := Geocode(Location{Kind: PlaceLocation, Name: strings.TrimSpace(s)}); gerr == nil {
+      return Location{Kind: CoordsLocation, Lat: p.Lat, Lon: p.Lon}, nil
+    }
+  }
+  return loc, err
+}
+
+// Distance returns the great-circle distance between two points in
+// kilometers
+func Distance(lat1 float64, lon1 float64, lat2 float64, lon2 float64) float64 {
+  const earthRadius = 6371.0
+  phi1, phi2 := lat1*math.Pi/180, lat2*math.Pi/180
+  dphi, dlambda := (lat2-lat1)*math.Pi/180, (lon2-lon1)*math.Pi/180
+  a := math.Sin(dphi/2)*math.Sin(dphi/2) + math.Cos(phi1)*math.Cos(phi2)*math.Sin(dlambda/2)*math.Sin(dlambda/2)
+  return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
+
+// Bearing returns the initial compass bearing (in degrees) from the
+// first point to the second
+func Bearing(lat1 float64, lon1 float64, lat2 float64, lon2 float64) float64 {
+  phi1, phi2 := lat1*math.Pi/180, lat2*math.Pi/180
+  dlambda := (lon2 - lon1) * math.Pi / 180
+  y := math.Sin(dlambda) * math.Cos(phi2)
+  x := math.Cos(phi1)*math.Sin(phi2) - math.Sin(phi1)*math.Cos(phi2)*math.Cos(dlambda)
+  return math.Mod(math.Atan2(y, x)*180/math.Pi+360, 360)
+}
+
+// NearestAirports returns the airports in the gazetteer within
+// radius kilometers of a point, nearest first
+func NearestAirports(lat float64, lon float64, radius float64) []Place {
+  var airports []Place
+  for _, p := range Gazetteer() {
+    if p.Kind == "airport" && p.ICAO != "" && Distance(lat, lon, p.Lat, p.Lon) <= radius {
+      airports = append(airports, p)
+    }
+  }
+  sort.Slice(airports, func(i, j int) bool {
+    return Distance(lat, lon, airports[i].Lat, airports[i].Lon) < Distance(lat, lon, airports[j].Lat, airports[j].Lon)
+  })
+  return airports
+}
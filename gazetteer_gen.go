@@ -0,0 +1,320 @@
+//go:build ignore
+
+/*
+* gazetteer_gen.go
+*
+* This file is part of wu.  It builds gazetteer.tsv.gz from the
+* GeoNames and OurAirports data (run by "go generate").
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 02:42:48 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bufio"
+  "compress/gzip"
+  "encoding/csv"
+  "flag"
+  "fmt"
+  "io"
+  "math"
+  "os"
+  "sort"
+  "strconv"
+  "strings"
+  "unicode"
+)
+
+// The inputs are the GeoNames dump files cities15000.txt and
+// admin1CodesASCII.txt (https://download.geonames.org/export/dump/)
+// and OurAirports' airports.csv (https://ourairports.com/data/).
+// Download them into this directory and run "go generate"; without
+// them, gazetteer.tsv.gz is left as it is.
+
+// place is a row of the gazetteer
+type place struct {
+  kind, name, alternates, city, admin, country string
+  lat, lon, elevation                         float64
+  icao, iata, timezone                        string
+  population                                  int
+}
+
+// caProvinces maps GeoNames' numeric admin1 codes for Canada onto
+// the postal abbreviations, as the US states already are
+var caProvinces = map[string]string{
+  "01": "AB", "02": "BC", "03": "MB", "04": "NB", "05": "NL", "07": "NS",
+  "08": "ON", "09": "PE", "10": "QC", "11": "SK", "12": "YT", "13": "NT", "14": "NU",
+}
+
+func main() {
+  citiesPath := flag.String("cities", "cities15000.txt", "GeoNames cities file")
+  adminPath := flag.String("admin", "admin1CodesASCII.txt", "GeoNames admin1 codes file")
+  airportsPath := flag.String("airports", "airports.csv", "OurAirports airports file")
+  out := flag.String("o", "gazetteer.tsv.gz", "Gazetteer to write (compressed)")
+  flag.Parse()
+
+  for _, path := range []string{*citiesPath, *adminPath, *airportsPath} {
+    if _, err := os.Stat(path); err != nil {
+      fmt.Fprintf(os.Stderr, "gazetteer_gen: %s not found; leaving %s as it is\n", path, *out)
+      return
+    }
+  }
+  admins, err := readAdmins(*adminPath)
+  check(err)
+  places, err := readCities(*citiesPath, admins)
+  check(err)
+  airports, err := readAirports(*airportsPath, places)
+  check(err)
+  check(write(*out, places, airports))
+  fmt.Fprintf(os.Stderr, "gazetteer_gen: wrote %d places and %d airports to %s\n", len(places), len(airports), *out)
+}
+
+func check(err error) {
+  if err != nil {
+    fmt.Fprintln(os.Stderr, "gazetteer_gen:", err)
+    os.Exit(1)
+  }
+}
+
+// tsvRows calls f with the fields of each line of a GeoNames file
+func tsvRows(path string, f func(fields []string)) error {
+  file, err := os.Open(path)
+  if err != nil {
+    return err
+  }
+  defer file.Close()
+  scanner := bufio.NewScanner(file)
+  scanner.Buffer(make([]byte, 1<<20), 1<<24) // alternate names run long
+  for scanner.Scan() {
+    if line := scanner.Text(); line != "" && line[0] != '#' {
+      f(strings.Split(line, "\t"))
+    }
+  }
+  return scanner.Err()
+}
+
+// readAdmins reads the names of the first-level divisions, keyed by
+// country and code ("FR.11")
+func readAdmins(path string) (map[string]string, error) {
+  admins := map[string]string{}
+  err := tsvRows(path, func(f []string) {
+    if len(f) >= 2 {
+      admins[f[0]] = f[1]
+    }
+  })
+  return admins, err
+}
+
+// readCities reads the GeoNames cities, largest first
+func readCities(path string, admins map[string]string) ([]place, error) {
+  var places []place
+  err := tsvRows(path, func(f []string) {
+    if len(f) < 19 {
+      return
+    }
+    p := place{kind: "place", name: f[1], country: f[8], timezone: f[17]}
+    p.lat, _ = strconv.ParseFloat(f[4], 64)
+    p.lon, _ = strconv.ParseFloat(f[5], 64)
+    p.population, _ = strconv.Atoi(f[14])
+    if e, err := strconv.ParseFloat(f[15], 64); err == nil {
+      p.elevation = e
+    } else if dem, err := strconv.ParseFloat(f[16], 64); err == nil && dem > -9999 {
+      p.elevation = dem
+    }
+    switch p.country {
+    case "US":
+      p.admin = f[10]
+    case "CA":
+      p.admin = caProvinces[f[10]]
+    default:
+      p.admin = admins[p.country+"."+f[10]]
+    }
+    p.alternates = alternates(f[1], f[2], f[3])
+    places = append(places, p)
+  })
+  sort.SliceStable(places, func(i, j int) bool { return places[i].population > places[j].population })
+  return places, err
+}
+
+// alternates picks a few other names for a place from GeoNames'
+// long list: those in the Latin alphabet that differ from the name
+// by more than accents and case (the gazetteer ignores those)
+func alternates(name string, ascii string, list string) string {
+  seen := map[string]bool{fold(name): true, fold(ascii): true}
+  var names []string
+  for _, alt := range strings.Split(list, ",") {
+    alt = strings.TrimSpace(alt)
+    if alt == "" || !latin(alt) || seen[fold(alt)] {
+      continue
+    }
+    seen[fold(alt)] = true
+    names = append(names, alt)
+    if len(names) == 4 {
+      break
+    }
+  }
+  return strings.Join(names, ",")
+}
+
+func latin(s string) bool {
+  for _, r := range s {
+    if unicode.IsLetter(r) && !unicode.Is(unicode.Latin, r) {
+      return false
+    }
+    if unicode.IsDigit(r) || r == '(' || r == '\t' {
+      return false
+    }
+  }
+  return true
+}
+
+func fold(s string) string {
+  var b strings.Builder
+  for _, r := range strings.ToLower(s) {
+    if unicode.IsLetter(r) && r < unicode.MaxASCII {
+      b.WriteRune(r)
+    } else if unicode.IsLetter(r) {
+      b.WriteRune(unicode.SimpleFold(r)) // close enough to tell names apart
+    }
+  }
+  return b.String()
+}
+
+// readAirports reads the large and medium airports that have an
+// ICAO code.  OurAirports gives no time zone, and outside the US
+// and Canada a region code rather than a name, so those are taken
+// from the nearest city.
+func readAirports(path string, places []place) ([]place, error) {
+  file, err := os.Open(path)
+  if err != nil {
+    return nil, err
+  }
+  defer file.Close()
+  r := csv.NewReader(file)
+  header, err := r.Read()
+  if err != nil {
+    return nil, err
+  }
+  col := map[string]int{}
+  for i, name := range header {
+    col[name] = i
+  }
+  field := func(row []string, name string) string {
+    if i, ok := col[name]; ok && i < len(row) {
+      return row[i]
+    }
+    return ""
+  }
+
+  var airports []place
+  for {
+    row, err := r.Read()
+    if err == io.EOF {
+      break
+    } else if err != nil {
+      return nil, err
+    }
+    if kind := field(row, "type"); kind != "large_airport" && kind != "medium_airport" {
+      continue
+    }
+    icao := field(row, "icao_code")
+    if icao == "" {
+      icao = field(row, "gps_code")
+    }
+    if len(icao) != 4 {
+      continue
+    }
+    a := place{kind: "airport", name: field(row, "name"), city: field(row, "municipality"),
+      country: field(row, "iso_country"), icao: icao, iata: field(row, "iata_code")}
+    a.lat, _ = strconv.ParseFloat(field(row, "latitude_deg"), 64)
+    a.lon, _ = strconv.ParseFloat(field(row, "longitude_deg"), 64)
+    if ft, err := strconv.ParseFloat(field(row, "elevation_ft"), 64); err == nil {
+      a.elevation = math.Round(ft * 0.3048)
+    }
+    near := nearest(places, a)
+    a.timezone = near.timezone
+    if a.country == "US" || a.country == "CA" {
+      a.admin = strings.TrimPrefix(field(row, "iso_region"), a.country+"-")
+    } else {
+      a.admin = near.admin
+    }
+    airports = append(airports, a)
+  }
+  sort.Slice(airports, func(i, j int) bool { return airports[i].icao < airports[j].icao })
+  return airports, nil
+}
+
+// nearest finds the place closest to an airport, preferring one in
+// the same country
+func nearest(places []place, a place) place {
+  var best place
+  bestDist := math.Inf(1)
+  coslat := math.Cos(a.lat * math.Pi / 180)
+  for _, p := range places {
+    dlat, dlon := p.lat-a.lat, (p.lon-a.lon)*coslat
+    d := dlat*dlat + dlon*dlon
+    if p.country != a.country {
+      d += 100 // about ten degrees
+    }
+    if d < bestDist {
+      best, bestDist = p, d
+    }
+  }
+  return best
+}
+
+// write writes the gazetteer, compressed, in the layout gazetteer.go
+// reads.  The gzip header is left without a name or time, so the
+// same data always makes the same file.
+func write(path string, places []place, airports []place) error {
+  f, err := os.Create(path)
+  if err != nil {
+    return err
+  }
+  z, err := gzip.NewWriterLevel(f, gzip.BestCompression)
+  if err != nil {
+    f.Close()
+    return err
+  }
+  w := bufio.NewWriter(z)
+  fmt.Fprintln(w, "# kind\tname\talternate names\tcity\tadmin\tcountry\tlatitude\tlongitude\televation (m)\tICAO\tIATA\ttimezone\tpopulation")
+  for _, p := range append(places, airports...) {
+    fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%.4f\t%.4f\t%.0f\t%s\t%s\t%s\t%d\n",
+      p.kind, clean(p.name), clean(p.alternates), clean(p.city), clean(p.admin), p.country,
+      p.lat, p.lon, p.elevation, p.icao, p.iata, p.timezone, p.population)
+  }
+  if err := w.Flush(); err != nil {
+    f.Close()
+    return err
+  }
+  if err := z.Close(); err != nil {
+    f.Close()
+    return err
+  }
+  return f.Close()
+}
+
+// clean keeps tabs and newlines out of a field
+func clean(s string) string {
+  return strings.Join(strings.Fields(s), " ")
+}
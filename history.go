@@ -7,7 +7,7 @@
 * Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
 * and Anthony Starks.
 *
-* Last Modified: Mon Aug 01 12:36:46 CDT 2016
+* Last Modified: Mon Oct 19 03:13:25 CDT 2026
 *
 * Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
 *
@@ -32,6 +32,7 @@ import (
   "fmt"
   "math"
   "os"
+  "regexp"
   "strconv"
 )
 
@@ -45,7 +46,13 @@ type History struct {
   Dailysummary []Dailysummary
 }
 
+// Observations are the readings through the day
 type Observations struct {
+  Date      Date
+  Tempi     string
+  Tempm     string
+  Pressurei string
+  Pressurem string
 }
 
 type Dailysummary struct {
@@ -119,156 +126,149 @@ type Dailysummary struct {
   Since1jancoolingdegreedaysnormal   string
 }
 
+const historyTemplate = `{{with index .History.Dailysummary 0 -}}
+{{T "Weather summary for"}} {{$.History.Date.Pretty}}: {{if eq .Fog "1"}}{{T "fog"}} {{end}}{{if eq .Rain "1"}}{{T "rain"}} {{end}}{{if eq .Snow "1"}}{{T "snow"}} {{end}}{{if eq .Hail "1"}}{{T "hail"}} {{end}}{{if eq .Tornado "1"}}{{T "tornado"}} {{end}}
+{{if and (eq .Snow "1") (ne .Monthtodatesnowfalli "")}}   {{T "Snow"}}:
+{{if eq .Snowfalli "T"}}     {{T "trace"}}
+{{else if ge .Snowfalli "0.00"}}     {{precip (printf "%s in (%s mm)" .Snowfalli .Snowfallm)}}
+     {{T "Snow depth"}}: {{.Snowdepthi}} in ({{.Snowdepthm}} mm)
+     {{T "Month to date"}}: {{.Monthtodatesnowfalli}} in ({{.Monthtodatesnowfallm}} mm)
+     {{T "Since July 1st"}}: {{.Since1julsnowfalli}} in ({{.Since1julsnowfallm}} mm)
+{{end}}{{end -}}
+{{if eq .Rain "1"}}{{if eq .Precipi "T"}}   {{T "Precipitation"}}: {{T "trace"}}
+{{else}}   {{T "Precipitation"}}: {{precip (printf "%s in (%s mm)" .Precipi .Precipm)}}
+{{end}}{{end}}   {{T "Temperature"}}:
+{{if celsius}}      {{T "Mean Temperature"}}: {{temp (printf "%s C" .Meantempm)}} ({{.Meantempi}} F)
+      {{T "Max Temperature"}}: {{temp (printf "%s C" .Maxtempm)}} ({{.Maxtempi}} F)
+      {{T "Min Temperature"}}: {{temp (printf "%s C" .Mintempm)}} ({{.Mintempi}} F)
+{{else}}      {{T "Mean Temperature"}}: {{temp (printf "%s F" .Meantempi)}} ({{.Meantempm}} C)
+      {{T "Max Temperature"}}: {{temp (printf "%s F" .Maxtempi)}} ({{.Maxtempm}} C)
+      {{T "Min Temperature"}}: {{temp (printf "%s F" .Mintempi)}} ({{.Mintempm}} C)
+{{end}}   {{T "Degree Days"}}:
+{{if ne .Heatingdegreedays ""}}      {{T "Heating Degree Days"}}: {{.Heatingdegreedays}}{{if ne .Heatingdegreedaysnormal ""}} ({{.Heatingdegreedaysnormal}} {{T "days normal"}})
+{{end}}{{if and (ne .Heatingdegreedaysnormal "") (ne .Heatingdegreedaysnormal "0")}}         {{T "HDG month to date"}}: {{.Monthtodateheatingdegreedays}} ({{.Monthtodateheatingdegreedaysnormal}} {{T "days normal"}})
+{{if eq .Since1julheatingdegreedaysnormal ""}}         {{T "HDG since Sept 1st"}}: {{.Since1sepheatingdegreedays}} ({{.Since1sepheatingdegreedaysnormal}} {{T "days normal"}})
+{{else}}         {{T "HDG since July 1st"}}: {{.Since1julheatingdegreedays}} ({{.Since1julheatingdegreedaysnormal}} {{T "days normal"}})
+{{end}}{{else}}
+{{end}}{{end -}}
+{{if and (ne .Coolingdegreedaysnormal "") (ne .Coolingdegreedaysnormal "0")}}      {{T "Cooling Degree Days"}}: {{.Coolingdegreedays}} ({{.Coolingdegreedaysnormal}} {{T "days normal"}})
+         {{T "CDG month to date"}}: {{.Monthtodatecoolingdegreedays}} ({{.Monthtodatecoolingdegreedaysnormal}} {{T "days normal"}})
+{{if eq .Since1jancoolingdegreedaysnormal ""}}         {{T "CDG since Sept 1st"}}: {{.Since1sepcoolingdegreedays}} ({{.Since1sepcoolingdegreedaysnormal}} {{T "days normal"}})
+{{else}}         {{T "CDG since Jan 1st"}}: {{.Since1jancoolingdegreedays}} ({{.Since1jancoolingdegreedaysnormal}} {{T "days normal"}})
+{{end}}{{end}}   {{T "Moisture"}}:
+{{if celsius}}      {{T "Mean Dew Point"}}: {{.Meandewptm}} ({{.Meandewpti}} F)
+      {{T "Max Dew Point"}}: {{.Maxdewptm}} ({{.Maxdewpti}} F)
+      {{T "Min Dew Point"}}: {{.Mindewptm}} ({{.Mindewpti}} F)
+{{else}}      {{T "Mean Dew Point"}}: {{.Meandewpti}} ({{.Meandewptm}} C)
+      {{T "Max Dew Point"}}: {{.Maxdewpti}} ({{.Maxdewptm}} C)
+      {{T "Min Dew Point"}}: {{.Mindewpti}} ({{.Mindewptm}} C)
+{{end}}{{if ne .Humidity ""}}      {{T "Humidity"}}: {{.Humidity}}%
+{{end}}      {{T "Max Humidity"}}: {{.Maxhumidity}}%
+      {{T "Min Humidity"}}: {{.Minhumidity}}%
+   {{T "Pressure"}}:
+      {{T "Mean Pressure"}}: {{.Meanpressurei}} in ({{.Meanpressurem}} mb)
+      {{T "Max Pressure"}}: {{.Maxpressurei}} in ({{.Maxpressurem}} mb)
+      {{T "Min Pressure"}}: {{.Minpressurei}} in ({{.Minpressurem}} mb)
+   {{T "Wind"}}:
+      {{T "Mean Wind Speed"}}: {{.Meanwindspdi}} mph ({{.Meanwindspdm}} kph)
+      {{T "Max Wind Speed"}}: {{.Maxwspdi}} mph ({{.Maxwspdm}} kph)
+      {{T "Min Wind Speed"}}: {{.Minwspdi}} mph ({{.Minwspdm}} kph)
+      {{T "Mean Wind Direction"}}: {{.Meanwdird}}° ({{compass .Meanwdird}})
+   {{T "Visibility"}}:
+      {{T "Mean Visibility"}} {{.Meanvisi}} mi ({{.Meanvism}} km)
+      {{T "Max Visibility"}} {{.Maxvisi}} mi ({{.Maxvism}} km)
+      {{T "Min Visibility"}} {{.Minvisi}} mi ({{.Minvism}} km)
+{{end}}`
+
 func PrintHistory(obs *HistoryConditions, stationId string, degrees string) {
 
   if len(obs.History.Observations) == 0 {
-    fmt.Println("No data available for specified date")
+    fmt.Println(T("No data available for specified date"))
     os.Exit(0)
   }
 
-  history := obs.History.Dailysummary[0]
-  fmt.Printf("Weather summary for %s: ", obs.History.Date.Pretty)
-  if history.Fog == "1" {
-    fmt.Print("fog ")
-  }
-  if history.Rain == "1" {
-    fmt.Print("rain ")
-  }
-  if history.Snow == "1" {
-    fmt.Print("snow ")
-  }
-  if history.Hail == "1" {
-    fmt.Print("hail ")
+  Render("history", stationId, degrees, obs)
+  if charting() {
+    printChart(T("Temperature"), historyChart(obs.History.Observations))
   }
-  if history.Tornado == "1" {
-    fmt.Print("tornado ")
-  }
-  fmt.Print("\n")
-
-  // if "month to date" is nil, it likely means that the station
-  // doesn't report full almanac information (which is frequently
-  // the case for non-U.S (NWS) station sources.  This may be the
-  // case for several measurements in this section.
-
-  // Snow
-
-  if history.Snow == "1" && history.Monthtodatesnowfalli != "" {
-    fmt.Println("   Snow:")
-    if history.Snowfalli == "T" {
-      fmt.Println("     trace")
-    } else if history.Snowfalli >= "0.00" {
-      fmt.Printf("     %s in (%s mm)\n", history.Snowfalli, history.Snowfallm)
+}
 
-      fmt.Printf("     Snow depth: %s in (%s mm)\n", history.Snowdepthi, history.Snowdepthm)
-      fmt.Printf("     Month to date: %s in (%s mm)\n", history.Monthtodatesnowfalli, history.Monthtodatesnowfallm)
-      fmt.Printf("     Since July 1st: %s in (%s mm)\n", history.Since1julsnowfalli, history.Since1julsnowfallm)
+// historyChart draws the temperature through a day, marking every
+// third hour
+func historyChart(observations []Observations) []string {
+  var temps []float64
+  var labels []string
+  labelled := ""
+  for _, o := range observations {
+    temp := o.Tempi
+    if conf.Degrees == "C" {
+      temp = o.Tempm
     }
-  }
-
-  // Precipitation
-
-  if history.Rain == "1" {
-    if history.Precipi == "T" {
-      fmt.Printf("   Precipitation: trace\n")
-    } else {
-      fmt.Printf("   Precipitation: %s in (%s mm)\n", history.Precipi, history.Precipm)
+    temps = append(temps, float64(ParseNumber(temp)))
+    label := ""
+    if hour, _ := strconv.Atoi(o.Date.Hour); hour%3 == 0 && o.Date.Hour != labelled {
+      label, labelled = o.Date.Hour+":00", o.Date.Hour
     }
+    labels = append(labels, label)
   }
+  height, width := chartSize()
+  return LineChart(temps, labels, height, width, tempFormat())
+}
 
-  // Temperature
-
-  fmt.Println("   Temperature:")
-	if (degrees == "C") {
-		fmt.Printf("      Mean Temperature: %s C (%s F)\n", history.Meantempm, history.Meantempi)
-		fmt.Printf("      Max Temperature: %s C (%s F)\n", history.Maxtempm, history.Maxtempi)
-		fmt.Printf("      Min Temperature: %s C (%s F)\n", history.Mintempm, history.Mintempi)
-	} else {
-		fmt.Printf("      Mean Temperature: %s F (%s C)\n", history.Meantempi, history.Meantempm)
-		fmt.Printf("      Max Temperature: %s F (%s C)\n", history.Maxtempi, history.Maxtempm)
-		fmt.Printf("      Min Temperature: %s F (%s C)\n", history.Mintempi, history.Mintempm)
-	}
-
-  // Degree Days
+// historyRange matches --history=YYYYMMDD-YYYYMMDD
+var historyRange = regexp.MustCompile(`^\d{8}-\d{8}$`)
 
-  fmt.Println("   Degree Days:")
-  if history.Heatingdegreedays != "" {
-    fmt.Print("      Heating Degree Days: " + history.Heatingdegreedays)
-    if history.Heatingdegreedaysnormal != "" {
-      fmt.Printf(" (%s days normal)\n", history.Heatingdegreedaysnormal)
-    }
-    if history.Heatingdegreedaysnormal != "" && history.Heatingdegreedaysnormal != "0" {
-      fmt.Printf("         HDG month to date: %s (%s days normal)\n", history.Monthtodateheatingdegreedays, history.Monthtodateheatingdegreedaysnormal)
-      if history.Since1julheatingdegreedaysnormal == "" {
-        fmt.Printf("         HDG since Sept 1st: %s (%s days normal)\n", history.Since1sepheatingdegreedays, history.Since1sepheatingdegreedaysnormal)
-      } else {
-        fmt.Printf("         HDG since July 1st: %s (%s days normal)\n", history.Since1julheatingdegreedays, history.Since1julheatingdegreedaysnormal)
-      }
-    } else {
-      fmt.Print("\n")
-    }
+// ChartHistory charts the precipitation of a range of days from the
+// station's archive
+func ChartHistory(station string, days string) {
+  if !charting() {
+    fmt.Fprintln(os.Stderr, T("A range of days can only be charted (--chart); \"wu climate\" reports on them."))
+    os.Exit(1)
   }
-
-  if history.Coolingdegreedaysnormal != "" && history.Coolingdegreedaysnormal != "0" {
-    fmt.Print("      Cooling Degree Days: " + history.Coolingdegreedays)
-    if history.Coolingdegreedaysnormal != "" {
-      fmt.Printf(" (%s days normal)\n", history.Coolingdegreedaysnormal)
-    } else {
-      fmt.Print("\n")
+  from, err := parseDay(days[:8])
+  CheckError(err)
+  to, err := parseDay(days[9:])
+  CheckError(err)
+  if from.After(to) {
+    CheckError(fmt.Errorf("%s is after %s", from.Format("2006-01-02"), to.Format("2006-01-02")))
+  }
+  archive := LoadArchive(station)
+
+  var precip []float64
+  var labels []string
+  missing := 0
+  for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+    summary, ok := archive[d.Format(archiveDate)]
+    amount := summary.Precipi
+    if conf.Degrees == "C" {
+      amount = summary.Precipm
     }
-    if history.Coolingdegreedaysnormal != "" {
-      fmt.Printf("         CDG month to date: %s (%s days normal)\n", history.Monthtodatecoolingdegreedays, history.Monthtodatecoolingdegreedaysnormal)
-      if history.Since1jancoolingdegreedaysnormal == "" {
-        fmt.Printf("         CDG since Sept 1st: %s (%s days normal)\n", history.Since1sepcoolingdegreedays, history.Since1sepcoolingdegreedaysnormal)
-      } else {
-        fmt.Printf("         CDG since Jan 1st: %s (%s days normal)\n", history.Since1jancoolingdegreedays, history.Since1jancoolingdegreedaysnormal)
-      }
-    } else {
-      fmt.Print("\n")
+    switch {
+    case !ok:
+      missing++
+      precip = append(precip, math.NaN())
+    case amount == "T":
+      precip = append(precip, 0)
+    default:
+      precip = append(precip, float64(ParseNumber(amount)))
     }
+    labels = append(labels, d.Format("Jan 2"))
   }
 
-  // Moisture
-
-  fmt.Println("   Moisture:")
-	if (degrees == "C") {
-  fmt.Printf("      Mean Dew Point: %s (%s F)\n", history.Meandewptm, history.Meandewpti)
-  fmt.Printf("      Max Dew Point: %s (%s F)\n", history.Maxdewptm, history.Maxdewpti)
-  fmt.Printf("      Min Dew Point: %s (%s F)\n", history.Mindewptm, history.Mindewpti)
-	} else {
-		fmt.Printf("      Mean Dew Point: %s (%s C)\n", history.Meandewpti, history.Meandewptm)
-		fmt.Printf("      Max Dew Point: %s (%s C)\n", history.Maxdewpti, history.Maxdewptm)
-		fmt.Printf("      Min Dew Point: %s (%s C)\n", history.Mindewpti, history.Mindewptm)
-	}
-
-  if history.Humidity != "" {
-    fmt.Printf("      Humidity: %s%%\n", history.Humidity)
+  format := "%.2f in"
+  if conf.Degrees == "C" {
+    format = "%.0f mm"
+  }
+  height, width := chartSize()
+  fmt.Println(StyleHeading(fmt.Sprintf(T("Precipitation at %s, %s to %s"), station, from.Format("2006-01-02"), to.Format("2006-01-02"))))
+  for _, line := range BarChart(precip, labels, height, width, format) {
+    fmt.Println(StylePrecip(line))
+  }
+  if missing > 0 {
+    message := Plural(missing, "%d day is missing: fill it in with \"wu backfill --from %s --to %s\"",
+      "%d days are missing: fill them in with \"wu backfill --from %s --to %s\"")
+    fmt.Printf(message+"\n", missing, from.Format("2006-01-02"), to.Format("2006-01-02"))
   }
-  fmt.Printf("      Max Humidity: %s%%\n", history.Maxhumidity)
-  fmt.Printf("      Min Humidity: %s%%\n", history.Minhumidity)
-
-  // Pressure
-
-  fmt.Println("   Pressure:")
-  fmt.Printf("      Mean Pressure: %s in (%s mb)\n", history.Meanpressurei, history.Meanpressurem)
-  fmt.Printf("      Max Pressure: %s in (%s mb)\n", history.Maxpressurei, history.Maxpressurem)
-  fmt.Printf("      Min Pressure: %s in (%s mb)\n", history.Minpressurei, history.Minpressurem)
-
-  // Wind
-
-  fmt.Println("   Wind:")
-  fmt.Printf("      Mean Wind Speed: %s mph (%s kph)\n", history.Meanwindspdi, history.Meanwindspdm)
-  fmt.Printf("      Max Wind Speed: %s mph (%s kph)\n", history.Maxwspdi, history.Maxwspdm)
-  fmt.Printf("      Min Wind Speed: %s mph (%s kph)\n", history.Minwspdi, history.Minwspdm)
-  boxedPoint := boxCompass(history.Meanwdird)
-  fmt.Printf("      Mean Wind Direction: %s° (%s)\n", history.Meanwdird, boxedPoint)
-
-  // Visibility
-
-  fmt.Println("   Visibility:")
-  fmt.Printf("      Mean Visibility %s mi (%s km)\n", history.Meanvisi, history.Meanvism)
-  fmt.Printf("      Max Visibility %s mi (%s km)\n", history.Maxvisi, history.Maxvism)
-  fmt.Printf("      Min Visibility %s mi (%s km)\n", history.Minvisi, history.Minvism)
-
 }
 
 // Convert wind degrees to boxed compass points.
@@ -314,6 +314,6 @@ func boxCompass(degreeString string) string {
     direction = "N"
   }
 
-  return direction
+  return localCompass(direction)
 
 }
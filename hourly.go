@@ -0,0 +1,120 @@
+/*
+* hourly.go
+*
+* This file is part of wu.  It contains functions related to
+* the -hourly switch (hourly forecast).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 01:50:59 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "encoding/json"
+)
+
+type HourlyConditions struct {
+  Hourly_forecast []HourlyForecast
+}
+
+type HourlyForecast struct {
+  Fcttime   FctTime
+  Temp      Measure
+  Dewpoint  Measure
+  Feelslike Measure
+  Condition string
+  Pop       Number
+  Qpf       Measure
+  Snow      Measure
+  Wspd      Measure
+  Wdir      Direction
+  Humidity  Number
+}
+
+func (h *HourlyForecast) UnmarshalJSON(b []byte) error {
+  type hourlyForecast HourlyForecast // without this method
+  missingNumbers(h)
+  return json.Unmarshal(b, (*hourlyForecast)(h))
+}
+
+type FctTime struct {
+  Hour                string
+  Min                 string
+  Mday                string
+  Mon_abbrev          string
+  Weekday_name_abbrev string
+  Civil               string
+  Epoch               string
+}
+
+// Measure is a value in English and metric units
+type Measure struct {
+  English Number
+  Metric  Number
+}
+
+type Direction struct {
+  Dir     string
+  Degrees Number
+}
+
+const hourlyTemplate = `{{T "Hourly forecast for"}} {{station}}
+{{heading (printf "%-12s %4s  %-24s %4s %8s  %-10s %4s" (T "Time") (T "Temp") (T "Conditions") (T "POP") (T "Precip") (T "Wind") (T "RH"))}}
+{{range .Hourly_forecast}}{{printf "%-3s %8s" (T .Fcttime.Weekday_name_abbrev) .Fcttime.Civil}} {{if celsius -}}
+{{temp (printf "%3s°" (fixed 0 .Temp.Metric))}}  {{pad 24 .Condition}} {{printf "%3s%%" (fixed 0 .Pop)}} {{precip (printf "%5s mm" (fixed 1 .Qpf.Metric))}}  {{pad 10 (printf "%s %s km/h" .Wdir.Dir (fixed 0 .Wspd.Metric))}}
+{{- else -}}
+{{temp (printf "%3s°" (fixed 0 .Temp.English))}}  {{pad 24 .Condition}} {{printf "%3s%%" (fixed 0 .Pop)}} {{precip (printf "%5s in" (fixed 2 .Qpf.English))}}  {{pad 10 (printf "%s %s mph" .Wdir.Dir (fixed 0 .Wspd.English))}}
+{{- end}} {{printf "%3s%%" (fixed 0 .Humidity)}}
+{{end}}`
+
+// PrintHourly prints the hourly forecast for a given station to
+// standard out, with --chart a chart of the temperature
+func PrintHourly(obs *HourlyConditions, stationId string) {
+  Render("hourly", stationId, conf.Degrees, obs)
+  if charting() {
+    printChart(T("Temperature"), hourlyChart(obs.Hourly_forecast))
+  }
+}
+
+// hourlyChart draws the temperature hour by hour, marking midnight
+// with the day and every six hours with the time
+func hourlyChart(hours []HourlyForecast) []string {
+  var temps []float64
+  var labels []string
+  for _, h := range hours {
+    temp := h.Temp.English
+    if conf.Degrees == "C" {
+      temp = h.Temp.Metric
+    }
+    temps = append(temps, float64(temp))
+    switch h.Fcttime.Hour {
+    case "0":
+      labels = append(labels, T(h.Fcttime.Weekday_name_abbrev))
+    case "6", "12", "18":
+      labels = append(labels, h.Fcttime.Hour+":00")
+    default:
+      labels = append(labels, "")
+    }
+  }
+  height, width := chartSize()
+  return LineChart(temps, labels, height, width, tempFormat())
+}
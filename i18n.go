@@ -0,0 +1,134 @@
+/*
+* i18n.go
+*
+* This file is part of wu.  It contains functions for localizing
+* the text of reports (the "language" setting in .condrc, or
+* $LANG).  The message catalogs themselves are in catalog_*.go.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 03:13:25 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "os"
+  "strings"
+)
+
+// catalogs map English messages to their translations.  English
+// needs no catalog: a message is its own English text.
+var catalogs = map[string]map[string]string{
+  "de": catalogDE,
+  "es": catalogES,
+  "fr": catalogFR,
+}
+
+// language is decided once, from .condrc or the environment
+var language string
+
+// Language returns the two-letter code of the language to use:
+// the "language" setting in .condrc, else the one named by
+// $LC_ALL, $LC_MESSAGES or $LANG (e.g. "de_DE.UTF-8"), else English.
+func Language() string {
+  if language == "" {
+    language = "en"
+    candidates := []string{conf.Language, os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG")}
+    for _, c := range candidates {
+      if c == "" {
+        continue
+      }
+      code := strings.ToLower(c)
+      if len(code) > 2 {
+        code = code[:2]
+      }
+      if _, ok := catalogs[code]; ok || code == "en" {
+        language = code
+      }
+      break
+    }
+  }
+  return language
+}
+
+// T translates a message into the user's language, falling back
+// on English
+func T(message string) string {
+  if translated, ok := catalogs[Language()][message]; ok {
+    return translated
+  }
+  return message
+}
+
+// Plural translates the form of a message for a count: one for a
+// single thing (or, in French, none), other for any other number
+func Plural(n int, one string, other string) string {
+  if n == 1 || n == 0 && Language() == "fr" {
+    return T(one)
+  }
+  return T(other)
+}
+
+// MonthName returns the localized name of a month (1-12)
+func MonthName(month int) string {
+  names := []string{"January", "February", "March", "April", "May", "June",
+    "July", "August", "September", "October", "November", "December"}
+  if month < 1 || month > 12 {
+    return ""
+  }
+  return T(names[month-1])
+}
+
+// LongDate formats a date in the usual way for the language, e.g.
+// "October 19, 2026" or "19. Oktober 2026"
+func LongDate(month int, day string, year string) string {
+  name := MonthName(month)
+  switch Language() {
+  case "de":
+    return day + ". " + name + " " + year
+  case "es":
+    return day + " de " + strings.ToLower(name) + " de " + year
+  case "fr":
+    return day + " " + strings.ToLower(name) + " " + year
+  }
+  return name + " " + day + ", " + year
+}
+
+// localCompass localizes a compass point such as "WSW".  Spanish and
+// French write west as O (oeste, ouest); German writes east as O (Ost).
+func localCompass(point string) string {
+  switch Language() {
+  case "es", "fr":
+    return strings.Replace(point, "W", "O", -1)
+  case "de":
+    return strings.Replace(point, "E", "O", -1)
+  }
+  return point
+}
+
+// DecimalSeparator returns the decimal separator for the language
+func DecimalSeparator() string {
+  switch Language() {
+  case "de", "es", "fr":
+    return ","
+  }
+  return "."
+}
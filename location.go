@@ -0,0 +1,318 @@
+/*
+* location.go
+*
+* This file is part of wu.  It contains the parser for the
+* locations given with -s and in the configuration.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 03:09:36 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "math"
+  "regexp"
+  "strconv"
+  "strings"
+)
+
+// The kinds of Location
+const (
+  PlaceLocation   = "place"
+  PostalLocation  = "postal"
+  ICAOLocation    = "icao"
+  IATALocation    = "iata"
+  CoordsLocation  = "coordinates"
+  GeohashLocation = "geohash"
+  PWSLocation     = "pws"
+  AutoIPLocation  = "autoip"
+  ZMWLocation     = "zmw"
+)
+
+// Location is a parsed weather station or place
+type Location struct {
+  Kind    string
+  Name    string  // place name
+  Region  string  // state or province abbreviation, or region name
+  Country string  // ISO 3166 code
+  Postal  string  // postal code
+  Code    string  // ICAO or IATA airport code, PWS id, geohash, or zmw code
+  Lat     float64 // for coordinates and geohashes
+  Lon     float64
+}
+
+var (
+  pwsPattern     = regexp.MustCompile(`^(?:(?i)pws:(\w+)|([A-Z]{4,}[0-9]+))$`)
+  zmwPattern     = regexp.MustCompile(`^(?i)zmw:(\d+\.\d+\.\d+)$`)
+  codePattern    = regexp.MustCompile(`^(?:[A-Z]{3,4}|[a-z]{3,4})$`)
+  wordPattern    = regexp.MustCompile(`^[A-Za-z]{3,4}$`)
+  zipPattern     = regexp.MustCompile(`^(\d{5})(?:-\d{4})?$`)
+  canadaPostal   = regexp.MustCompile(`^(?i)([A-Z]\d[A-Z]) ?(\d[A-Z]\d)$`)
+  ukPostcode     = regexp.MustCompile(`^(?i)[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`)
+  postalPattern  = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9 -]{1,9}$`)
+  namePattern    = regexp.MustCompile(`^\pL[\pL\pM .'’-]*$`)
+  geohashPattern = regexp.MustCompile(`^(?:geo:)?([0-9b-hjkmnp-z]{4,12})$`)
+  decimalCoords  = regexp.MustCompile(`^(?i)([+-]?\d{1,3}(?:\.\d+)?)\s*°?\s*([NS])?\s*[,;\s]\s*([+-]?\d{1,3}(?:\.\d+)?)\s*°?\s*([EW])?$`)
+  dmsCoord       = `(\d{1,3})\s*[°d ]\s*(?:(\d{1,2}(?:\.\d+)?)\s*['′m]?\s*)?(?:(\d{1,2}(?:\.\d+)?)\s*(?:"|″|''|s)?\s*)?([NSEW])`
+  dmsCoords      = regexp.MustCompile(`^(?i)` + dmsCoord + `\s*[,;]?\s*` + dmsCoord + `$`)
+)
+
+// ParseLocation parses a station or place given as "City, ST",
+// "City, Region, Country" or "City, Country"; a postal code (US or
+// Canadian, or followed by a country); a 4-letter ICAO or 3-letter
+// IATA airport code; latitude and longitude, in decimal degrees or
+// degrees, minutes and seconds; a geohash ("geo:9yzg0"); a personal
+// weather station ID ("KNYLONGE2" or "pws:KNYLONGE2"); or one of the
+// API's own queries, "autoip" (wherever the request comes from) or a
+// zmw code ("zmw:00000.1.10400"), which are passed on as they are.
+func ParseLocation(s string) (Location, error) {
+  s = strings.TrimSpace(s)
+  switch {
+  case s == "":
+    return Location{}, fmt.Errorf("no location given")
+  case strings.EqualFold(s, "autoip"):
+    return Location{Kind: AutoIPLocation}, nil
+  case zmwPattern.MatchString(s):
+    return Location{Kind: ZMWLocation, Code: zmwPattern.FindStringSubmatch(s)[1]}, nil
+  case pwsPattern.MatchString(s):
+    m := pwsPattern.FindStringSubmatch(s)
+    return Location{Kind: PWSLocation, Code: strings.ToUpper(m[1] + m[2])}, nil
+  case codePattern.MatchString(s):
+    if len(s) == 3 {
+      return Location{Kind: IATALocation, Code: strings.ToUpper(s)}, nil
+    }
+    return Location{Kind: ICAOLocation, Code: strings.ToUpper(s)}, nil
+  case wordPattern.MatchString(s):
+    return Location{}, fmt.Errorf("%q is ambiguous: write it in capitals (%q) for an airport code, or add the state or country for a place (\"%s, ...\")", s, strings.ToUpper(s), s)
+  case zipPattern.MatchString(s):
+    return Location{Kind: PostalLocation, Postal: zipPattern.FindStringSubmatch(s)[1], Country: "US"}, nil
+  case canadaPostal.MatchString(s):
+    m := canadaPostal.FindStringSubmatch(s)
+    return Location{Kind: PostalLocation, Postal: strings.ToUpper(m[1] + " " + m[2]), Country: "CA"}, nil
+  case ukPostcode.MatchString(s):
+    return Location{Kind: PostalLocation, Postal: strings.ToUpper(s), Country: "GB"}, nil
+  }
+
+  if loc, ok, err := parseCoords(s); ok || err != nil {
+    return loc, err
+  }
+  if m := geohashPattern.FindStringSubmatch(s); m != nil {
+    // Without the prefix, a geohash needs both letters and digits:
+    // all digits could as well be a postal code ("8001")
+    switch {
+    case strings.HasPrefix(s, "geo:"), strings.ContainsAny(s, "0123456789") && strings.ContainsAny(s, "bcdefghjkmnpqrstuvwxyz"):
+      lat, lon := decodeGeohash(m[1])
+      return Location{Kind: GeohashLocation, Code: m[1], Lat: lat, Lon: lon}, nil
+    case !strings.ContainsAny(s, "bcdefghjkmnpqrstuvwxyz"):
+      return Location{}, fmt.Errorf("%q is ambiguous: add the country for a postal code (\"%s, ...\"), or write \"geo:%s\" for a geohash", s, s, s)
+    }
+  }
+
+  parts := strings.Split(s, ",")
+  for i := range parts {
+    parts[i] = strings.TrimSpace(parts[i])
+  }
+  switch len(parts) {
+  case 1:
+    if namePattern.MatchString(s) {
+      return Location{}, fmt.Errorf("%q is ambiguous: add the state or country (\"%s, ...\")", s, s)
+    }
+    return Location{}, fmt.Errorf("can't make sense of the location %q", s)
+  case 2:
+    return parsePlace(s, parts[0], "", parts[1])
+  case 3:
+    return parsePlace(s, parts[0], parts[1], parts[2])
+  }
+  return Location{}, fmt.Errorf("can't make sense of the location %q: too many commas", s)
+}
+
+// parsePlace parses a place name, postal code and country, or a
+// place name, region and country, from the parts of a location
+func parsePlace(s string, name string, region string, country string) (Location, error) {
+  // A postal code and country ("8001, Switzerland")
+  if region == "" && strings.ContainsAny(name, "0123456789") && postalPattern.MatchString(name) {
+    code, ok := lookupCountry(country)
+    if !ok {
+      return Location{}, fmt.Errorf("%q: unknown country %q", s, country)
+    }
+    return Location{Kind: PostalLocation, Postal: strings.ToUpper(name), Country: code}, nil
+  }
+  if !namePattern.MatchString(name) {
+    return Location{}, fmt.Errorf("%q: %q isn't a place name", s, name)
+  }
+
+  if region == "" {
+    // "City, ST" or "City, Country".  A two-letter abbreviation is
+    // read as a US state or Canadian province (so "Paris, CA" is in
+    // California); a name that's both a state and a country has to
+    // be spelled out.
+    abbr, regionCountry, isRegion := lookupRegion(country)
+    code, isCountry := lookupCountry(country)
+    switch {
+    case isRegion && len(country) == 2:
+      return Location{Kind: PlaceLocation, Name: name, Region: abbr, Country: regionCountry}, nil
+    case isRegion && isCountry:
+      // The gazetteer may know which is meant
+      inRegion, inCountry := SearchPlaces(name, abbr, regionCountry), SearchPlaces(name, "", code)
+      if len(inRegion) > 0 && len(inCountry) == 0 {
+        return Location{Kind: PlaceLocation, Name: name, Region: abbr, Country: regionCountry}, nil
+      } else if len(inCountry) > 0 && len(inRegion) == 0 {
+        return Location{Kind: PlaceLocation, Name: name, Country: code}, nil
+      }
+      return Location{}, fmt.Errorf("%q is ambiguous: write \"%s, %s\" for the state, or \"%s, %s\" for the country", s, name, abbr, name, countries[code][0])
+    case isRegion:
+      return Location{Kind: PlaceLocation, Name: name, Region: abbr, Country: regionCountry}, nil
+    case isCountry:
+      return Location{Kind: PlaceLocation, Name: name, Country: code}, nil
+    case namePattern.MatchString(country):
+      // Some other country or region: leave it to the API
+      return Location{Kind: PlaceLocation, Name: name, Region: country}, nil
+    }
+    return Location{}, fmt.Errorf("%q: %q isn't a state, province or country", s, country)
+  }
+
+  code, ok := lookupCountry(country)
+  if !ok {
+    return Location{}, fmt.Errorf("%q: unknown country %q", s, country)
+  }
+  if abbr, regionCountry, ok := lookupRegion(region); ok && regionCountry == code {
+    region = abbr
+  } else if code == "US" || code == "CA" {
+    return Location{}, fmt.Errorf("%q: %q isn't a state or province of %s", s, region, countries[code][0])
+  }
+  return Location{Kind: PlaceLocation, Name: name, Region: region, Country: code}, nil
+}
+
+// parseCoords parses latitude and longitude in decimal degrees
+// ("40.81,-96.71", "40.81N 96.71W") or degrees, minutes and
+// seconds ("40°48'47\"N 96°42'30\"W").  It reports whether s was
+// coordinates at all.
+func parseCoords(s string) (Location, bool, error) {
+  var lat, lon float64
+  if m := decimalCoords.FindStringSubmatch(s); m != nil {
+    lat, _ = strconv.ParseFloat(m[1], 64)
+    lon, _ = strconv.ParseFloat(m[3], 64)
+    if strings.EqualFold(m[2], "S") {
+      lat = -lat
+    }
+    if strings.EqualFold(m[4], "W") {
+      lon = -lon
+    }
+  } else if m := dmsCoords.FindStringSubmatch(s); m != nil {
+    a, ha := dms(m[1], m[2], m[3]), strings.ToUpper(m[4])
+    b, hb := dms(m[5], m[6], m[7]), strings.ToUpper(m[8])
+    if strings.Contains("EW", ha) {
+      a, ha, b, hb = b, hb, a, ha
+    }
+    if !strings.Contains("NS", ha) || !strings.Contains("EW", hb) {
+      return Location{}, true, fmt.Errorf("%q: give one latitude (N or S) and one longitude (E or W)", s)
+    }
+    lat, lon = a, b
+    if ha == "S" {
+      lat = -lat
+    }
+    if hb == "W" {
+      lon = -lon
+    }
+  } else {
+    return Location{}, false, nil
+  }
+  if math.Abs(lat) > 90 || math.Abs(lon) > 180 {
+    return Location{}, true, fmt.Errorf("%q: latitude must be within 90° and longitude within 180°", s)
+  }
+  return Location{Kind: CoordsLocation, Lat: lat, Lon: lon}, true, nil
+}
+
+// dms converts degrees, minutes and seconds to decimal degrees
+func dms(d string, m string, s string) float64 {
+  deg, _ := strconv.ParseFloat(d, 64)
+  min, _ := strconv.ParseFloat(m, 64)
+  sec, _ := strconv.ParseFloat(s, 64)
+  return deg + min/60 + sec/3600
+}
+
+// decodeGeohash returns the latitude and longitude at the center of
+// a geohash cell
+func decodeGeohash(hash string) (float64, float64) {
+  const base32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+  lat, lon := [2]float64{-90, 90}, [2]float64{-180, 180}
+  even := true
+  for _, c := range hash {
+    bits := strings.IndexRune(base32, c)
+    for mask := 16; mask > 0; mask >>= 1 {
+      r := &lat
+      if even {
+        r = &lon
+      }
+      mid := (r[0] + r[1]) / 2
+      if bits&mask != 0 {
+        r[0] = mid
+      } else {
+        r[1] = mid
+      }
+      even = !even
+    }
+  }
+  return (lat[0] + lat[1]) / 2, (lon[0] + lon[1]) / 2
+}
+
+// Query returns the location as the API expects it
+func (l Location) Query() (string, error) {
+  switch l.Kind {
+  case PlaceLocation:
+    if l.Region == "" && (l.Country == "US" || l.Country == "CA") {
+      // The API finds American and Canadian places by state or
+      // province; failing that, the gazetteer may know where it is.
+      if p, err := Geocode(l); err == nil {
+        return Location{Kind: CoordsLocation, Lat: p.Lat, Lon: p.Lon}.Query()
+      }
+      region := "state"
+      if l.Country == "CA" {
+        region = "province"
+      }
+      return "", fmt.Errorf("%s: add the %s (\"%s, ...\")", l.Name, region, l.Name)
+    }
+    place := l.Region
+    if names, ok := countries[l.Country]; ok && l.Country != "US" && l.Country != "CA" {
+      place = names[0]
+    }
+    underscores := strings.NewReplacer(" ", "_", ".", "")
+    return underscores.Replace(place) + "/" + underscores.Replace(l.Name), nil
+  case PostalLocation:
+    if l.Country != "US" && l.Country != "CA" {
+      return "", fmt.Errorf("postal code %s: only US and Canadian postal codes can be looked up; try the place name", l.Postal)
+    }
+    return strings.Replace(l.Postal, " ", "", -1), nil
+  case ICAOLocation, IATALocation:
+    return l.Code, nil
+  case PWSLocation:
+    return "pws:" + l.Code, nil
+  case AutoIPLocation:
+    return "autoip", nil
+  case ZMWLocation:
+    return "zmw:" + l.Code, nil
+  case CoordsLocation, GeohashLocation:
+    return strconv.FormatFloat(l.Lat, 'f', -1, 64) + "," + strconv.FormatFloat(l.Lon, 'f', -1, 64), nil
+  }
+  return "", fmt.Errorf("unknown kind of location %q", l.Kind)
+}
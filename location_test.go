@@ -0,0 +1,128 @@
+/*
+* location_test.go
+*
+* This file is part of wu.  It contains tests for parsing locations.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 03:09:36 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "math"
+  "strings"
+  "testing"
+)
+
+func TestParseLocation(t *testing.T) {
+  tests := []struct {
+    in   string
+    want Location
+    err  string // part of the error, if one is expected
+  }{
+    {in: "St. Louis, MO", want: Location{Kind: PlaceLocation, Name: "St. Louis", Region: "MO", Country: "US"}},
+    {in: "Winston-Salem, North Carolina", want: Location{Kind: PlaceLocation, Name: "Winston-Salem", Region: "NC", Country: "US"}},
+    {in: "Zürich, Switzerland", want: Location{Kind: PlaceLocation, Name: "Zürich", Country: "CH"}},
+    {in: "London, UK", want: Location{Kind: PlaceLocation, Name: "London", Country: "GB"}},
+    {in: "Berlin, Germany", want: Location{Kind: PlaceLocation, Name: "Berlin", Country: "DE"}},
+    // Two letters are a state first: this is Berlin, Delaware
+    {in: "Berlin, DE", want: Location{Kind: PlaceLocation, Name: "Berlin", Region: "DE", Country: "US"}},
+    {in: "40°48'N, 96°42'W", want: Location{Kind: CoordsLocation, Lat: 40.8, Lon: -96.7}},
+    {in: "40.8, -96.7", want: Location{Kind: CoordsLocation, Lat: 40.8, Lon: -96.7}},
+    {in: "u4pruyd", want: Location{Kind: GeohashLocation, Code: "u4pruyd", Lat: 57.649, Lon: 10.407}},
+    {in: "geo:u4pruydqqvj", want: Location{Kind: GeohashLocation, Code: "u4pruydqqvj", Lat: 57.649, Lon: 10.407}},
+    {in: "pws:KNYLONGE2", want: Location{Kind: PWSLocation, Code: "KNYLONGE2"}},
+    {in: "KNYLONGE2", want: Location{Kind: PWSLocation, Code: "KNYLONGE2"}},
+    {in: "KLNK", want: Location{Kind: ICAOLocation, Code: "KLNK"}},
+    {in: "68508", want: Location{Kind: PostalLocation, Postal: "68508", Country: "US"}},
+    {in: "K1A 0B1", want: Location{Kind: PostalLocation, Postal: "K1A 0B1", Country: "CA"}},
+    {in: "SW1A 1AA", want: Location{Kind: PostalLocation, Postal: "SW1A 1AA", Country: "GB"}},
+    {in: "8001, Switzerland", want: Location{Kind: PostalLocation, Postal: "8001", Country: "CH"}},
+    {in: "autoip", want: Location{Kind: AutoIPLocation}},
+    {in: "zmw:00000.1.10400", want: Location{Kind: ZMWLocation, Code: "00000.1.10400"}},
+    {in: "8001", err: "ambiguous"},
+    {in: "Lnk", err: "ambiguous"},
+    {in: "Lincoln", err: "ambiguous"},
+    {in: "Springfield, Narnia, Atlantis", err: "unknown country"},
+  }
+  for _, test := range tests {
+    got, err := ParseLocation(test.in)
+    if test.err != "" {
+      if err == nil || !strings.Contains(err.Error(), test.err) {
+        t.Errorf("%q: got %+v, %v; want an error about %q", test.in, got, err, test.err)
+      }
+      continue
+    }
+    if err != nil {
+      t.Errorf("%q: %v", test.in, err)
+      continue
+    }
+    // Coordinates need only be close
+    if math.Abs(got.Lat-test.want.Lat) > 0.05 || math.Abs(got.Lon-test.want.Lon) > 0.05 {
+      t.Errorf("%q: got %.3f, %.3f; want %.3f, %.3f", test.in, got.Lat, got.Lon, test.want.Lat, test.want.Lon)
+    }
+    got.Lat, got.Lon = test.want.Lat, test.want.Lon
+    if got != test.want {
+      t.Errorf("%q: got %+v, want %+v", test.in, got, test.want)
+    }
+  }
+}
+
+func TestLocationQuery(t *testing.T) {
+  tests := []struct {
+    in   string
+    want string
+    err  string // part of the error, if one is expected
+  }{
+    {in: "St. Louis, MO", want: "MO/St_Louis"},
+    {in: "Zürich, Switzerland", want: "Switzerland/Zürich"},
+    {in: "KLNK", want: "KLNK"},
+    {in: "pws:KNYLONGE2", want: "pws:KNYLONGE2"},
+    {in: "K1A 0B1", want: "K1A0B1"},
+    {in: "40.8, -96.7", want: "40.8,-96.7"},
+    {in: "AUTOIP", want: "autoip"},
+    {in: "zmw:00000.1.10400", want: "zmw:00000.1.10400"},
+    // Without a state, a US place is found by its coordinates...
+    {in: "Lincoln, United States", want: "40.8136,-96.7026"},
+    // ...if the gazetteer knows just one
+    {in: "Springfield, United States", err: "add the state"},
+    {in: "Moncton, Canada", err: "add the province"},
+    {in: "SW1A 1AA", err: "only US and Canadian postal codes"},
+  }
+  for _, test := range tests {
+    loc, err := ParseLocation(test.in)
+    if err != nil {
+      t.Errorf("%q: %v", test.in, err)
+      continue
+    }
+    got, err := loc.Query()
+    if test.err != "" {
+      if err == nil || !strings.Contains(err.Error(), test.err) {
+        t.Errorf("%q: got %q, %v; want an error about %q", test.in, got, err, test.err)
+      }
+      continue
+    }
+    if err != nil || got != test.want {
+      t.Errorf("%q: got %q, %v; want %q", test.in, got, err, test.want)
+    }
+  }
+}
@@ -7,7 +7,7 @@
 * Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
 * and Anthony Starks.
 *
-* Last Modified: Wed Dec 18 16:11:47 CST 2013
+* Last Modified: Mon Oct 19 02:00:41 CDT 2026
 *
 * Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
 *
@@ -28,37 +28,224 @@
 
 package main
 
-import "fmt"
+import (
+  "bufio"
+  "fmt"
+  "math"
+  "os"
+  "sort"
+  "strconv"
+  "strings"
+)
+
+// lookupRadius is how far from a place (in km) to look for stations,
+// unless --radius says otherwise
+const lookupRadius = 150
 
 type Lookup struct {
   Location SLocation
+  Stations []Station // nearby stations of both kinds, nearest first
 }
 
 type SLocation struct {
+  Lat                     Number
+  Lon                     Number
   Nearby_weather_stations Nearby_weather_stations
 }
 
 type Nearby_weather_stations struct {
   Airport Airport
+  Pws     Pws
 }
 
 type Airport struct {
   Station []Station
 }
 
+// Pws lists personal weather stations
+type Pws struct {
+  Station []Station
+}
+
+// Station is an airport (Icao) or personal weather station (Id)
 type Station struct {
-  City string
-  Icao string
+  Id           string
+  Icao         string
+  Neighborhood string
+  City         string
+  State        string
+  Country      string
+  Lat          Number
+  Lon          Number
+  Elevation    Number // meters
+  Distance     Number // km from the place looked up
+  Bearing      Number // degrees from the place looked up
+}
+
+// Code returns the station's ICAO code or PWS ID, either of which
+// can be given to -s
+func (s Station) Code() string {
+  if s.Icao != "" {
+    return s.Icao
+  }
+  return s.Id
+}
+
+// Place describes where the station is, e.g. "Near South, Lincoln, NE"
+func (s Station) Place() string {
+  var parts []string
+  for _, p := range []string{s.Neighborhood, s.City, s.State} {
+    if p = strings.TrimSpace(p); p != "" {
+      parts = append(parts, p)
+    }
+  }
+  if s.State == "" && s.Country != "" {
+    parts = append(parts, s.Country)
+  }
+  return strings.Join(parts, ", ")
 }
 
+const lookupTemplate = `{{with .Stations -}}
+{{range $i, $s := .}}{{printf "%2.0f" (add $i 1)}}. {{pad 11 .Code}} {{pad 30 .Place}} {{if celsius}}{{printf "%6s km" (fixed 1 .Distance)}}{{else}}{{printf "%6s mi" (fixed 1 (kmtomi .Distance))}}{{end}} {{pad 3 (compass .Bearing)}}  {{fixed 3 .Lat}}, {{fixed 3 .Lon}}{{if .Elevation.Valid}}  {{if celsius}}{{fixed 0 .Elevation}} m{{else}}{{fixed 0 (mtoft .Elevation)}} ft{{end}}{{end}}
+{{end}}{{else}}{{T "No area stations"}}
+{{end}}`
+
 // printLookup prints nearby stations
 func PrintLookup(obs *Lookup) {
-  station := obs.Location.Nearby_weather_stations.Airport.Station
-  if len(station) == 0 {
-    fmt.Println("No area stations")
-  } else {
-    for _, s := range station {
-      fmt.Printf("%s: %s\n", s.City, s.Icao)
+  Render("lookup", "", conf.Degrees, obs)
+}
+
+// Nearby fills in Stations with the airports and personal weather
+// stations within radius km of a point (adding the airports in the
+// gazetteer to those from the API), nearest first, up to limit
+// stations (if limit > 0)
+func (l *Lookup) Nearby(lat float64, lon float64, radius float64, limit int) {
+  var all []Station
+  seen := map[string]bool{}
+  for _, s := range l.Location.Nearby_weather_stations.Airport.Station {
+    if s.Icao == "" || seen[s.Icao] {
+      continue
+    }
+    seen[s.Icao] = true
+    if p, ok := FindAirport(s.Icao); ok {
+      s.Elevation = Number(p.Elevation)
+    } else {
+      s.Elevation = Number(math.NaN())
+    }
+    all = append(all, s)
+  }
+  for _, p := range NearestAirports(lat, lon, radius) {
+    if !seen[p.ICAO] {
+      seen[p.ICAO] = true
+      s := Station{Icao: p.ICAO, City: p.City, Country: p.Country,
+        Lat: Number(p.Lat), Lon: Number(p.Lon), Elevation: Number(p.Elevation)}
+      if p.Country == "US" || p.Country == "CA" {
+        s.State = p.Admin
+      }
+      all = append(all, s)
+    }
+  }
+  for _, s := range l.Location.Nearby_weather_stations.Pws.Station {
+    s.Elevation = Number(math.NaN())
+    all = append(all, s)
+  }
+
+  l.Stations = nil
+  for _, s := range all {
+    if !s.Lat.Valid() || !s.Lon.Valid() {
+      continue
+    }
+    s.Distance = Number(Distance(lat, lon, float64(s.Lat), float64(s.Lon)))
+    s.Bearing = Number(Bearing(lat, lon, float64(s.Lat), float64(s.Lon)))
+    if float64(s.Distance) <= radius {
+      l.Stations = append(l.Stations, s)
+    }
+  }
+  sort.SliceStable(l.Stations, func(i, j int) bool { return l.Stations[i].Distance < l.Stations[j].Distance })
+  if limit > 0 && len(l.Stations) > limit {
+    l.Stations = l.Stations[:limit]
+  }
+}
+
+// LookupStations lists the stations near a place.  The airports come
+// from the gazetteer, so for the places it has this works offline;
+// personal weather stations (and any places or airports the
+// gazetteer lacks) come from the API when it can be reached.
+func LookupStations(place string) {
+  loc, err := LocateStation(place)
+  if err != nil {
+    fmt.Fprintln(os.Stderr, err)
+    os.Exit(1)
+  }
+  radius, err := parseRadius(lookupRadiusFlag)
+  CheckError(err)
+
+  var l Lookup
+  if conf.Key != "" {
+    if query, err := loc.Query(); err == nil {
+      if err := Get("geolookup", query, &l); err != nil {
+        fmt.Fprintf(os.Stderr, "Listing airports only (the API couldn't be reached: %v)\n", err)
+      }
+    }
+  }
+  lat, lon := float64(l.Location.Lat), float64(l.Location.Lon)
+  if p, err := Geocode(loc); err == nil {
+    lat, lon = p.Lat, p.Lon
+  } else if !l.Location.Lat.Valid() || !l.Location.Lon.Valid() || (lat == 0 && lon == 0) {
+    fmt.Fprintln(os.Stderr, err)
+    os.Exit(1)
+  }
+  l.Nearby(lat, lon, radius, lookupLimit)
+  PrintLookup(&l)
+  if lookupPick && len(l.Stations) > 0 {
+    PickStation(l.Stations)
+  }
+}
+
+// parseRadius reads a --radius such as "50km" or "30mi"; a bare
+// number is in miles, or kilometers if degrees are Celsius.  It
+// returns kilometers.
+func parseRadius(radius string) (float64, error) {
+  if radius == "" {
+    return lookupRadius, nil
+  }
+  scale := 1.609344
+  if conf.Degrees == "C" {
+    scale = 1
+  }
+  if strings.HasSuffix(radius, "km") {
+    radius, scale = strings.TrimSuffix(radius, "km"), 1
+  } else if strings.HasSuffix(radius, "mi") {
+    radius, scale = strings.TrimSuffix(radius, "mi"), 1.609344
+  }
+  r, err := strconv.ParseFloat(strings.TrimSpace(radius), 64)
+  if err != nil || r <= 0 {
+    return 0, fmt.Errorf("--radius should be a distance such as 50km or 30mi")
+  }
+  return r * scale, nil
+}
+
+// PickStation asks which of the stations listed to save as the
+// default station
+func PickStation(stations []Station) {
+  in := bufio.NewReader(os.Stdin)
+  for {
+    fmt.Printf("Save which station as your default (1-%d, or Enter for none)? ", len(stations))
+    line, err := in.ReadString('\n')
+    line = strings.TrimSpace(line)
+    if line == "" {
+      if err != nil {
+        fmt.Println()
+      }
+      return
+    }
+    n, err := strconv.Atoi(line)
+    if err != nil || n < 1 || n > len(stations) {
+      continue
     }
+    path, err := SaveSetting("station", stations[n-1].Code())
+    CheckError(err)
+    fmt.Printf("%s: station set to %q\n", path, stations[n-1].Code())
+    return
   }
 }
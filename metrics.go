@@ -0,0 +1,123 @@
+/*
+* metrics.go
+*
+* This file is part of wu.  It contains functions related to
+* the Prometheus metrics endpoint (wu serve --metrics).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 00:11:27 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "io"
+  "net/http"
+  "strconv"
+  "strings"
+)
+
+// gauges are the current-conditions metrics, in Prometheus' base units
+var gauges = []struct {
+  name  string
+  help  string
+  value func(c *Current) Number
+}{
+  {"wu_temperature_celsius", "Air temperature.",
+    func(c *Current) Number { return c.Temp_c }},
+  {"wu_dewpoint_celsius", "Dew point.",
+    func(c *Current) Number { return c.Dewpoint_c }},
+  {"wu_relative_humidity_percent", "Relative humidity.",
+    func(c *Current) Number { return ParseNumber(c.Relative_humidity) }},
+  {"wu_pressure_hectopascals", "Barometric pressure.",
+    func(c *Current) Number { return ParseNumber(c.Pressure_mb) }},
+  {"wu_wind_speed_meters_per_second", "Wind speed.",
+    func(c *Current) Number { return c.Wind_kph / 3.6 }},
+  {"wu_wind_gust_meters_per_second", "Wind gust speed.",
+    func(c *Current) Number { return c.Wind_gust_kph / 3.6 }},
+  {"wu_wind_direction_degrees", "Wind direction.",
+    func(c *Current) Number { return c.Wind_degrees }},
+  {"wu_visibility_meters", "Visibility.",
+    func(c *Current) Number { return ParseNumber(c.Visibility_km) * 1000 }},
+  {"wu_precip_today_millimeters", "Precipitation so far today.",
+    func(c *Current) Number { return c.Precip_today_metric }},
+}
+
+func (s *server) serveMetrics(w http.ResponseWriter, r *http.Request) {
+  w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+  s.writeMetrics(w)
+}
+
+// writeMetrics writes the metrics in the Prometheus text format
+func (s *server) writeMetrics(w io.Writer) {
+  s.mu.Lock()
+  for _, g := range gauges {
+    fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", g.name, g.help, g.name)
+    for _, station := range s.stations {
+      if c, ok := s.current[station]; ok {
+        if v := g.value(c); v.Valid() {
+          fmt.Fprintf(w, "%s{station=%s} %s\n", g.name, label(station), formatFloat(float64(v)))
+        }
+      }
+    }
+  }
+  fmt.Fprintf(w, "# HELP wu_last_refresh_timestamp_seconds When the conditions were last fetched.\n")
+  fmt.Fprintf(w, "# TYPE wu_last_refresh_timestamp_seconds gauge\n")
+  for _, station := range s.stations {
+    if t, ok := s.updated[station]; ok {
+      fmt.Fprintf(w, "wu_last_refresh_timestamp_seconds{station=%s} %d\n", label(station), t.Unix())
+    }
+  }
+  s.mu.Unlock()
+
+  endpoints, stats, today := s.cache.Stats()
+  fmt.Fprintf(w, "# HELP wu_fetch_requests_total API requests made, by endpoint.\n")
+  fmt.Fprintf(w, "# TYPE wu_fetch_requests_total counter\n")
+  for i, e := range endpoints {
+    fmt.Fprintf(w, "wu_fetch_requests_total{endpoint=%s} %d\n", label(e), stats[i].Requests)
+  }
+  fmt.Fprintf(w, "# HELP wu_fetch_errors_total Failed API requests, by endpoint.\n")
+  fmt.Fprintf(w, "# TYPE wu_fetch_errors_total counter\n")
+  for i, e := range endpoints {
+    fmt.Fprintf(w, "wu_fetch_errors_total{endpoint=%s} %d\n", label(e), stats[i].Errors)
+  }
+  fmt.Fprintf(w, "# HELP wu_fetch_duration_seconds Latency of the most recent successful API request, by endpoint.\n")
+  fmt.Fprintf(w, "# TYPE wu_fetch_duration_seconds gauge\n")
+  for i, e := range endpoints {
+    fmt.Fprintf(w, "wu_fetch_duration_seconds{endpoint=%s} %s\n", label(e), formatFloat(stats[i].LastLatency.Seconds()))
+  }
+  fmt.Fprintf(w, "# HELP wu_api_quota_used API calls made today.\n")
+  fmt.Fprintf(w, "# TYPE wu_api_quota_used gauge\n")
+  fmt.Fprintf(w, "wu_api_quota_used %d\n", today)
+  fmt.Fprintf(w, "# HELP wu_api_quota_limit API calls allowed per day.\n")
+  fmt.Fprintf(w, "# TYPE wu_api_quota_limit gauge\n")
+  fmt.Fprintf(w, "wu_api_quota_limit %d\n", callsPerDay)
+}
+
+// label quotes a Prometheus label value
+func label(v string) string {
+  return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v) + `"`
+}
+
+func formatFloat(f float64) string {
+  return strconv.FormatFloat(f, 'g', -1, 64)
+}
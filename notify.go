@@ -0,0 +1,198 @@
+/*
+* notify.go
+*
+* This file is part of wu.  It contains functions for sending
+* notifications (alerts, threshold crossings) to webhooks.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 01:35:09 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "encoding/json"
+  "fmt"
+  "net/http"
+  "os"
+  "path/filepath"
+  "text/template"
+  "time"
+)
+
+// Webhook is a notification sink from the "webhooks" list in .condrc:
+//
+//   "webhooks": [
+//     {"url": "https://hooks.slack.com/services/...", "format": "slack"},
+//     {"url": "http://localhost:9000/wu", "template": "{{.Title}}"}
+//   ]
+//
+// Format is "json" (the default), "slack", which also suits
+// Mattermost and other Slack-compatible services, or "discord".
+// Template, if given, is a text/template used for the message text;
+// it is executed on the Notification, so it can refer to .Alert and
+// .Current.  Retries defaults to 3; "retries": 0 sends only once.
+type Webhook struct {
+  Url      string
+  Format   string
+  Template string
+  Retries  *int
+}
+
+// Notification is the data sent to webhooks
+type Notification struct {
+  Event   string   `json:"event"`
+  Station string   `json:"station"`
+  Title   string   `json:"title"`
+  Text    string   `json:"text"`
+  Time    string   `json:"time"`
+  Alert   *Alerts  `json:"alert,omitempty"`
+  Current *Current `json:"current,omitempty"`
+}
+
+const defaultRetries = 3
+
+var (
+  notifyClient = &http.Client{Timeout: 15 * time.Second}
+  retryWait    = time.Second // the first backoff; it doubles after each try
+)
+
+// webhookFormats are the payload formats a webhook may ask for
+var webhookFormats = map[string]bool{
+  "": true, "json": true, "slack": true, "mattermost": true, "discord": true,
+}
+
+// AlertNotification builds the notification for an alert change
+func AlertNotification(e AlertEvent) Notification {
+  alert := e.Alert
+  return Notification{
+    Event:   "alert." + e.Kind,
+    Station: e.Station,
+    Title:   fmt.Sprintf("%s (%s)", alert.Description, e.Kind),
+    Text:    fmt.Sprintf("Issued at %s\nExpires at %s\n%s", alert.Date, alert.Expires, alert.Message),
+    Time:    time.Now().Format(time.RFC3339),
+    Alert:   &alert,
+  }
+}
+
+// Notify sends a notification to every configured webhook.  Failed
+// deliveries are written to the dead-letter file rather than
+// stopping wu.  A webhook whose payload can't be made (a bad format
+// or template) is reported and skipped, since there is nothing to
+// resend.
+func Notify(n Notification) {
+  for _, hook := range conf.Webhooks {
+    body, err := hook.Payload(n)
+    if err != nil {
+      fmt.Fprintf(os.Stderr, "Notification to %s not sent: %v\n", hook.Url, err)
+      continue
+    }
+    if err := deliver(hook.Url, body, hook.retries()); err != nil {
+      fmt.Fprintf(os.Stderr, "Notification to %s failed: %v\n", hook.Url, err)
+      deadLetter(hook.Url, body, err)
+    }
+  }
+}
+
+func (hook Webhook) retries() int {
+  switch {
+  case hook.Retries == nil:
+    return defaultRetries
+  case *hook.Retries < 0:
+    return 0
+  }
+  return *hook.Retries
+}
+
+// Payload formats a notification for this webhook
+func (hook Webhook) Payload(n Notification) ([]byte, error) {
+  if !webhookFormats[hook.Format] {
+    return nil, fmt.Errorf("unknown webhook format %q", hook.Format)
+  }
+  text := n.Title + "\n" + n.Text
+  if hook.Template != "" {
+    t, err := template.New(hook.Url).Parse(hook.Template)
+    if err != nil {
+      return nil, err
+    }
+    var b bytes.Buffer
+    if err := t.Execute(&b, n); err != nil {
+      return nil, err
+    }
+    text = b.String()
+  }
+
+  switch hook.Format {
+  case "slack", "mattermost":
+    return json.Marshal(map[string]string{"username": "wu", "text": text})
+  case "discord":
+    return json.Marshal(map[string]string{"username": "wu", "content": text})
+  }
+  n.Text = text
+  return json.Marshal(n)
+}
+
+// deliver POSTs a payload, retrying with exponential backoff on
+// network errors and 5xx responses
+func deliver(url string, body []byte, retries int) error {
+  var err error
+  wait := retryWait
+  for attempt := 0; attempt <= retries; attempt++ {
+    if attempt > 0 {
+      time.Sleep(wait)
+      wait *= 2
+    }
+    var res *http.Response
+    res, err = notifyClient.Post(url, "application/json", bytes.NewReader(body))
+    if err != nil {
+      continue
+    }
+    res.Body.Close()
+    if res.StatusCode < 300 {
+      return nil
+    }
+    err = fmt.Errorf("Bad HTTP Status: %d", res.StatusCode)
+    if res.StatusCode < 500 {
+      return err // the request itself is wrong; retrying won't help
+    }
+  }
+  return err
+}
+
+// deadLetter appends an undeliverable payload to
+// $HOME/.wu/deadletter.jsonl so that it can be inspected or resent
+func deadLetter(url string, body []byte, cause error) {
+  record, _ := json.Marshal(map[string]interface{}{
+    "time":    time.Now().Format(time.RFC3339),
+    "url":     url,
+    "error":   cause.Error(),
+    "payload": string(body),
+  })
+  f, err := os.OpenFile(filepath.Join(StateDir(), "deadletter.jsonl"),
+    os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+  if err != nil {
+    fmt.Fprintf(os.Stderr, "Could not write dead letter: %v\n", err)
+    return
+  }
+  defer f.Close()
+  f.Write(append(record, '\n'))
+}
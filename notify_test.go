@@ -0,0 +1,188 @@
+/*
+* notify_test.go
+*
+* This file is part of wu.  It contains tests for webhook
+* notifications.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 01:58:12 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "encoding/json"
+  "io/ioutil"
+  "net/http"
+  "net/http/httptest"
+  "os"
+  "path/filepath"
+  "strings"
+  "sync"
+  "testing"
+)
+
+// hookServer is a webhook endpoint that fails the first n requests
+// with status and records every body it receives
+type hookServer struct {
+  *httptest.Server
+  mu     sync.Mutex
+  bodies []string
+}
+
+func newHookServer(failures, status int) *hookServer {
+  h := &hookServer{}
+  h.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+    body, _ := ioutil.ReadAll(r.Body)
+    h.mu.Lock()
+    h.bodies = append(h.bodies, string(body))
+    n := len(h.bodies)
+    h.mu.Unlock()
+    if n <= failures {
+      w.WriteHeader(status)
+    }
+  }))
+  return h
+}
+
+// notifyTest points StateDir at a temporary $HOME, makes retries
+// fast, quiets the failure messages and sets the webhooks for one
+// test
+func notifyTest(t *testing.T, hooks ...Webhook) string {
+  home := t.TempDir()
+  t.Setenv("HOME", home)
+  wait := retryWait
+  retryWait = 0
+  saved := conf.Webhooks
+  conf.Webhooks = hooks
+  stderr := os.Stderr
+  os.Stderr, _ = os.Open(os.DevNull)
+  t.Cleanup(func() {
+    retryWait = wait
+    conf.Webhooks = saved
+    os.Stderr.Close()
+    os.Stderr = stderr
+  })
+  return filepath.Join(home, ".wu", "deadletter.jsonl")
+}
+
+func retries(n int) *int {
+  return &n
+}
+
+var testNotification = Notification{
+  Event:   "alert.new",
+  Station: "KLNK",
+  Title:   "Winter Storm Warning (new)",
+  Text:    "Expires at 6:00 PM CST",
+}
+
+func TestPayloads(t *testing.T) {
+  tests := []struct {
+    format   string
+    template string
+    field    string
+    want     string
+  }{
+    {"", "", "text", "Winter Storm Warning (new)\nExpires at 6:00 PM CST"},
+    {"json", "", "event", "alert.new"},
+    {"json", "{{.Station}}: {{.Title}}", "text", "KLNK: Winter Storm Warning (new)"},
+    {"slack", "", "text", "Winter Storm Warning (new)\nExpires at 6:00 PM CST"},
+    {"mattermost", "{{.Title}}", "text", "Winter Storm Warning (new)"},
+    {"discord", "{{.Title}}", "content", "Winter Storm Warning (new)"},
+  }
+  for _, test := range tests {
+    h := newHookServer(0, 0)
+    notifyTest(t, Webhook{Url: h.URL, Format: test.format, Template: test.template})
+    Notify(testNotification)
+    h.Close()
+    if len(h.bodies) != 1 {
+      t.Errorf("%q: %d requests, want 1", test.format, len(h.bodies))
+      continue
+    }
+    var payload map[string]interface{}
+    if err := json.Unmarshal([]byte(h.bodies[0]), &payload); err != nil {
+      t.Errorf("%q: bad JSON %q: %v", test.format, h.bodies[0], err)
+      continue
+    }
+    if got := payload[test.field]; got != test.want {
+      t.Errorf("%q: %s = %q, want %q", test.format, test.field, got, test.want)
+    }
+  }
+}
+
+func TestDelivery(t *testing.T) {
+  tests := []struct {
+    name     string
+    retries  *int
+    failures int
+    status   int
+    requests int
+    dead     bool
+  }{
+    {"ok", nil, 0, 0, 1, false},
+    {"recovers", nil, 2, 503, 3, false},
+    {"gives up", nil, 10, 500, 4, true},
+    {"one retry", retries(1), 10, 502, 2, true},
+    {"no retries", retries(0), 10, 500, 1, true},
+    {"client error", nil, 10, 404, 1, true},
+  }
+  for _, test := range tests {
+    h := newHookServer(test.failures, test.status)
+    deadletters := notifyTest(t, Webhook{Url: h.URL, Retries: test.retries})
+    Notify(testNotification)
+    h.Close()
+    if len(h.bodies) != test.requests {
+      t.Errorf("%s: %d requests, want %d", test.name, len(h.bodies), test.requests)
+    }
+    record, err := ioutil.ReadFile(deadletters)
+    if !test.dead {
+      if err == nil {
+        t.Errorf("%s: unexpected dead letter %s", test.name, record)
+      }
+      continue
+    }
+    var dead struct{ Url, Error, Payload string }
+    if err := json.Unmarshal(record, &dead); err != nil {
+      t.Errorf("%s: bad dead letter %q: %v", test.name, record, err)
+      continue
+    }
+    if dead.Url != h.URL || dead.Payload != h.bodies[0] || dead.Error == "" {
+      t.Errorf("%s: dead letter %+v doesn't match the request", test.name, dead)
+    }
+  }
+}
+
+func TestUnknownFormat(t *testing.T) {
+  h := newHookServer(0, 0)
+  defer h.Close()
+  deadletters := notifyTest(t, Webhook{Url: h.URL, Format: "teams"})
+  Notify(testNotification)
+  if len(h.bodies) != 0 {
+    t.Errorf("sent %d requests for an unknown format", len(h.bodies))
+  }
+  if _, err := os.Stat(deadletters); err == nil {
+    t.Error("wrote a dead letter for an unknown format")
+  }
+  if problems := ValidateConfig(&Config{Webhooks: conf.Webhooks}); !strings.Contains(strings.Join(problems, "\n"), "teams") {
+    t.Errorf("config validation missed the format: %v", problems)
+  }
+}
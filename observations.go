@@ -0,0 +1,103 @@
+/*
+* observations.go
+*
+* This file is part of wu.  It contains the store of past
+* observations that wu keeps in $HOME/.wu.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 00:39:17 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "encoding/json"
+  "io/ioutil"
+  "sort"
+  "time"
+)
+
+// observationsKept is how long observations are kept in the store
+const observationsKept = 10 * 24 * time.Hour
+
+// Observation is a reading kept in the store
+type Observation struct {
+  Time         time.Time
+  Pressure_mb  Number
+  Temp_c       Number
+  Dewpoint_c   Number
+  Humidity     Number
+  Wind_kph     Number
+  Wind_degrees Number
+}
+
+// LoadObservations returns the stored observations for a station,
+// oldest first
+func LoadObservations(station string) []Observation {
+  var obs []Observation
+  if b, err := ioutil.ReadFile(StateFile("observations", station)); err == nil {
+    json.Unmarshal(b, &obs)
+  }
+  sort.Slice(obs, func(i, j int) bool { return obs[i].Time.Before(obs[j].Time) })
+  return obs
+}
+
+// RecordObservation adds the current conditions to a station's store
+// (unless they are already there), drops observations that are too
+// old to be useful and returns the rest
+func RecordObservation(station string, c *Current) []Observation {
+  now := time.Now()
+  t, td, rh, wind := c.readings()
+  o := Observation{
+    Time:         observedAt(c, now),
+    Pressure_mb:  ParseNumber(c.Pressure_mb),
+    Temp_c:       Number(t),
+    Dewpoint_c:   Number(td),
+    Humidity:     Number(rh),
+    Wind_kph:     Number(wind),
+    Wind_degrees: c.Wind_degrees,
+  }
+
+  var kept []Observation
+  seen := false
+  for _, old := range LoadObservations(station) {
+    if now.Sub(old.Time) > observationsKept {
+      continue
+    }
+    seen = seen || old.Time.Equal(o.Time)
+    kept = append(kept, old)
+  }
+  if !seen {
+    kept = append(kept, o)
+  }
+
+  if b, err := json.Marshal(kept); err == nil {
+    ioutil.WriteFile(StateFile("observations", station), b, 0600)
+  }
+  return kept
+}
+
+// NoteObservation records the current conditions and works out the
+// pressure tendency from the observations stored so far
+func NoteObservation(station string, c *Current) {
+  obs := RecordObservation(station, c)
+  c.Tendency = PressureTendency(obs, float64(c.Wind_degrees), float64(c.Observation_location.Latitude))
+}
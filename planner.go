@@ -7,7 +7,7 @@
 * Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
 * and Anthony Starks.
 *
-* Last Modified: Mon Aug 01 12:49:01 CDT 2016
+* Last Modified: Mon Oct 19 00:17:12 CDT 2026
 *
 * Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
 *
@@ -173,6 +173,38 @@ type Chanceofsnowday struct {
   Percentage  string
 }
 
+const plannerTemplate = `{{.Trip.Title}}
+{{T "Station"}}: {{.Trip.Airport_code}}
+{{T "Chance of"}}: 
+   {{T "Temps"}}:
+{{with .Trip.Chance_of -}}
+{{if celsius}}      {{T "Over 32 C (90 F)"}}: {{.Tempoverninety.Percentage}}%
+      {{T "Between 15 C (60 F) and 32 C (90 F)"}}: {{.Tempoversixty.Percentage}}%
+      {{T "Between 0 C (32 F) and 16 C (60 F)"}}: {{.Tempoversixty.Percentage}}%
+      {{T "Below 0 F (32 C)"}}: {{.Tempbelowfreezing.Percentage}}%
+   {{T "Dewpoint above 21 C (70 F)"}}: {{.Chanceofsultryday.Percentage}}%
+   {{T "Dewpoint above 15 C (60 F)"}}: {{.Chanceofhumidday.Percentage}}%
+   {{T "Winds over 15 km/h (10 mph)"}}: {{.Chanceofwindyday.Percentage}}%
+{{else}}      {{T "Over 90 F (32 C)"}}: {{.Tempoverninety.Percentage}}%
+      {{T "Between 60 F (15 C) and 90 F (32 C)"}}: {{.Tempoversixty.Percentage}}%
+      {{T "Between 32 F (0 C) and 60 F (16 C)"}}: {{.Tempoversixty.Percentage}}%
+      {{T "Below 32 F (0 C)"}}: {{.Tempbelowfreezing.Percentage}}%
+   {{T "Dewpoint above 70 F (21 C)"}}: {{.Chanceofsultryday.Percentage}}%
+   {{T "Dewpoint above 60 F (15 C)"}}: {{.Chanceofhumidday.Percentage}}%
+   {{T "Winds over 10 mph (15 km/h)"}}: {{.Chanceofwindyday.Percentage}}%
+{{end}}   {{printf (T "%s day") .Chanceofsunnycloudyday.Name}}: {{.Chanceofsunnycloudyday.Percentage}}%
+   {{printf (T "%s day") .Chanceofcloudyday.Name}}: {{.Chanceofcloudyday.Percentage}}%
+   {{printf (T "%s day") .Chanceofpartlycloudyday.Name}}: {{.Chanceofpartlycloudyday.Percentage}}%
+   {{.Chanceofprecip.Name}}: {{.Chanceofprecip.Percentage}}%
+   {{.Chanceoffogday.Name}}: {{.Chanceoffogday.Percentage}}%
+   {{.Chanceofrainday.Name}}: {{.Chanceofrainday.Percentage}}%
+   {{.Chanceofthunderday.Name}}: {{.Chanceofthunderday.Percentage}}%
+   {{.Chanceoftornadoday.Name}}: {{.Chanceoftornadoday.Percentage}}%
+   {{.Chanceofhailday.Name}}: {{.Chanceofhailday.Percentage}}%
+   {{.Chanceofsnowday.Name}}: {{.Chanceofsnowday.Percentage}}%
+   {{.Chanceofsnowonground.Name}}: {{.Chanceofsnowonground.Percentage}}%
+{{end}}`
+
 func PrintPlanner(obs *PlannerConditions, stationId string, degrees string) {
 
   if obs.Trip.Error != "" {
@@ -180,37 +212,5 @@ func PrintPlanner(obs *PlannerConditions, stationId string, degrees string) {
     os.Exit(0)
   }
 
-  planner := obs.Trip.Chance_of
-  fmt.Println(obs.Trip.Title)
-  fmt.Println("Station: " + obs.Trip.Airport_code)
-  fmt.Println("Chance of: ")
-  fmt.Println("   Temps:")
-	if (degrees == "C") {
-		fmt.Printf("      Over 32 C (90 F): %s%%\n", planner.Tempoverninety.Percentage)
-		fmt.Printf("      Between 15 C (60 F) and 32 C (90 F): %s%%\n", planner.Tempoversixty.Percentage)
-		fmt.Printf("      Between 0 C (32 F) and 16 C (60 F): %s%%\n", planner.Tempoversixty.Percentage)
-		fmt.Printf("      Below 0 F (32 C): %s%%\n", planner.Tempbelowfreezing.Percentage)
-		fmt.Printf("   Dewpoint above 21 C (70 F): %s%%\n", planner.Chanceofsultryday.Percentage)
-		fmt.Printf("   Dewpoint above 15 C (60 F): %s%%\n", planner.Chanceofhumidday.Percentage)
-	fmt.Printf("   Winds over 15 km/h (10 mph): %s%%\n", planner.Chanceofwindyday.Percentage)
-	} else {
-		fmt.Printf("      Over 90 F (32 C): %s%%\n", planner.Tempoverninety.Percentage)
-		fmt.Printf("      Between 60 F (15 C) and 90 F (32 C): %s%%\n", planner.Tempoversixty.Percentage)
-		fmt.Printf("      Between 32 F (0 C) and 60 F (16 C): %s%%\n", planner.Tempoversixty.Percentage)
-		fmt.Printf("      Below 32 F (0 C): %s%%\n", planner.Tempbelowfreezing.Percentage)
-		fmt.Printf("   Dewpoint above 70 F (21 C): %s%%\n", planner.Chanceofsultryday.Percentage)
-		fmt.Printf("   Dewpoint above 60 F (15 C): %s%%\n", planner.Chanceofhumidday.Percentage)
-	fmt.Printf("   Winds over 10 mph (15 km/h): %s%%\n", planner.Chanceofwindyday.Percentage)
-	}
-  fmt.Printf("   %s day: %s%%\n", planner.Chanceofsunnycloudyday.Name, planner.Chanceofsunnycloudyday.Percentage)
-  fmt.Printf("   %s day: %s%%\n", planner.Chanceofcloudyday.Name, planner.Chanceofcloudyday.Percentage)
-  fmt.Printf("   %s day: %s%%\n", planner.Chanceofpartlycloudyday.Name, planner.Chanceofpartlycloudyday.Percentage)
-  fmt.Printf("   %s: %s%%\n", planner.Chanceofprecip.Name, planner.Chanceofprecip.Percentage)
-  fmt.Printf("   %s: %s%%\n", planner.Chanceoffogday.Name, planner.Chanceoffogday.Percentage)
-  fmt.Printf("   %s: %s%%\n", planner.Chanceofrainday.Name, planner.Chanceofrainday.Percentage)
-  fmt.Printf("   %s: %s%%\n", planner.Chanceofthunderday.Name, planner.Chanceofthunderday.Percentage)
-  fmt.Printf("   %s: %s%%\n", planner.Chanceoftornadoday.Name, planner.Chanceoftornadoday.Percentage)
-  fmt.Printf("   %s: %s%%\n", planner.Chanceofhailday.Name, planner.Chanceofhailday.Percentage)
-  fmt.Printf("   %s: %s%%\n", planner.Chanceofsnowday.Name, planner.Chanceofsnowday.Percentage)
-  fmt.Printf("   %s: %s%%\n", planner.Chanceofsnowonground.Name, planner.Chanceofsnowonground.Percentage)
+  Render("planner", stationId, degrees, obs)
 }
@@ -0,0 +1,616 @@
+/*
+* plot.go
+*
+* This file is part of wu.  It contains functions related to
+* "wu chart", which draws charts of the archived history and the
+* stored observations as SVG or PNG images.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 02:18:37 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "encoding/json"
+  "flag"
+  "fmt"
+  "image/color"
+  "math"
+  "os"
+  "path/filepath"
+  "strings"
+  "time"
+)
+
+var (
+  inkColor      = color.RGBA{0x33, 0x33, 0x33, 255}
+  gridColor     = color.RGBA{0xdd, 0xdd, 0xdd, 255}
+  highColor     = color.RGBA{0xd6, 0x27, 0x28, 255}
+  lowColor      = color.RGBA{0x1f, 0x77, 0xb4, 255}
+  normalColor   = color.RGBA{0x80, 0x80, 0x80, 64}
+  precipColor   = color.RGBA{0x2c, 0x7f, 0xb8, 255}
+  pressureColor = color.RGBA{0x6a, 0x3d, 0x9a, 255}
+  rangeColor    = color.RGBA{0x6a, 0x3d, 0x9a, 48}
+  roseColors    = []color.RGBA{
+    {0xc6, 0xdb, 0xef, 255},
+    {0x9e, 0xca, 0xe1, 255},
+    {0x6b, 0xae, 0xd6, 255},
+    {0x31, 0x82, 0xbd, 255},
+    {0x08, 0x51, 0x9c, 255},
+  }
+)
+
+const (
+  plotWidth  = 800
+  plotHeight = 420
+  roseSize   = 520
+  fontSize   = 13
+  titleSize  = 16
+)
+
+// chartTypes are the charts "wu chart" draws
+var chartTypes = map[string]func(canvas Canvas, c chartData){
+  "temp":      tempPlot,
+  "precip":    precipPlot,
+  "pressure":  pressurePlot,
+  "wind-rose": windRose,
+}
+
+// chartData is what a chart is drawn from: the days of the range,
+// with their archived history (if any), the readings kept in that
+// time, and today's almanac
+type chartData struct {
+  station string
+  from    time.Time
+  to      time.Time
+  days    []time.Time
+  history []*Dailysummary // nil where a day isn't archived
+  obs     []Observation
+  almanac *Almanac
+  celsius bool
+}
+
+// title returns a chart's title, e.g. "Temperature at KLNK, 2025-05-01
+// to 2025-05-31"
+func (c chartData) title(format string) string {
+  return fmt.Sprintf(T(format), c.station, c.from.Format("2006-01-02"), c.to.Format("2006-01-02"))
+}
+
+// daily returns a value for each day (NaN where there's none): the
+// English or metric field of the history
+func (c chartData) daily(english func(d *Dailysummary) string, metric func(d *Dailysummary) string) []float64 {
+  values := make([]float64, len(c.days))
+  for i, d := range c.history {
+    values[i] = math.NaN()
+    if d == nil {
+      continue
+    }
+    s := english(d)
+    if c.celsius {
+      s = metric(d)
+    }
+    if s == "T" {
+      values[i] = 0
+    } else {
+      values[i] = float64(ParseNumber(s))
+    }
+  }
+  return values
+}
+
+// plot is an x/y chart: the area inside the axes and the ranges of
+// the data shown there.  The x axis counts days from the start, each
+// day centred on its number.
+type plot struct {
+  canvas                   Canvas
+  left, top, right, bottom float64
+  xmin, xmax, ymin, ymax   float64
+  ystep                    float64
+}
+
+// newPlot lays out a chart of some days, widening the y range to
+// round numbers
+func newPlot(canvas Canvas, days int, ymin float64, ymax float64) plot {
+  if math.IsInf(ymin, 0) || math.IsNaN(ymin) {
+    ymin, ymax = 0, 1
+  }
+  if ymin == ymax {
+    ymin, ymax = ymin-1, ymax+1
+  }
+  step := niceStep(ymax-ymin, 6)
+  return plot{
+    canvas: canvas,
+    left:   70, top: 50, right: plotWidth - 20, bottom: plotHeight - 50,
+    xmin: -0.5, xmax: float64(days) - 0.5,
+    ymin: math.Floor(ymin/step) * step, ymax: math.Ceil(ymax/step) * step,
+    ystep: step,
+  }
+}
+
+// niceStep returns a round step (1, 2 or 5 times a power of ten)
+// giving about n steps over a span
+func niceStep(span float64, n int) float64 {
+  raw := span / float64(n)
+  power := math.Pow(10, math.Floor(math.Log10(raw)))
+  for _, m := range []float64{1, 2, 5, 10} {
+    if raw <= m*power {
+      return m * power
+    }
+  }
+  return 10 * power
+}
+
+func (p plot) at(x float64, y float64) Point {
+  return Point{
+    p.left + (x-p.xmin)/(p.xmax-p.xmin)*(p.right-p.left),
+    p.bottom - (y-p.ymin)/(p.ymax-p.ymin)*(p.bottom-p.top),
+  }
+}
+
+// axes draws the title, the grid with the y axis labelled in a unit,
+// and the dates along the x axis
+func (p plot) axes(title string, unit string, places int, days []time.Time) {
+  p.canvas.Text(p.left, 28, title, titleSize, "start", inkColor)
+  for y := p.ymin; y <= p.ymax+p.ystep/2; y += p.ystep {
+    at := p.at(p.xmin, y)
+    p.canvas.Line([]Point{at, {p.right, at.Y}}, gridColor, 1, false)
+    p.canvas.Text(p.left-8, at.Y+4, fmt.Sprintf("%.*f", places, y), fontSize, "end", inkColor)
+  }
+  p.canvas.Text(p.left-8, p.top-10, unit, fontSize, "end", inkColor)
+
+  every := (len(days) + 9) / 10
+  for i, d := range days {
+    at := p.at(float64(i), p.ymin)
+    if i%every == 0 {
+      p.canvas.Line([]Point{at, {at.X, at.Y + 5}}, inkColor, 1, false)
+      p.canvas.Text(at.X, at.Y+20, d.Format("Jan 2"), fontSize, "middle", inkColor)
+    }
+  }
+  p.canvas.Line([]Point{{p.left, p.top}, {p.left, p.bottom}, {p.right, p.bottom}}, inkColor, 1, false)
+}
+
+// legend lists the colors of the series at the top right
+func (p plot) legend(labels []string, colors []color.RGBA) {
+  x := p.right
+  for i := len(labels) - 1; i >= 0; i-- {
+    x -= textWidth(labels[i], fontSize)
+    p.canvas.Text(x, 28, labels[i], fontSize, "start", inkColor)
+    x -= 18
+    p.canvas.Fill(box(x, 18, 12, 12), colors[i])
+    x -= 16
+  }
+}
+
+func box(x float64, y float64, w float64, h float64) []Point {
+  return []Point{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}}
+}
+
+// line draws a series, leaving gaps for missing values
+func (p plot) line(xs []float64, ys []float64, c color.RGBA) {
+  var run []Point
+  for i := range ys {
+    if math.IsNaN(ys[i]) {
+      p.canvas.Line(run, c, 2, false)
+      run = nil
+      continue
+    }
+    run = append(run, p.at(xs[i], ys[i]))
+  }
+  p.canvas.Line(run, c, 2, false)
+}
+
+// band shades between two series, leaving gaps for missing values
+func (p plot) band(xs []float64, lows []float64, highs []float64, c color.RGBA) {
+  var upper, lower []Point
+  flush := func() {
+    for i := len(lower) - 1; i >= 0; i-- {
+      upper = append(upper, lower[i])
+    }
+    p.canvas.Fill(upper, c)
+    upper, lower = nil, nil
+  }
+  for i := range xs {
+    if math.IsNaN(lows[i]) || math.IsNaN(highs[i]) {
+      flush()
+      continue
+    }
+    upper, lower = append(upper, p.at(xs[i], highs[i])), append(lower, p.at(xs[i], lows[i]))
+  }
+  flush()
+}
+
+func dayNumbers(n int) []float64 {
+  xs := make([]float64, n)
+  for i := range xs {
+    xs[i] = float64(i)
+  }
+  return xs
+}
+
+func extent(series ...[]float64) (float64, float64) {
+  lo, hi := math.Inf(1), math.Inf(-1)
+  for _, s := range series {
+    for _, v := range s {
+      if !math.IsNaN(v) {
+        lo, hi = math.Min(lo, v), math.Max(hi, v)
+      }
+    }
+  }
+  return lo, hi
+}
+
+// normalBand returns the normal low and high for each day.  The API
+// gives the almanac only for today, so its spread between the normal
+// high and low is centred on each day's normal mean (worked out from
+// the degree-day normals), where the history has one.  With none in
+// the history, there's no band.
+func (c chartData) normalBand() ([]float64, []float64) {
+  if c.almanac == nil {
+    return nil, nil
+  }
+  high := ParseNumber(c.almanac.Temp_high.Normal.F)
+  low := ParseNumber(c.almanac.Temp_low.Normal.F)
+  if !high.Valid() || !low.Valid() {
+    return nil, nil
+  }
+  lows, highs := make([]float64, len(c.days)), make([]float64, len(c.days))
+  centred := false
+  for i, d := range c.history {
+    lows[i], highs[i] = math.NaN(), math.NaN()
+    if d == nil {
+      continue
+    }
+    hdd, cdd := ParseNumber(d.Heatingdegreedaysnormal), ParseNumber(d.Coolingdegreedaysnormal)
+    if hdd.Valid() || cdd.Valid() {
+      mean := 65 - float64(zeroIfMissing(hdd)) + float64(zeroIfMissing(cdd))
+      lows[i], highs[i] = mean-float64(high-low)/2, mean+float64(high-low)/2
+      centred = true
+    }
+  }
+  if !centred {
+    // Without the history's normals there's nothing to centre it on,
+    // and today's range on every day would be misleading
+    return nil, nil
+  }
+  if c.celsius {
+    for i := range lows {
+      lows[i], highs[i] = ftoc(lows[i]), ftoc(highs[i])
+    }
+  }
+  return lows, highs
+}
+
+// tempPlot draws the daily highs and lows, over the normal range
+func tempPlot(canvas Canvas, c chartData) {
+  highs := c.daily(func(d *Dailysummary) string { return d.Maxtempi }, func(d *Dailysummary) string { return d.Maxtempm })
+  lows := c.daily(func(d *Dailysummary) string { return d.Mintempi }, func(d *Dailysummary) string { return d.Mintempm })
+  normalLows, normalHighs := c.normalBand()
+  lo, hi := extent(highs, lows, normalLows, normalHighs)
+  p := newPlot(canvas, len(c.days), lo, hi)
+  unit := "°F"
+  if c.celsius {
+    unit = "°C"
+  }
+  p.axes(c.title("Temperature at %s, %s to %s"), unit, 0, c.days)
+
+  xs := dayNumbers(len(c.days))
+  labels, colors := []string{T("High"), T("Low")}, []color.RGBA{highColor, lowColor}
+  if normalLows != nil {
+    p.band(xs, normalLows, normalHighs, normalColor)
+    labels, colors = append(labels, T("Normal range")), append(colors, normalColor)
+  }
+  p.line(xs, highs, highColor)
+  p.line(xs, lows, lowColor)
+  p.legend(labels, colors)
+}
+
+// precipPlot draws a bar for each day's precipitation
+func precipPlot(canvas Canvas, c chartData) {
+  precip := c.daily(func(d *Dailysummary) string { return d.Precipi }, func(d *Dailysummary) string { return d.Precipm })
+  _, hi := extent(precip)
+  if math.IsInf(hi, -1) || hi <= 0 {
+    hi = 1
+  }
+  p := newPlot(canvas, len(c.days), 0, hi)
+  unit, places := "in", 2
+  if c.celsius {
+    unit, places = "mm", 0
+  }
+  if p.ystep >= 1 {
+    places = 0
+  } else if p.ystep >= 0.1 {
+    places = 1
+  }
+  p.axes(c.title("Precipitation at %s, %s to %s"), unit, places, c.days)
+  for i, v := range precip {
+    if math.IsNaN(v) || v <= 0 {
+      continue
+    }
+    a, b := p.at(float64(i)-0.35, v), p.at(float64(i)+0.35, 0)
+    canvas.Fill(box(a.X, a.Y, b.X-a.X, b.Y-a.Y), precipColor)
+  }
+}
+
+// pressurePlot draws the pressure from the readings kept, or if there
+// are none for the days, each day's mean and range from the history
+func pressurePlot(canvas Canvas, c chartData) {
+  convert := func(mb float64) float64 {
+    if c.celsius {
+      return mb
+    }
+    return mb / 33.8639
+  }
+  unit, places := "in", 2
+  if c.celsius {
+    unit, places = "mb", 0
+  }
+
+  start := time.Date(c.from.Year(), c.from.Month(), c.from.Day(), 0, 0, 0, 0, time.Local)
+  var xs, ys []float64
+  for _, o := range c.obs {
+    x := o.Time.Sub(start).Hours()/24 - 0.5
+    if len(xs) > 0 && x-xs[len(xs)-1] > 0.125 {
+      // a gap of more than three hours between readings
+      xs, ys = append(xs, x), append(ys, math.NaN())
+    }
+    xs, ys = append(xs, x), append(ys, convert(float64(o.Pressure_mb)))
+  }
+  if len(xs) > 0 {
+    lo, hi := extent(ys)
+    p := newPlot(canvas, len(c.days), lo, hi)
+    p.axes(c.title("Pressure at %s, %s to %s"), unit, places, c.days)
+    p.line(xs, ys, pressureColor)
+    return
+  }
+
+  mean := c.daily(func(d *Dailysummary) string { return d.Meanpressurei }, func(d *Dailysummary) string { return d.Meanpressurem })
+  lows := c.daily(func(d *Dailysummary) string { return d.Minpressurei }, func(d *Dailysummary) string { return d.Minpressurem })
+  highs := c.daily(func(d *Dailysummary) string { return d.Maxpressurei }, func(d *Dailysummary) string { return d.Maxpressurem })
+  lo, hi := extent(mean, lows, highs)
+  p := newPlot(canvas, len(c.days), lo, hi)
+  p.axes(c.title("Pressure at %s, %s to %s"), unit, places, c.days)
+  days := dayNumbers(len(c.days))
+  p.band(days, lows, highs, rangeColor)
+  p.line(days, mean, pressureColor)
+  p.legend([]string{T("Mean"), T("Daily range")}, []color.RGBA{pressureColor, rangeColor})
+}
+
+// windSample is a wind reading for the wind rose
+type windSample struct {
+  degrees, speed float64
+}
+
+// windSamples returns the readings kept for the days, or if there
+// are none, each day's mean wind from the history
+func (c chartData) windSamples() []windSample {
+  var samples []windSample
+  for _, o := range c.obs {
+    speed := float64(o.Wind_kph)
+    if !c.celsius {
+      speed /= 1.609344
+    }
+    samples = append(samples, windSample{float64(o.Wind_degrees), speed})
+  }
+  if len(samples) > 0 {
+    return samples
+  }
+  speeds := c.daily(func(d *Dailysummary) string { return d.Meanwindspdi }, func(d *Dailysummary) string { return d.Meanwindspdm })
+  for i, d := range c.history {
+    if d != nil {
+      samples = append(samples, windSample{float64(ParseNumber(d.Meanwdird)), speeds[i]})
+    }
+  }
+  return samples
+}
+
+// windRose draws how often the wind blew from each of 16 directions,
+// stacked by speed
+func windRose(canvas Canvas, c chartData) {
+  limits, unit := []float64{5, 10, 15, 20}, "mph"
+  if c.celsius {
+    limits, unit = []float64{8, 16, 24, 32}, "km/h"
+  }
+  var counts [16][5]float64
+  total, calm := 0.0, 0.0
+  for _, s := range c.windSamples() {
+    if math.IsNaN(s.speed) || math.IsNaN(s.degrees) {
+      continue
+    }
+    total++
+    if s.speed < 1 {
+      calm++
+      continue
+    }
+    sector := int(math.Mod(s.degrees+11.25, 360) / 22.5)
+    bin := 0
+    for bin < len(limits) && s.speed >= limits[bin] {
+      bin++
+    }
+    counts[sector][bin]++
+  }
+
+  canvas.Text(20, 28, c.title("Wind at %s, %s to %s"), titleSize, "start", inkColor)
+  if total == 0 {
+    canvas.Text(roseSize/2, roseSize/2, T("No data available for specified date"), fontSize, "middle", inkColor)
+    return
+  }
+  most := 0.0
+  for _, sector := range counts {
+    sum := 0.0
+    for _, n := range sector {
+      sum += n
+    }
+    most = math.Max(most, sum/total*100)
+  }
+  if most == 0 {
+    most = 1
+  }
+  step := niceStep(most, 4)
+  outer := math.Ceil(most/step) * step
+  center, radius := Point{roseSize / 2, roseSize/2 + 10}, float64(roseSize)/2-80
+  r := func(pct float64) float64 { return pct / outer * radius }
+  polar := func(deg float64, dist float64) Point {
+    a := deg * math.Pi / 180
+    return Point{center.X + dist*math.Sin(a), center.Y - dist*math.Cos(a)}
+  }
+
+  for pct := step; pct <= outer+step/2; pct += step {
+    var ring []Point
+    for deg := 0.0; deg <= 360; deg += 5 {
+      ring = append(ring, polar(deg, r(pct)))
+    }
+    canvas.Line(ring, gridColor, 1, true)
+  }
+  for i, name := range []string{"N", "E", "S", "W"} {
+    deg := float64(i) * 90
+    canvas.Line([]Point{center, polar(deg, radius)}, gridColor, 1, false)
+    at := polar(deg, radius+18)
+    canvas.Text(at.X, at.Y+5, name, fontSize, "middle", inkColor)
+  }
+
+  for sector := range counts {
+    mid := float64(sector) * 22.5
+    inner := 0.0
+    for bin, n := range counts[sector] {
+      if n == 0 {
+        continue
+      }
+      outer := inner + n/total*100
+      var wedge []Point
+      for deg := mid - 9; deg <= mid+9.01; deg += 3 {
+        wedge = append(wedge, polar(deg, r(outer)))
+      }
+      for deg := mid + 9; deg >= mid-9.01; deg -= 3 {
+        wedge = append(wedge, polar(deg, r(inner)))
+      }
+      canvas.Fill(wedge, roseColors[bin])
+      inner = outer
+    }
+  }
+
+  for pct := step; pct <= outer+step/2; pct += step {
+    at := polar(22.5, r(pct))
+    canvas.Text(at.X+4, at.Y, fmt.Sprintf("%.0f%%", pct), fontSize-2, "start", inkColor)
+  }
+
+  // The speeds, and the calms, down the left
+  y := 60.0
+  for bin := range roseColors {
+    label := fmt.Sprintf("%.0f-%.0f %s", 0.0, limits[0], unit)
+    if bin > 0 && bin < len(limits) {
+      label = fmt.Sprintf("%.0f-%.0f %s", limits[bin-1], limits[bin], unit)
+    } else if bin == len(limits) {
+      label = fmt.Sprintf("%.0f+ %s", limits[bin-1], unit)
+    }
+    canvas.Fill(box(20, y-10, 12, 12), roseColors[bin])
+    canvas.Text(38, y, label, fontSize, "start", inkColor)
+    y += 18
+  }
+  canvas.Text(20, y, fmt.Sprintf("%s: %.0f%%", T("Calm"), calm/total*100), fontSize, "start", inkColor)
+}
+
+// Chart runs "wu chart", which draws a chart of the archived history
+// (or the stored readings) over a range of days as SVG or PNG
+func Chart(args []string) {
+  ReadConf()
+  flags := flag.NewFlagSet("chart", flag.ExitOnError)
+  stationFlag := flags.String("s", "", "Weather station (defaults to the one in .condrc)")
+  typeFlag := flags.String("type", "temp", "The chart: temp, precip, pressure or wind-rose")
+  fromFlag := flags.String("from", "", "The first day (YYYY-MM-DD; defaults to 30 days before --to)")
+  toFlag := flags.String("to", "", "The last day (YYYY-MM-DD; defaults to yesterday)")
+  out := flags.String("out", "chart.svg", "The file to write: .svg or .png")
+  normals := flags.Bool("normals", true, "With --type=temp, shades the normal range (from the almanac)")
+  flags.Parse(args)
+
+  draw, ok := chartTypes[*typeFlag]
+  if !ok {
+    fmt.Println("Usage: wu chart --type temp|precip|pressure|wind-rose [--from YYYY-MM-DD] [--to YYYY-MM-DD] [-s station] [--out chart.svg|chart.png]")
+    os.Exit(2)
+  }
+  now := time.Now()
+  to := time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, time.UTC)
+  var err error
+  if *toFlag != "" {
+    to, err = parseDay(*toFlag)
+    CheckError(err)
+  }
+  from := to.AddDate(0, 0, -29)
+  if *fromFlag != "" {
+    from, err = parseDay(*fromFlag)
+    CheckError(err)
+  }
+  if from.After(to) {
+    CheckError(fmt.Errorf("--from %s is after --to %s", from.Format("2006-01-02"), to.Format("2006-01-02")))
+  }
+
+  var canvas Canvas
+  width, height := plotWidth, plotHeight
+  if *typeFlag == "wind-rose" {
+    width, height = roseSize, roseSize
+  }
+  switch strings.ToLower(filepath.Ext(*out)) {
+  case ".svg":
+    canvas = NewSVGCanvas(width, height)
+  case ".png":
+    canvas = NewPNGCanvas(width, height)
+  default:
+    CheckError(fmt.Errorf("%s: the chart must be .svg or .png", *out))
+  }
+
+  station := CommandStation(*stationFlag)
+  data := chartData{station: station, from: from, to: to, celsius: conf.Degrees == "C"}
+  archive := LoadArchive(station)
+  for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+    data.days = append(data.days, d)
+    if summary, ok := archive[d.Format(archiveDate)]; ok {
+      data.history = append(data.history, &summary)
+    } else {
+      data.history = append(data.history, nil)
+    }
+  }
+  start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+  end := time.Date(to.Year(), to.Month(), to.Day()+1, 0, 0, 0, 0, time.Local)
+  for _, o := range LoadObservations(station) {
+    if !o.Time.Before(start) && o.Time.Before(end) {
+      data.obs = append(data.obs, o)
+    }
+  }
+  if *typeFlag == "temp" && *normals {
+    var almanac AlmanacConditions
+    b, err := Fetch(buildURL("almanac", "", station))
+    if err == nil {
+      err = json.Unmarshal(b, &almanac)
+    }
+    if err != nil {
+      fmt.Fprintln(os.Stderr, "Can't get the normals: "+RedactError(err).Error())
+    } else {
+      data.almanac = &almanac.Almanac
+    }
+  }
+
+  draw(canvas, data)
+  f, err := os.Create(*out)
+  CheckError(err)
+  CheckError(canvas.Encode(f))
+  CheckError(f.Close())
+  fmt.Println(T("Wrote") + " " + *out)
+}
@@ -0,0 +1,293 @@
+/*
+* pressure.go
+*
+* This file is part of wu.  It contains the functions that work
+* out the pressure tendency from stored observations, and the
+* Zambretti forecaster (the --zambretti switch).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 02:39:20 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "math"
+  "time"
+)
+
+// Tendency is the change in pressure over the last three hours, with
+// its WMO characteristic (code 0200) and a Zambretti forecast
+type Tendency struct {
+  Change      Number // mb in three hours
+  Code        Number
+  Description string
+  Rapid       bool
+  Zambretti   string
+}
+
+// rapidFall is the fall in pressure (mb in three hours) beyond which
+// it's taken as a sign of a coming storm
+const rapidFall = 3.0
+
+// steadyChange is the smallest change (mb) that counts as a rise or
+// fall in either half of the three hours
+const steadyChange = 0.2
+
+// tendencies describes the WMO pressure tendency characteristics
+var tendencies = []string{
+  "rising, then falling",
+  "rising, then steady or rising more slowly",
+  "rising steadily",
+  "falling or steady, then rising; or rising more quickly",
+  "steady",
+  "falling, then rising",
+  "falling, then steady or falling more slowly",
+  "falling steadily",
+  "rising or steady, then falling; or falling more quickly",
+}
+
+// PressureTendency works out the tendency from a station's stored
+// observations, which should reach back about three hours.  The wind
+// direction (degrees) and latitude go into the Zambretti forecast.
+// Without enough observations, the change is missing.
+func PressureTendency(obs []Observation, wind float64, lat float64) Tendency {
+  t := Tendency{Change: Number(math.NaN()), Code: Number(math.NaN())}
+  var readings []Observation
+  for _, o := range obs {
+    if o.Pressure_mb.Valid() {
+      readings = append(readings, o)
+    }
+  }
+  if len(readings) < 2 {
+    return t
+  }
+  last := readings[len(readings)-1]
+  first := nearest(readings, last.Time.Add(-3*time.Hour))
+  span := last.Time.Sub(first.Time)
+  if span < 2*time.Hour+15*time.Minute || span > 3*time.Hour+45*time.Minute {
+    return t
+  }
+  middle := nearest(readings, first.Time.Add(span/2))
+
+  // Scale to exactly three hours, and compare the rates of change
+  // either side of the middle reading, as changes over an hour and a
+  // half (they are the same if there's nothing in between)
+  p0, p1 := float64(first.Pressure_mb), float64(last.Pressure_mb)
+  change := (p1 - p0) * float64(3*time.Hour) / float64(span)
+  d1, d2 := change/2, change/2
+  if middle.Time.After(first.Time) && middle.Time.Before(last.Time) {
+    pm := float64(middle.Pressure_mb)
+    half := float64(90 * time.Minute)
+    d1 = (pm - p0) * half / float64(middle.Time.Sub(first.Time))
+    d2 = (p1 - pm) * half / float64(last.Time.Sub(middle.Time))
+  }
+
+  code := tendencyCode(change, d1, d2)
+  t.Change = Number(change)
+  t.Code = Number(code)
+  t.Description = tendencies[code]
+  t.Rapid = change < -rapidFall
+  t.Zambretti = Zambretti(p1, change, wind, last.Time.Month(), lat < 0)
+  return t
+}
+
+// nearest returns the observation closest in time to t
+func nearest(obs []Observation, t time.Time) Observation {
+  best := obs[0]
+  for _, o := range obs[1:] {
+    if math.Abs(float64(o.Time.Sub(t))) < math.Abs(float64(best.Time.Sub(t))) {
+      best = o
+    }
+  }
+  return best
+}
+
+// tendencyCode returns the WMO characteristic for a change over
+// three hours, made up of changes d1 and d2 in each half
+func tendencyCode(change float64, d1 float64, d2 float64) int {
+  const e = steadyChange
+  switch {
+  case math.Abs(change) < 0.1:
+    switch {
+    case d1 > e && d2 < -e:
+      return 0
+    case d1 < -e && d2 > e:
+      return 5
+    }
+    return 4
+  case change > 0:
+    switch {
+    case d1 > e && d2 < -e:
+      return 0
+    case d1 <= e:
+      return 3
+    case d2 <= e || d2 < d1-e:
+      return 1
+    case d2 > d1+e:
+      return 3
+    }
+    return 2
+  default:
+    switch {
+    case d1 < -e && d2 > e:
+      return 5
+    case d1 >= -e:
+      return 8
+    case d2 >= -e || d2 > d1+e:
+      return 6
+    case d2 < d1-e:
+      return 8
+    }
+    return 7
+  }
+}
+
+// zambrettiForecasts are the Zambretti forecaster's 26 forecasts, A
+// to Z
+var zambrettiForecasts = []string{
+  "Settled fine",
+  "Fine weather",
+  "Becoming fine",
+  "Fine, becoming less settled",
+  "Fine, possible showers",
+  "Fairly fine, improving",
+  "Fairly fine, possible showers early",
+  "Fairly fine, showery later",
+  "Showery early, improving",
+  "Changeable, mending",
+  "Fairly fine, showers likely",
+  "Rather unsettled, clearing later",
+  "Unsettled, probably improving",
+  "Showery, bright intervals",
+  "Showery, becoming less settled",
+  "Changeable, some rain",
+  "Unsettled, short fine intervals",
+  "Unsettled, rain later",
+  "Unsettled, some rain",
+  "Mostly very unsettled",
+  "Occasional rain, worsening",
+  "Rain at times, very unsettled",
+  "Rain at frequent intervals",
+  "Rain, very unsettled",
+  "Stormy, may improve",
+  "Stormy, much rain",
+}
+
+// The forecasts (as letters) for falling, steady and rising pressure,
+// from high pressure to low
+const (
+  zambrettiFalling = "ABDHORUVX"
+  zambrettiSteady  = "ABEKNPSWXZ"
+  zambrettiRising  = "ACFGIJLMQTYZ"
+)
+
+// zambrettiWinds adjusts the pressure (mb) for each of the sixteen
+// wind directions, starting from north
+var zambrettiWinds = []float64{6, 5, 5, 2, -0.5, -2, -5, -8.5, -12, -10, -6, -4.5, -3, -0.5, 1.5, 3}
+
+// Zambretti returns a short-range forecast by the Zambretti
+// forecaster from the sea-level pressure (mb), its change over three
+// hours, the wind direction (degrees, or NaN) and the month
+func Zambretti(pressure float64, change float64, wind float64, month time.Month, southern bool) string {
+  if !math.IsNaN(wind) {
+    if southern {
+      wind += 180
+    }
+    pressure += zambrettiWinds[int(math.Mod(wind+11.25, 360)/22.5)%16]
+  }
+  summer := month >= time.April && month <= time.September
+  if southern {
+    summer = !summer
+  }
+
+  // Each formula gives a number from the first upward, for the first
+  // of its letters
+  var z float64
+  var first int
+  var letters string
+  switch {
+  case change <= -1.6:
+    if summer {
+      pressure -= 7
+    }
+    z, first, letters = 127-0.12*pressure, 1, zambrettiFalling
+  case change >= 1.6:
+    if summer {
+      pressure += 7
+    }
+    z, first, letters = 185-0.16*pressure, 20, zambrettiRising
+  default:
+    z, first, letters = 144-0.13*pressure, 10, zambrettiSteady
+  }
+  i := int(math.Round(z)) - first
+  if i < 0 {
+    i = 0
+  } else if i >= len(letters) {
+    i = len(letters) - 1
+  }
+  return zambrettiForecasts[letters[i]-'A']
+}
+
+// pressureCharted is how far back the pressure chart goes
+const pressureCharted = 72 * time.Hour
+
+// PressureChart draws the pressure over the last three days from the
+// readings kept for a station, hour by hour (with gaps where there
+// are none within half an hour)
+func PressureChart(obs []Observation) []string {
+  if len(obs) < 2 {
+    return nil
+  }
+  last := obs[len(obs)-1].Time
+  first := obs[0].Time
+  if first.Before(last.Add(-pressureCharted)) {
+    first = last.Add(-pressureCharted)
+  }
+  first = first.Truncate(time.Hour)
+
+  var values []float64
+  var labels []string
+  for t := first; !t.After(last.Add(30 * time.Minute)); t = t.Add(time.Hour) {
+    value := math.NaN()
+    if o := nearest(obs, t); math.Abs(o.Time.Sub(t).Minutes()) <= 30 {
+      value = float64(o.Pressure_mb)
+      if conf.Degrees != "C" {
+        value /= 33.8639
+      }
+    }
+    values = append(values, value)
+    label := ""
+    if local := t.Local(); local.Hour() == 0 {
+      label = local.Format("Mon")
+    } else if local.Hour()%6 == 0 {
+      label = local.Format("15:04")
+    }
+    labels = append(labels, label)
+  }
+
+  format := "%.2f in"
+  if conf.Degrees == "C" {
+    format = "%.0f mb"
+  }
+  height, width := chartSize()
+  return LineChart(values, labels, height, width, format)
+}
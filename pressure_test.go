@@ -0,0 +1,69 @@
+/*
+* pressure_test.go
+*
+* This file is part of wu.  It contains tests for the pressure
+* tendency.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 02:39:20 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "testing"
+  "time"
+)
+
+func TestRapidFall(t *testing.T) {
+  start := time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+  tests := []struct {
+    fall  float64 // mb in three hours
+    rapid bool
+  }{
+    {2.0, false},
+    {3.0, false},
+    {3.1, true},
+  }
+  for _, test := range tests {
+    obs := []Observation{
+      {Time: start, Pressure_mb: 1012},
+      {Time: start.Add(90 * time.Minute), Pressure_mb: Number(1012 - test.fall/2)},
+      {Time: start.Add(3 * time.Hour), Pressure_mb: Number(1012 - test.fall)},
+    }
+    tendency := PressureTendency(obs, 180, 40)
+    if tendency.Rapid != test.rapid {
+      t.Errorf("a fall of %.1f mb: rapid %v; want %v", test.fall, tendency.Rapid, test.rapid)
+    }
+  }
+}
+
+func TestObservedAt(t *testing.T) {
+  now := time.Unix(1760821000, 0)
+  // the request was served later than the reading was taken
+  c := Current{Observation_epoch: "1760820780", Local_epoch: "1760820990"}
+  if got := observedAt(&c, now); !got.Equal(time.Unix(1760820780, 0)) {
+    t.Errorf("observed at %v; want the observation epoch", got)
+  }
+  if got := observedAt(&Current{Local_epoch: "1760820990"}, now); !got.Equal(now) {
+    t.Errorf("without an observation epoch: %v; want now", got)
+  }
+}
@@ -0,0 +1,461 @@
+/*
+* raster.go
+*
+* This file is part of wu.  It contains the canvas that draws
+* the charts of "wu chart" as PNG images, with a stroke font.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 02:18:37 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "image"
+  "image/color"
+  "image/png"
+  "io"
+  "math"
+  "sort"
+  "strconv"
+  "strings"
+  "unicode"
+)
+
+// supersample is how many pixels each way the image is drawn at for
+// each one in the PNG; averaging them smooths the edges
+const supersample = 3
+
+// rasterCanvas draws into an image
+type rasterCanvas struct {
+  img *image.RGBA
+}
+
+func NewPNGCanvas(width int, height int) Canvas {
+  img := image.NewRGBA(image.Rect(0, 0, width*supersample, height*supersample))
+  for i := range img.Pix {
+    img.Pix[i] = 255
+  }
+  return &rasterCanvas{img: img}
+}
+
+// blend paints a pixel, mixing in translucent colors
+func (r *rasterCanvas) blend(x int, y int, c color.RGBA) {
+  if !(image.Point{x, y}.In(r.img.Rect)) {
+    return
+  }
+  i := r.img.PixOffset(x, y)
+  a := float64(c.A) / 255
+  for k, v := range []uint8{c.R, c.G, c.B} {
+    r.img.Pix[i+k] = uint8(float64(r.img.Pix[i+k])*(1-a) + float64(v)*a + 0.5)
+  }
+}
+
+// fill paints a polygon (in supersampled pixels) by scanlines, with
+// the even-odd rule
+func (r *rasterCanvas) fill(points []Point, c color.RGBA) {
+  if len(points) < 3 {
+    return
+  }
+  top, bottom := math.Inf(1), math.Inf(-1)
+  for _, p := range points {
+    top, bottom = math.Min(top, p.Y), math.Max(bottom, p.Y)
+  }
+  for y := int(math.Max(top, 0)); y <= int(math.Min(bottom, float64(r.img.Rect.Dy()-1))); y++ {
+    cy := float64(y) + 0.5
+    var xs []float64
+    for i := range points {
+      a, b := points[i], points[(i+1)%len(points)]
+      if (a.Y <= cy) != (b.Y <= cy) {
+        xs = append(xs, a.X+(cy-a.Y)/(b.Y-a.Y)*(b.X-a.X))
+      }
+    }
+    sort.Float64s(xs)
+    for i := 0; i+1 < len(xs); i += 2 {
+      for x := int(math.Ceil(xs[i] - 0.5)); float64(x)+0.5 <= xs[i+1]; x++ {
+        r.blend(x, y, c)
+      }
+    }
+  }
+}
+
+func (r *rasterCanvas) scale(points []Point) []Point {
+  scaled := make([]Point, len(points))
+  for i, p := range points {
+    scaled[i] = Point{p.X * supersample, p.Y * supersample}
+  }
+  return scaled
+}
+
+// disc paints a round dot, for the joins and ends of lines
+func (r *rasterCanvas) disc(center Point, radius float64, c color.RGBA) {
+  var points []Point
+  for i := 0; i < 16; i++ {
+    a := float64(i) * math.Pi / 8
+    points = append(points, Point{center.X + radius*math.Cos(a), center.Y + radius*math.Sin(a)})
+  }
+  r.fill(points, c)
+}
+
+// segment paints a straight line of a width as a rectangle
+func (r *rasterCanvas) segment(a Point, b Point, width float64, c color.RGBA) {
+  length := math.Hypot(b.X-a.X, b.Y-a.Y)
+  if length == 0 {
+    return
+  }
+  nx, ny := -(b.Y-a.Y)/length*width/2, (b.X-a.X)/length*width/2
+  r.fill([]Point{{a.X + nx, a.Y + ny}, {b.X + nx, b.Y + ny}, {b.X - nx, b.Y - ny}, {a.X - nx, a.Y - ny}}, c)
+}
+
+// Line paints a polyline.  Joins are rounded, which double-paints
+// translucent lines there; the charts draw their lines opaque.
+func (r *rasterCanvas) Line(points []Point, c color.RGBA, width float64, dashed bool) {
+  points = r.scale(points)
+  width *= supersample
+  if dashed {
+    for _, dash := range dashes(points, 6*supersample, 4*supersample) {
+      r.segment(dash[0], dash[1], width, c)
+    }
+    return
+  }
+  for i := 0; i+1 < len(points); i++ {
+    r.segment(points[i], points[i+1], width, c)
+  }
+  if width > supersample {
+    for _, p := range points {
+      r.disc(p, width/2, c)
+    }
+  }
+}
+
+// dashes breaks a polyline into dashes of a length with gaps between
+func dashes(points []Point, on float64, off float64) [][2]Point {
+  var out [][2]Point
+  drawing, left := true, on
+  for i := 0; i+1 < len(points); i++ {
+    a, b := points[i], points[i+1]
+    length := math.Hypot(b.X-a.X, b.Y-a.Y)
+    at := 0.0
+    for at < length {
+      step := math.Min(left, length-at)
+      from := Point{a.X + (b.X-a.X)*at/length, a.Y + (b.Y-a.Y)*at/length}
+      to := Point{a.X + (b.X-a.X)*(at+step)/length, a.Y + (b.Y-a.Y)*(at+step)/length}
+      if drawing {
+        out = append(out, [2]Point{from, to})
+      }
+      at, left = at+step, left-step
+      if left <= 0 {
+        drawing = !drawing
+        left = on
+        if !drawing {
+          left = off
+        }
+      }
+    }
+  }
+  return out
+}
+
+func (r *rasterCanvas) Fill(points []Point, c color.RGBA) {
+  r.fill(r.scale(points), c)
+}
+
+// Text draws text in the stroke font, its lines a twelfth of the
+// font size wide and smoothed like the rest of the chart by the
+// supersampling.  Letters the font lacks are spelled out with plain
+// ones, and any other characters drawn as "?".
+func (r *rasterCanvas) Text(x float64, y float64, s string, size float64, anchor string, c color.RGBA) {
+  switch anchor {
+  case "middle":
+    x -= textWidth(s, size) / 2
+  case "end":
+    x -= textWidth(s, size)
+  }
+  weight := size / 12
+  for _, ch := range s {
+    strokes, ok := glyphStrokes(ch)
+    if !ok {
+      if plain := plainText(string(ch)); plain != string(ch) {
+        r.Text(x, y, plain, size, "start", c)
+        x += textWidth(plain, size)
+        continue
+      }
+      strokes, _ = glyphStrokes('?')
+    }
+    for _, stroke := range strokes {
+      points := make([]Point, len(stroke))
+      for i, p := range stroke {
+        points[i] = Point{x + p.X*size/1000, y - p.Y*size/1000}
+      }
+      if len(points) == 1 {
+        r.disc(r.scale(points)[0], weight*supersample*0.7, c)
+      } else {
+        r.Line(points, c, weight, false)
+      }
+    }
+    x += textWidth(string(ch), size)
+  }
+}
+
+// Encode averages the supersampled image down and writes it as PNG
+func (r *rasterCanvas) Encode(w io.Writer) error {
+  bounds := r.img.Rect
+  out := image.NewRGBA(image.Rect(0, 0, bounds.Dx()/supersample, bounds.Dy()/supersample))
+  for y := 0; y < out.Rect.Dy(); y++ {
+    for x := 0; x < out.Rect.Dx(); x++ {
+      var sum [3]int
+      for dy := 0; dy < supersample; dy++ {
+        for dx := 0; dx < supersample; dx++ {
+          i := r.img.PixOffset(x*supersample+dx, y*supersample+dy)
+          for k := range sum {
+            sum[k] += int(r.img.Pix[i+k])
+          }
+        }
+      }
+      n := supersample * supersample
+      out.SetRGBA(x, y, color.RGBA{uint8(sum[0] / n), uint8(sum[1] / n), uint8(sum[2] / n), 255})
+    }
+  }
+  return png.Encode(w, out)
+}
+
+// plainText spells out letters the font lacks, such as "ß" and "œ",
+// with plain ones (as the gazetteer does)
+func plainText(s string) string {
+  var b strings.Builder
+  for _, ch := range s {
+    plain, ok := accents[unicode.ToLower(ch)]
+    switch {
+    case !ok:
+      b.WriteRune(ch)
+    case unicode.IsUpper(ch):
+      b.WriteString(strings.ToUpper(plain))
+    default:
+      b.WriteString(plain)
+    }
+  }
+  return b.String()
+}
+
+// glyphStrokes returns the strokes of a character, putting together
+// an accented letter from its plain one and its mark
+func glyphStrokes(ch rune) ([][]Point, bool) {
+  if strokes, ok := fontStrokes[ch]; ok {
+    return strokes, true
+  }
+  for _, m := range markedLetters {
+    i := strings.IndexRune(m.letters, ch)
+    if i < 0 {
+      continue
+    }
+    base := []rune(m.bases)[len([]rune(m.letters[:i]))]
+    strokes := append([][]Point{}, fontStrokes[base]...)
+    // the mark sits above the x-height or the capitals, or (a
+    // cedilla) under the baseline, centred on the letter
+    at := Point{float64(helvetica[base]) / 2, 560}
+    if unicode.IsUpper(base) {
+      at.Y = 770
+    }
+    if m.mark == "cedilla" {
+      at.Y = 0
+    }
+    for _, stroke := range markStrokes[m.mark] {
+      moved := make([]Point, len(stroke))
+      for j, p := range stroke {
+        moved[j] = Point{p.X + at.X, p.Y + at.Y}
+      }
+      strokes = append(strokes, moved)
+    }
+    return strokes, true
+  }
+  return nil, false
+}
+
+// markedLetters are the accented letters the font puts together, with
+// the plain letter of each
+var markedLetters = []struct {
+  mark    string
+  letters string
+  bases   string
+}{
+  {"grave", "àèìòùÀÈÌÒÙ", "aeıouAEIOU"},
+  {"acute", "áéíóúýÁÉÍÓÚÝ", "aeıouyAEIOUY"},
+  {"circumflex", "âêîôûÂÊÎÔÛ", "aeıouAEIOU"},
+  {"diaeresis", "äëïöüÿÄËÏÖÜ", "aeıouyAEIOU"},
+  {"tilde", "ãñõÃÑÕ", "anoANO"},
+  {"ring", "åÅ", "aA"},
+  {"cedilla", "çÇ", "cC"},
+}
+
+// parseStrokes reads the strokes of a glyph.  Each is a list of
+// points "x,y", in thousandths of the font size up from the baseline,
+// and arcs "a:cx,cy,rx,ry,from,to" of an ellipse, from one angle to
+// another in degrees (anticlockwise if the second is larger).  A
+// stroke of one point is a dot.
+func parseStrokes(glyph []string) [][]Point {
+  var strokes [][]Point
+  for _, s := range glyph {
+    var stroke []Point
+    for _, token := range strings.Fields(s) {
+      var v []float64
+      for _, n := range strings.Split(strings.TrimPrefix(token, "a:"), ",") {
+        f, _ := strconv.ParseFloat(n, 64)
+        v = append(v, f)
+      }
+      if !strings.HasPrefix(token, "a:") {
+        stroke = append(stroke, Point{v[0], v[1]})
+        continue
+      }
+      steps := int(math.Ceil(math.Abs(v[5]-v[4]) / 10))
+      for i := 0; i <= steps; i++ {
+        a := (v[4] + (v[5]-v[4])*float64(i)/float64(steps)) * math.Pi / 180
+        stroke = append(stroke, Point{v[0] + v[2]*math.Cos(a), v[1] + v[3]*math.Sin(a)})
+      }
+    }
+    strokes = append(strokes, stroke)
+  }
+  return strokes
+}
+
+var fontStrokes = func() map[rune][][]Point {
+  parsed := map[rune][][]Point{}
+  for ch, glyph := range glyphs {
+    parsed[ch] = parseStrokes(glyph)
+  }
+  return parsed
+}()
+
+var markStrokes = func() map[string][][]Point {
+  parsed := map[string][][]Point{}
+  for name, mark := range marks {
+    parsed[name] = parseStrokes(mark)
+  }
+  return parsed
+}()
+
+// glyphs is a stroke font drawn to Helvetica's widths: the capitals
+// and ascenders 700 high, the x-height 500 and descenders to -200
+var glyphs = map[rune][]string{
+  ' ':  {},
+  '0':  {"a:278,350,200,350,0,360"},
+  '1':  {"150,560 300,700 300,0"},
+  '2':  {"a:278,500,200,200,165,-25 78,0 490,0"},
+  '3':  {"a:278,530,180,170,150,-90 a:278,185,205,175,90,-150"},
+  '4':  {"400,0 400,700 60,200 500,200"},
+  '5':  {"460,700 150,700 139,393 a:280,230,200,230,135,-150"},
+  '6':  {"a:290,340,210,340,55,200", "a:280,225,200,225,0,360"},
+  '7':  {"70,700 490,700 220,0"},
+  '8':  {"a:278,530,170,170,0,360", "a:278,185,205,185,0,360"},
+  '9':  {"a:276,475,200,225,0,360", "a:266,360,210,360,5,-125"},
+  'A':  {"40,0 333,700 627,0", "150,240 516,240"},
+  'B':  {"90,0 90,700 390,700 a:390,530,170,170,90,-90 90,360", "390,360 a:390,180,200,180,90,-90 90,0"},
+  'C':  {"a:400,350,320,350,50,310"},
+  'D':  {"90,0 90,700 320,700 a:320,350,320,350,90,-90 90,0"},
+  'E':  {"580,700 90,700 90,0 580,0", "90,360 530,360"},
+  'F':  {"560,700 90,700 90,0", "90,360 500,360"},
+  'G':  {"a:410,350,330,350,40,355 450,320"},
+  'H':  {"90,0 90,700", "632,0 632,700", "90,360 632,360"},
+  'I':  {"139,0 139,700"},
+  'J':  {"410,700 410,220 a:240,220,170,220,0,-160"},
+  'K':  {"90,0 90,700", "620,700 90,230", "270,410 640,0"},
+  'L':  {"90,700 90,0 520,0"},
+  'M':  {"90,0 90,700 416,0 743,700 743,0"},
+  'N':  {"90,0 90,700 632,0 632,700"},
+  'O':  {"a:389,350,320,350,0,360"},
+  'P':  {"90,0 90,700 390,700 a:390,515,200,185,90,-90 90,330"},
+  'Q':  {"a:389,350,320,350,0,360", "470,150 700,-40"},
+  'R':  {"90,0 90,700 400,700 a:400,525,200,175,90,-90 90,350", "400,350 630,0"},
+  'S':  {"a:330,530,220,170,20,270 a:335,180,240,180,90,-160"},
+  'T':  {"30,700 581,700", "306,700 306,0"},
+  'U':  {"90,700 90,240 a:361,240,271,240,180,360 632,700"},
+  'V':  {"30,700 333,0 637,700"},
+  'W':  {"30,700 250,0 472,620 694,0 914,700"},
+  'X':  {"50,700 617,0", "617,700 50,0"},
+  'Y':  {"40,700 333,340 627,700", "333,340 333,0"},
+  'Z':  {"70,700 550,700 60,0 560,0"},
+  'a':  {"a:270,370,165,130,150,0 435,0", "a:255,145,180,145,0,360"},
+  'b':  {"80,700 80,0", "a:300,250,220,250,0,360"},
+  'c':  {"a:270,250,200,250,45,315"},
+  'd':  {"476,700 476,0", "a:256,250,220,250,0,360"},
+  'e':  {"70,255 486,255 a:278,250,208,250,0,320"},
+  'f':  {"265,677 a:230,590,70,100,60,180 160,0", "40,490 250,490"},
+  'g':  {"476,500 476,-50 a:266,-50,210,150,0,-160", "a:256,260,220,240,0,360"},
+  'h':  {"80,700 80,0", "80,330 a:278,330,198,170,180,0 476,0"},
+  'i':  {"111,0 111,500", "111,665"},
+  'ı':  {"139,0 139,500"},
+  'j':  {"111,500 111,-130 40,-200", "111,665"},
+  'k':  {"80,700 80,0", "440,500 80,170", "210,280 460,0"},
+  'l':  {"111,700 111,0"},
+  'm':  {"80,500 80,0", "80,330 a:248,330,168,170,180,0 416,0", "416,330 a:584,330,168,170,180,0 752,0"},
+  'n':  {"80,500 80,0", "80,330 a:278,330,198,170,180,0 476,0"},
+  'o':  {"a:278,250,208,250,0,360"},
+  'p':  {"80,500 80,-200", "a:300,250,220,250,0,360"},
+  'q':  {"476,500 476,-200", "a:256,250,220,250,0,360"},
+  'r':  {"80,500 80,0", "80,300 a:300,300,220,200,180,100"},
+  's':  {"a:250,375,170,125,20,270 a:255,125,190,125,90,-160"},
+  't':  {"140,650 140,80 a:210,80,70,80,180,300", "30,500 250,500"},
+  'u':  {"80,500 80,170 a:278,170,198,170,180,360", "476,500 476,0"},
+  'v':  {"30,500 250,0 470,500"},
+  'w':  {"20,500 190,0 361,440 532,0 702,500"},
+  'x':  {"40,500 460,0", "460,500 40,0"},
+  'y':  {"30,500 255,0", "470,500 210,-130 150,-200 80,-200"},
+  'z':  {"50,500 450,500 50,0 460,0"},
+  'ß':  {"80,0 80,560 a:250,560,170,140,180,-70 240,390 a:300,200,210,190,80,-125"},
+  '.':  {"139,30"},
+  ',':  {"150,40 100,-110"},
+  ':':  {"139,30", "139,470"},
+  ';':  {"150,40 100,-110", "139,470"},
+  '!':  {"139,700 139,200", "139,30"},
+  '?':  {"a:278,520,190,180,160,-90 278,200", "278,30"},
+  '\'': {"95,700 95,500"},
+  '"':  {"100,700 100,500", "255,700 255,500"},
+  '-':  {"60,300 273,300"},
+  '–':  {"40,300 516,300"},
+  '_':  {"0,-150 556,-150"},
+  '+':  {"292,80 292,520", "72,300 512,300"},
+  '×':  {"110,120 474,480", "474,120 110,480"},
+  '=':  {"80,390 504,390", "80,210 504,210"},
+  '<':  {"500,520 80,300 500,80"},
+  '>':  {"84,520 504,300 84,80"},
+  '/':  {"0,-20 278,720"},
+  '|':  {"130,740 130,-200"},
+  '(':  {"a:330,250,200,450,120,240"},
+  ')':  {"a:0,250,200,450,60,-60"},
+  '[':  {"230,740 90,740 90,-150 230,-150"},
+  ']':  {"48,740 188,740 188,-150 48,-150"},
+  '*':  {"195,700 195,420", "70,630 320,490", "320,630 70,490"},
+  '#':  {"200,680 160,20", "400,680 360,20", "80,470 500,470", "60,230 480,230"},
+  '%':  {"a:200,540,120,150,0,360", "a:690,160,120,150,0,360", "660,700 230,0"},
+  '°':  {"a:200,580,110,110,0,360"},
+  '·':  {"139,300"},
+}
+
+// marks are the accents, centred on 0 and drawn up from the mark's
+// place over (or under) the letter
+var marks = map[string][]string{
+  "grave":      {"-70,120 30,0"},
+  "acute":      {"-30,0 70,120"},
+  "circumflex": {"-90,0 0,110 90,0"},
+  "diaeresis":  {"-90,40", "90,40"},
+  "tilde":      {"-110,20 -50,90 50,30 110,100"},
+  "ring":       {"a:0,70,70,70,0,360"},
+  "cedilla":    {"20,0 60,-70 -40,-150"},
+}
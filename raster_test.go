@@ -0,0 +1,82 @@
+/*
+* raster_test.go
+*
+* This file is part of wu.  It contains tests for the PNG charts'
+* stroke font.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 02:19:50 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "testing"
+)
+
+func TestStrokeFont(t *testing.T) {
+  for ch, strokes := range fontStrokes {
+    if _, ok := helvetica[ch]; !ok {
+      t.Errorf("%q has strokes but no width", ch)
+    }
+    for _, stroke := range strokes {
+      if len(stroke) == 0 {
+        t.Errorf("%q has an empty stroke", ch)
+      }
+    }
+  }
+  for _, m := range markedLetters {
+    letters, bases := []rune(m.letters), []rune(m.bases)
+    if len(letters) != len(bases) {
+      t.Errorf("%s: %d letters but %d plain ones", m.mark, len(letters), len(bases))
+      continue
+    }
+    if _, ok := markStrokes[m.mark]; !ok {
+      t.Errorf("no %s mark", m.mark)
+    }
+    for i, ch := range letters {
+      if _, ok := fontStrokes[bases[i]]; !ok {
+        t.Errorf("%q: no glyph for %q", ch, bases[i])
+      }
+      if charWidth(ch) != helvetica[bases[i]] {
+        t.Errorf("%q is %d wide; want %d, as %q", ch, charWidth(ch), helvetica[bases[i]], bases[i])
+      }
+    }
+  }
+}
+
+func TestTextWidth(t *testing.T) {
+  tests := []struct {
+    text  string
+    width float64 // at 10 points
+  }{
+    {"", 0},
+    {"High", 7.22 + 2.22 + 5.56 + 5.56},
+    {"°F", 4 + 6.11},
+    {"Zürich", 6.11 + 5.56 + 3.33 + 2.22 + 5 + 5.56},
+    {"Œ", 7.78 + 6.67}, // spelled out as "OE"
+  }
+  for _, test := range tests {
+    if got := textWidth(test.text, 10); got < test.width-0.001 || got > test.width+0.001 {
+      t.Errorf("%q: %.2f wide; want %.2f", test.text, got, test.width)
+    }
+  }
+}
@@ -0,0 +1,263 @@
+/*
+* records.go
+*
+* This file is part of wu.  It contains functions related to
+* the -anomaly switch (a day's temperatures against the almanac's
+* normals and records).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 03:07:29 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "encoding/json"
+  "fmt"
+  "math"
+  "os"
+  "strconv"
+  "time"
+)
+
+// Anomaly compares a day's high, low and mean temperatures with the
+// almanac's normals and records.  High_record and Low_record are
+// "new", "tied" or empty; they are left empty for any day but today,
+// whose records the almanac doesn't give.
+type Anomaly struct {
+  Date             string
+  High_f           Number
+  High_c           Number
+  Low_f            Number
+  Low_c            Number
+  Mean_f           Number
+  Mean_c           Number
+  Normal_high_f    Number
+  Normal_high_c    Number
+  Normal_low_f     Number
+  Normal_low_c     Number
+  Record_high_f    Number
+  Record_high_c    Number
+  Record_high_year string
+  Record_low_f     Number
+  Record_low_c     Number
+  Record_low_year  string
+  High_departure_f Number
+  High_departure_c Number
+  Low_departure_f  Number
+  Low_departure_c  Number
+  Mean_departure_f Number
+  Mean_departure_c Number
+  High_record      string
+  Low_record       string
+  Normals_for      string // the date of the almanac, if not the same day: the departures are approximate
+}
+
+// Pretty returns the day's date for the report
+func (a Anomaly) Pretty() string {
+  return prettyDay(a.Date)
+}
+
+// prettyDay formats a date (YYYY-MM-DD) in the user's language
+func prettyDay(date string) string {
+  t, err := time.Parse("2006-01-02", date)
+  if err != nil {
+    return date
+  }
+  return LongDate(int(t.Month()), strconv.Itoa(t.Day()), strconv.Itoa(t.Year()))
+}
+
+// NewAnomaly compares a day's history with the almanac, which is
+// today's.  For another day the departures are only approximate, and
+// the records aren't compared.
+func NewAnomaly(day time.Time, summary Dailysummary, almanac Almanac) Anomaly {
+  a := Anomaly{
+    Date:             day.Format("2006-01-02"),
+    High_f:           ParseNumber(summary.Maxtempi),
+    High_c:           ParseNumber(summary.Maxtempm),
+    Low_f:            ParseNumber(summary.Mintempi),
+    Low_c:            ParseNumber(summary.Mintempm),
+    Mean_f:           ParseNumber(summary.Meantempi),
+    Mean_c:           ParseNumber(summary.Meantempm),
+    Normal_high_f:    ParseNumber(almanac.Temp_high.Normal.F),
+    Normal_high_c:    ParseNumber(almanac.Temp_high.Normal.C),
+    Normal_low_f:     ParseNumber(almanac.Temp_low.Normal.F),
+    Normal_low_c:     ParseNumber(almanac.Temp_low.Normal.C),
+    Record_high_f:    ParseNumber(almanac.Temp_high.Record.F),
+    Record_high_c:    ParseNumber(almanac.Temp_high.Record.C),
+    Record_high_year: almanac.Temp_high.Recordyear,
+    Record_low_f:     ParseNumber(almanac.Temp_low.Record.F),
+    Record_low_c:     ParseNumber(almanac.Temp_low.Record.C),
+    Record_low_year:  almanac.Temp_low.Recordyear,
+  }
+  a.High_departure_f = a.High_f - a.Normal_high_f
+  a.Low_departure_f = a.Low_f - a.Normal_low_f
+  a.Mean_departure_f = a.Mean_f - (a.Normal_high_f+a.Normal_low_f)/2
+  a.High_departure_c = a.High_departure_f * 5 / 9
+  a.Low_departure_c = a.Low_departure_f * 5 / 9
+  a.Mean_departure_c = a.Mean_departure_f * 5 / 9
+
+  if today := time.Now().Format("2006-01-02"); today != a.Date {
+    a.Normals_for = today
+    return a
+  }
+  a.High_record = recordStatus(a.High_f, a.Record_high_f, 1)
+  a.Low_record = recordStatus(a.Low_f, a.Record_low_f, -1)
+  return a
+}
+
+// recordStatus returns "new" if a reading beats a record (upward if
+// sign is 1, downward if -1), "tied" if it equals it, or ""
+func recordStatus(reading Number, record Number, sign float64) string {
+  if !reading.Valid() || !record.Valid() {
+    return ""
+  }
+  switch d := sign * (math.Round(float64(reading)) - float64(record)); {
+  case d > 0:
+    return "new"
+  case d == 0:
+    return "tied"
+  }
+  return ""
+}
+
+// parseAnomalyDay reads the --anomaly date: "today", "yesterday" or
+// YYYYMMDD
+func parseAnomalyDay(s string) (time.Time, error) {
+  now := time.Now()
+  today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+  switch s {
+  case "today":
+    return today, nil
+  case "yesterday":
+    return today.AddDate(0, 0, -1), nil
+  }
+  return parseDay(s)
+}
+
+const anomalyTemplate = `{{heading (printf (T "Departures from normal at %s on %s") station .Pretty)}}
+{{if celsius}}   {{pad 4 (T "High")}}: {{temp (printf "%s C" (fixed 0 .High_c))}} ({{T "normal"}} {{fixed 0 .Normal_high_c}} C, {{printf "%+.1f" (num .High_departure_c)}} C)
+   {{pad 4 (T "Low")}}: {{temp (printf "%s C" (fixed 0 .Low_c))}} ({{T "normal"}} {{fixed 0 .Normal_low_c}} C, {{printf "%+.1f" (num .Low_departure_c)}} C)
+   {{pad 4 (T "Mean")}}: {{temp (printf "%s C" (fixed 0 .Mean_c))}} ({{printf "%+.1f" (num .Mean_departure_c)}} C)
+{{else}}   {{pad 4 (T "High")}}: {{temp (printf "%s F" (fixed 0 .High_f))}} ({{T "normal"}} {{fixed 0 .Normal_high_f}} F, {{printf "%+.0f" (num .High_departure_f)}} F)
+   {{pad 4 (T "Low")}}: {{temp (printf "%s F" (fixed 0 .Low_f))}} ({{T "normal"}} {{fixed 0 .Normal_low_f}} F, {{printf "%+.0f" (num .Low_departure_f)}} F)
+   {{pad 4 (T "Mean")}}: {{temp (printf "%s F" (fixed 0 .Mean_f))}} ({{printf "%+.0f" (num .Mean_departure_f)}} F)
+{{end}}{{$high := printf "%s F" (fixed 0 .Record_high_f)}}{{$low := printf "%s F" (fixed 0 .Record_low_f)}}{{if celsius}}{{$high = printf "%s C" (fixed 0 .Record_high_c)}}{{$low = printf "%s C" (fixed 0 .Record_low_c)}}{{end -}}
+{{if eq .High_record "new"}}   {{alert (printf (T "New record high (the record was %s in %s)") $high .Record_high_year)}}
+{{else if eq .High_record "tied"}}   {{alert (printf (T "Ties the record high (%s in %s)") $high .Record_high_year)}}
+{{end}}{{if eq .Low_record "new"}}   {{alert (printf (T "New record low (the record was %s in %s)") $low .Record_low_year)}}
+{{else if eq .Low_record "tied"}}   {{alert (printf (T "Ties the record low (%s in %s)") $low .Record_low_year)}}
+{{end}}{{if .Normals_for}}   ({{printf (T "Approximate: the normals are those for %s, and records are only compared on the day.") (longdate (slice .Normals_for 5 7) (printf "%d" (atoi (slice .Normals_for 8))) (slice .Normals_for 0 4))}})
+{{end}}`
+
+// PrintAnomaly fetches the history for a day ("today", "yesterday" or
+// YYYYMMDD) and the almanac, and prints the departures from normal.
+// The API gives the almanac only for today, so other days are
+// compared with today's normals, approximately, and not with its
+// records.
+func PrintAnomaly(station string, when string) {
+  day, err := parseAnomalyDay(when)
+  CheckError(err)
+  key := day.Format(archiveDate)
+
+  var almanac AlmanacConditions
+  b, err := Fetch(buildURL("almanac", "", station))
+  CheckError(err)
+  CheckError(json.Unmarshal(b, &almanac))
+
+  archive := LoadArchive(station)
+  summary, ok := archive[key]
+  if !ok || key == time.Now().Format(archiveDate) {
+    var obs HistoryConditions
+    b, err := Fetch(buildURL("history", key, station))
+    CheckError(err)
+    CheckError(json.Unmarshal(b, &obs))
+    if len(obs.History.Dailysummary) == 0 {
+      fmt.Println(T("No data available for specified date"))
+      return
+    }
+    summary = obs.History.Dailysummary[0]
+    ArchiveHistory(station, &obs)
+  }
+
+  a := NewAnomaly(day, summary, almanac.Almanac)
+  Render("anomaly", station, conf.Degrees, &a)
+}
+
+// recordWatch remembers the day's almanac while watching the
+// conditions, and which records have been announced
+type recordWatch struct {
+  day       string
+  almanac   Almanac
+  announced map[string]bool
+}
+
+// check announces (and sends to the webhooks) a current temperature
+// above the day's record high or below its record low, once a day
+func (w *recordWatch) check(station string, current *Current) {
+  today := time.Now().Format(archiveDate)
+  if w.day != today {
+    var obs AlmanacConditions
+    b, err := Fetch(buildURL("almanac", "", station))
+    if err == nil {
+      err = json.Unmarshal(b, &obs)
+    }
+    if err != nil {
+      fmt.Fprintf(os.Stderr, "%s: %v\n", time.Now().Format(time.Kitchen), err)
+      return
+    }
+    w.day, w.almanac, w.announced = today, obs.Almanac, map[string]bool{}
+  }
+
+  t, _, _, _ := current.readings()
+  temp := Number(ctof(t))
+  for _, r := range []struct {
+    kind   string
+    label  string
+    record Temp_high
+    sign   float64
+  }{
+    {"high", "Record high", w.almanac.Temp_high, 1},
+    {"low", "Record low", Temp_high(w.almanac.Temp_low), -1},
+  } {
+    record := ParseNumber(r.record.Record.F)
+    if w.announced[r.kind] || !temp.Valid() || !record.Valid() || r.sign*float64(temp-record) <= 0 {
+      continue
+    }
+    w.announced[r.kind] = true
+    reading, was := fmt.Sprintf("%.0f F", float64(temp)), r.record.Record.F+" F"
+    if conf.Degrees == "C" {
+      reading, was = fmt.Sprintf("%.0f C", t), r.record.Record.C+" C"
+    }
+    fmt.Println(StyleAlert(fmt.Sprintf(T("%s today: %s (the record was %s in %s)"),
+      T(r.label), reading, was, r.record.Recordyear)))
+    title := fmt.Sprintf("Record %s at %s: %s (the record was %s in %s)",
+      r.kind, station, reading, was, r.record.Recordyear)
+    Notify(Notification{
+      Event:   "record." + r.kind,
+      Station: station,
+      Title:   title,
+      Text:    current.Observation_time,
+      Time:    time.Now().Format(time.RFC3339),
+      Current: current,
+    })
+  }
+}
@@ -0,0 +1,60 @@
+/*
+* records_test.go
+*
+* This file is part of wu.  It contains tests for comparing a day's
+* temperatures with the almanac's normals and records.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 02:09:18 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "testing"
+  "time"
+)
+
+func TestNewAnomaly(t *testing.T) {
+  var almanac Almanac
+  almanac.Temp_high.Normal.F, almanac.Temp_high.Record.F, almanac.Temp_high.Recordyear = "60", "90", "1950"
+  almanac.Temp_low.Normal.F, almanac.Temp_low.Record.F, almanac.Temp_low.Recordyear = "40", "20", "1917"
+  summary := Dailysummary{Maxtempi: "90", Mintempi: "18", Meantempi: "54"}
+
+  today := NewAnomaly(time.Now(), summary, almanac)
+  if today.High_record != "tied" || today.Low_record != "new" || today.Normals_for != "" {
+    t.Errorf("today: records %q and %q, normals for %q; want tied, new and none",
+      today.High_record, today.Low_record, today.Normals_for)
+  }
+  if today.High_departure_f != 30 || today.Low_departure_f != -22 || today.Mean_departure_f != 4 {
+    t.Errorf("today: departures %v, %v, %v; want 30, -22, 4",
+      today.High_departure_f, today.Low_departure_f, today.Mean_departure_f)
+  }
+
+  // the almanac is today's, so another day's records can't be judged
+  earlier := NewAnomaly(time.Now().AddDate(0, 0, -3), summary, almanac)
+  if earlier.High_record != "" || earlier.Low_record != "" {
+    t.Errorf("three days ago: records %q and %q; want none", earlier.High_record, earlier.Low_record)
+  }
+  if earlier.Normals_for != time.Now().Format("2006-01-02") {
+    t.Errorf("three days ago: normals for %q; want today", earlier.Normals_for)
+  }
+}
@@ -0,0 +1,149 @@
+/*
+* regions.go
+*
+* This file is part of wu.  It contains the names of countries,
+* US states and Canadian provinces recognized in locations.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 01:58:12 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "strings"
+
+// usStates maps the postal abbreviations of US states (and DC and
+// the territories) to their names
+var usStates = map[string]string{
+  "AL": "Alabama", "AK": "Alaska", "AZ": "Arizona", "AR": "Arkansas",
+  "CA": "California", "CO": "Colorado", "CT": "Connecticut", "DE": "Delaware",
+  "DC": "District of Columbia", "FL": "Florida", "GA": "Georgia", "HI": "Hawaii",
+  "ID": "Idaho", "IL": "Illinois", "IN": "Indiana", "IA": "Iowa",
+  "KS": "Kansas", "KY": "Kentucky", "LA": "Louisiana", "ME": "Maine",
+  "MD": "Maryland", "MA": "Massachusetts", "MI": "Michigan", "MN": "Minnesota",
+  "MS": "Mississippi", "MO": "Missouri", "MT": "Montana", "NE": "Nebraska",
+  "NV": "Nevada", "NH": "New Hampshire", "NJ": "New Jersey", "NM": "New Mexico",
+  "NY": "New York", "NC": "North Carolina", "ND": "North Dakota", "OH": "Ohio",
+  "OK": "Oklahoma", "OR": "Oregon", "PA": "Pennsylvania", "RI": "Rhode Island",
+  "SC": "South Carolina", "SD": "South Dakota", "TN": "Tennessee", "TX": "Texas",
+  "UT": "Utah", "VT": "Vermont", "VA": "Virginia", "WA": "Washington",
+  "WV": "West Virginia", "WI": "Wisconsin", "WY": "Wyoming",
+  "PR": "Puerto Rico", "GU": "Guam", "VI": "Virgin Islands", "AS": "American Samoa",
+}
+
+// caProvinces maps the postal abbreviations of Canadian provinces
+// and territories to their names
+var caProvinces = map[string]string{
+  "AB": "Alberta", "BC": "British Columbia", "MB": "Manitoba",
+  "NB": "New Brunswick", "NL": "Newfoundland and Labrador", "NS": "Nova Scotia",
+  "NT": "Northwest Territories", "NU": "Nunavut", "ON": "Ontario",
+  "PE": "Prince Edward Island", "QC": "Quebec", "SK": "Saskatchewan",
+  "YT": "Yukon",
+}
+
+// countries maps ISO 3166 codes to country names: first the English
+// name, then any other names the country goes by
+var countries = map[string][]string{
+  "AR": {"Argentina"},
+  "AT": {"Austria", "Österreich"},
+  "AU": {"Australia"},
+  "BE": {"Belgium", "België", "Belgique"},
+  "BR": {"Brazil", "Brasil"},
+  "CA": {"Canada"},
+  "CH": {"Switzerland", "Schweiz", "Suisse", "Svizzera"},
+  "CL": {"Chile"},
+  "CN": {"China"},
+  "CO": {"Colombia"},
+  "CZ": {"Czech Republic", "Czechia", "Česko"},
+  "DE": {"Germany", "Deutschland"},
+  "DK": {"Denmark", "Danmark"},
+  "EG": {"Egypt"},
+  "ES": {"Spain", "España"},
+  "FI": {"Finland", "Suomi"},
+  "FR": {"France"},
+  "GB": {"United Kingdom", "UK", "Great Britain", "England", "Scotland", "Wales", "Northern Ireland"},
+  "GE": {"Georgia"},
+  "GR": {"Greece", "Ελλάδα"},
+  "HK": {"Hong Kong"},
+  "HU": {"Hungary", "Magyarország"},
+  "IE": {"Ireland", "Éire"},
+  "IL": {"Israel"},
+  "IN": {"India"},
+  "IS": {"Iceland", "Ísland"},
+  "IT": {"Italy", "Italia"},
+  "JP": {"Japan"},
+  "KE": {"Kenya"},
+  "KR": {"South Korea", "Korea"},
+  "LU": {"Luxembourg"},
+  "MX": {"Mexico", "México"},
+  "NL": {"Netherlands", "The Netherlands", "Holland", "Nederland"},
+  "NO": {"Norway", "Norge"},
+  "NZ": {"New Zealand"},
+  "PE": {"Peru", "Perú"},
+  "PH": {"Philippines"},
+  "PL": {"Poland", "Polska"},
+  "PT": {"Portugal"},
+  "RU": {"Russia"},
+  "SE": {"Sweden", "Sverige"},
+  "SG": {"Singapore"},
+  "TH": {"Thailand"},
+  "TR": {"Turkey", "Türkiye"},
+  "UA": {"Ukraine"},
+  "US": {"United States", "USA", "United States of America"},
+  "ZA": {"South Africa"},
+}
+
+// lookupCountry returns the ISO code for a country name or code
+func lookupCountry(name string) (string, bool) {
+  if code := strings.ToUpper(name); len(code) == 2 {
+    if _, ok := countries[code]; ok {
+      return code, true
+    }
+  }
+  for code, names := range countries {
+    for _, n := range names {
+      if strings.EqualFold(n, name) {
+        return code, true
+      }
+    }
+  }
+  return "", false
+}
+
+// lookupRegion returns the abbreviation for a US state or Canadian
+// province, given either its abbreviation or its name, and the
+// country it's in
+func lookupRegion(name string) (region string, country string, ok bool) {
+  for _, t := range []struct {
+    country string
+    regions map[string]string
+  }{{"US", usStates}, {"CA", caProvinces}} {
+    if _, ok := t.regions[strings.ToUpper(name)]; ok && len(name) == 2 {
+      return strings.ToUpper(name), t.country, true
+    }
+    for abbr, n := range t.regions {
+      if strings.EqualFold(n, name) {
+        return abbr, t.country, true
+      }
+    }
+  }
+  return "", "", false
+}
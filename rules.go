@@ -0,0 +1,565 @@
+/*
+* rules.go
+*
+* This file is part of wu.  It contains the threshold rules engine
+* used for custom notifications (the "rules" list in .condrc and
+* the --rules switch).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 03:02:06 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "encoding/json"
+  "fmt"
+  "io/ioutil"
+  "os"
+  "os/exec"
+  "regexp"
+  "strconv"
+  "strings"
+  "time"
+)
+
+// Rule is a threshold rule from the "rules" list in .condrc:
+//
+//   "rules": [
+//     {"name": "gusty", "when": "wind_gust_mph > 35", "actions": ["print", "webhook"]},
+//     {"name": "frost", "when": "temp_f < 28 between 22:00 and 07:00", "cooldown": "12h"},
+//     {"name": "storm", "when": "pressure_trend == falling && pressure_in < 29.6",
+//      "actions": ["exec"], "command": "notify-send \"$WU_MESSAGE\""}
+//   ]
+//
+// Conditions compare a field with a number using ==, !=, <, <=, > or
+// >=, or a word with == or !=, and can be combined with &&, || and
+// parentheses.  Words containing spaces are quoted: weather ==
+// "Partly Cloudy".  An
+// optional trailing "between HH:MM and HH:MM" limits the rule to
+// observations made during that time of day.  Actions are "print"
+// (the default), "webhook" and "exec"; a rule that has fired won't
+// fire again for the same observation or forecast day until its
+// cooldown (default 1h) has passed.  Print writes to standard error
+// when --format is json or csv, so as not to spoil the output.
+type Rule struct {
+  Name     string
+  When     string
+  Actions  []string
+  Command  string
+  Cooldown string
+}
+
+const defaultCooldown = time.Hour
+
+// Fields available to rules evaluated against current conditions
+var currentFields = map[string]func(c *Current) interface{}{
+  "temp_f":          func(c *Current) interface{} { return c.Temp_f },
+  "temp_c":          func(c *Current) interface{} { return c.Temp_c },
+  "dewpoint_f":      func(c *Current) interface{} { return c.Dewpoint_f },
+  "dewpoint_c":      func(c *Current) interface{} { return c.Dewpoint_c },
+  "feelslike_f":     func(c *Current) interface{} { return c.Feels_like_f() },
+  "feelslike_c":     func(c *Current) interface{} { return c.Feels_like_c() },
+  "humidity":        func(c *Current) interface{} { return ParseNumber(c.Relative_humidity) },
+  "wind_mph":        func(c *Current) interface{} { return c.Wind_mph },
+  "wind_kph":        func(c *Current) interface{} { return c.Wind_kph },
+  "wind_gust_mph":   func(c *Current) interface{} { return c.Wind_gust_mph },
+  "wind_gust_kph":   func(c *Current) interface{} { return c.Wind_gust_kph },
+  "wind_degrees":    func(c *Current) interface{} { return c.Wind_degrees },
+  "pressure_in":     func(c *Current) interface{} { return ParseNumber(c.Pressure_in) },
+  "pressure_mb":     func(c *Current) interface{} { return ParseNumber(c.Pressure_mb) },
+  "pressure_trend":  func(c *Current) interface{} { return trendWord(c.Pressure_trend) },
+  "pressure_change": func(c *Current) interface{} { return c.Tendency.Change },
+  "visibility_mi":   func(c *Current) interface{} { return ParseNumber(c.Visibility_mi) },
+  "visibility_km":   func(c *Current) interface{} { return ParseNumber(c.Visibility_km) },
+  "precip_1hr_in":   func(c *Current) interface{} { return c.Precip_1hr_in },
+  "precip_today_in": func(c *Current) interface{} { return c.Precip_today_in },
+  "uv":              func(c *Current) interface{} { return c.Uv },
+  "weather":         func(c *Current) interface{} { return c.Weather },
+}
+
+// Fields available to rules evaluated against each forecast day
+var forecastFields = map[string]func(f *Forecastdetail) interface{}{
+  "high_f":      func(f *Forecastdetail) interface{} { return f.High.Fahrenheit },
+  "high_c":      func(f *Forecastdetail) interface{} { return f.High.Celsius },
+  "low_f":       func(f *Forecastdetail) interface{} { return f.Low.Fahrenheit },
+  "low_c":       func(f *Forecastdetail) interface{} { return f.Low.Celsius },
+  "frost":       func(f *Forecastdetail) interface{} { return forecastFrost(f) },
+  "pop":         func(f *Forecastdetail) interface{} { return f.Pop },
+  "qpf_in":      func(f *Forecastdetail) interface{} { return f.Qpf_allday.In },
+  "qpf_mm":      func(f *Forecastdetail) interface{} { return f.Qpf_allday.Mm },
+  "wind_mph":    func(f *Forecastdetail) interface{} { return f.Maxwind.Mph },
+  "wind_kph":    func(f *Forecastdetail) interface{} { return f.Maxwind.Kph },
+  "avewind_mph": func(f *Forecastdetail) interface{} { return f.Avewind.Mph },
+  "humidity":    func(f *Forecastdetail) interface{} { return f.Avehumidity },
+  "conditions":  func(f *Forecastdetail) interface{} { return f.Conditions },
+}
+
+// trendWord turns the API's pressure trend symbol into a word
+// that rules can compare against
+func trendWord(trend string) string {
+  switch trend {
+  case "+":
+    return "rising"
+  case "-":
+    return "falling"
+  case "0":
+    return "steady"
+  }
+  return ""
+}
+
+// ruleExpr is a parsed rule condition, evaluated against a field lookup
+type ruleExpr interface {
+  eval(lookup func(field string) (interface{}, bool)) bool
+}
+
+type andExpr []ruleExpr
+type orExpr []ruleExpr
+
+type cmpExpr struct {
+  field string
+  op    string
+  value string
+}
+
+func (e andExpr) eval(lookup func(string) (interface{}, bool)) bool {
+  for _, sub := range e {
+    if !sub.eval(lookup) {
+      return false
+    }
+  }
+  return true
+}
+
+func (e orExpr) eval(lookup func(string) (interface{}, bool)) bool {
+  for _, sub := range e {
+    if sub.eval(lookup) {
+      return true
+    }
+  }
+  return false
+}
+
+// eval compares a field with the rule's value (CompileRule has
+// checked that the two are alike).  Missing fields and measurements
+// never match.
+func (e cmpExpr) eval(lookup func(string) (interface{}, bool)) bool {
+  v, ok := lookup(e.field)
+  if !ok {
+    return false
+  }
+  switch v := v.(type) {
+  case Number:
+    want, err := strconv.ParseFloat(e.value, 64)
+    if err != nil || !v.Valid() {
+      return false
+    }
+    return compare(float64(v), e.op, want)
+  case string:
+    if v == "" {
+      return false
+    }
+    switch e.op {
+    case "==":
+      return strings.EqualFold(v, e.value)
+    case "!=":
+      return !strings.EqualFold(v, e.value)
+    }
+  }
+  return false
+}
+
+func compare(a float64, op string, b float64) bool {
+  switch op {
+  case "==":
+    return a == b
+  case "!=":
+    return a != b
+  case "<":
+    return a < b
+  case "<=":
+    return a <= b
+  case ">":
+    return a > b
+  case ">=":
+    return a >= b
+  }
+  return false
+}
+
+// compiledRule is a Rule with its condition parsed
+type compiledRule struct {
+  Rule
+  expr     ruleExpr
+  from, to int // minutes after midnight, or -1 for any time
+  cooldown time.Duration
+}
+
+var ruleToken = regexp.MustCompile(`"[^"]*"|'[^']*'|&&|\|\||==|!=|<=|>=|<|>|\(|\)|[^\s()<>=!&|"']+`)
+var clockTime = regexp.MustCompile(`^([01]?[0-9]|2[0-3]):([0-5][0-9])$`)
+
+// CompileRule parses a rule's condition, checking that it only uses
+// known fields and operators
+func CompileRule(r Rule) (*compiledRule, error) {
+  c := &compiledRule{Rule: r, from: -1, to: -1, cooldown: defaultCooldown}
+  if r.Cooldown != "" {
+    d, err := time.ParseDuration(r.Cooldown)
+    if err != nil {
+      return nil, fmt.Errorf("rule %q: bad cooldown: %v", r.Name, err)
+    }
+    c.cooldown = d
+  }
+
+  tokens := ruleToken.FindAllString(r.When, -1)
+  if n := len(tokens); n >= 4 && strings.EqualFold(tokens[n-4], "between") && strings.EqualFold(tokens[n-2], "and") {
+    from, to := clockMinutes(tokens[n-3]), clockMinutes(tokens[n-1])
+    if from < 0 || to < 0 {
+      return nil, fmt.Errorf("rule %q: times must be written HH:MM", r.Name)
+    }
+    c.from, c.to = from, to
+    tokens = tokens[:n-4]
+  }
+
+  p := &ruleParser{tokens: tokens}
+  expr, err := p.parseOr()
+  if err == nil && p.pos < len(p.tokens) {
+    err = fmt.Errorf("unexpected %q", p.tokens[p.pos])
+  }
+  if err != nil {
+    return nil, fmt.Errorf("rule %q: %v", r.Name, err)
+  }
+  c.expr = expr
+
+  for _, action := range r.Actions {
+    switch action {
+    case "print", "webhook":
+    case "exec":
+      if r.Command == "" {
+        return nil, fmt.Errorf("rule %q: the exec action needs a command", r.Name)
+      }
+    default:
+      return nil, fmt.Errorf("rule %q: unknown action %q", r.Name, action)
+    }
+  }
+  return c, nil
+}
+
+func clockMinutes(s string) int {
+  m := clockTime.FindStringSubmatch(s)
+  if m == nil {
+    return -1
+  }
+  h, _ := strconv.Atoi(m[1])
+  min, _ := strconv.Atoi(m[2])
+  return h*60 + min
+}
+
+type ruleParser struct {
+  tokens []string
+  pos    int
+}
+
+func (p *ruleParser) next() string {
+  if p.pos >= len(p.tokens) {
+    return ""
+  }
+  t := p.tokens[p.pos]
+  p.pos++
+  return t
+}
+
+func (p *ruleParser) peek() string {
+  if p.pos >= len(p.tokens) {
+    return ""
+  }
+  return p.tokens[p.pos]
+}
+
+func (p *ruleParser) parseOr() (ruleExpr, error) {
+  var or orExpr
+  for {
+    e, err := p.parseAnd()
+    if err != nil {
+      return nil, err
+    }
+    or = append(or, e)
+    if p.peek() != "||" {
+      break
+    }
+    p.next()
+  }
+  if len(or) == 1 {
+    return or[0], nil
+  }
+  return or, nil
+}
+
+func (p *ruleParser) parseAnd() (ruleExpr, error) {
+  var and andExpr
+  for {
+    e, err := p.parseTerm()
+    if err != nil {
+      return nil, err
+    }
+    and = append(and, e)
+    if p.peek() != "&&" {
+      break
+    }
+    p.next()
+  }
+  if len(and) == 1 {
+    return and[0], nil
+  }
+  return and, nil
+}
+
+func (p *ruleParser) parseTerm() (ruleExpr, error) {
+  if p.peek() == "(" {
+    p.next()
+    e, err := p.parseOr()
+    if err != nil {
+      return nil, err
+    }
+    if p.next() != ")" {
+      return nil, fmt.Errorf("missing )")
+    }
+    return e, nil
+  }
+
+  field, op, value := strings.ToLower(p.next()), p.next(), p.next()
+  if field == "" {
+    return nil, fmt.Errorf("incomplete condition")
+  }
+  _, isCurrent := currentFields[field]
+  _, isForecast := forecastFields[field]
+  if !isCurrent && !isForecast {
+    return nil, fmt.Errorf("unknown field %q", field)
+  }
+  switch op {
+  case "==", "!=", "<", "<=", ">", ">=":
+  default:
+    return nil, fmt.Errorf("expected a comparison after %s", field)
+  }
+  if n := len(value); n >= 2 && (value[0] == '"' || value[0] == '\'') {
+    value = value[1 : n-1]
+  } else if value == "" || value == "(" || value == ")" || value == "&&" || value == "||" {
+    return nil, fmt.Errorf("expected a value after %s %s", field, op)
+  }
+
+  // A comparison between a measurement and a word would never match,
+  // so it is refused here rather than left to fail silently.
+  if numericField(field) {
+    if _, err := strconv.ParseFloat(value, 64); err != nil {
+      return nil, fmt.Errorf("%s is a number, so can't be compared with %q", field, value)
+    }
+  } else if op != "==" && op != "!=" {
+    return nil, fmt.Errorf("%s is a word, so can only be compared with == or !=", field)
+  }
+  return cmpExpr{field, op, value}, nil
+}
+
+// numericField reports whether a field holds a measurement rather
+// than a word
+func numericField(field string) bool {
+  if get, ok := currentFields[field]; ok {
+    _, number := get(&Current{}).(Number)
+    return number
+  }
+  _, number := forecastFields[field](&Forecastdetail{}).(Number)
+  return number
+}
+
+// within reports whether t falls inside the rule's time-of-day window
+func (c *compiledRule) within(t time.Time) bool {
+  if c.from < 0 {
+    return true
+  }
+  now := t.Hour()*60 + t.Minute()
+  if c.from <= c.to {
+    return now >= c.from && now <= c.to
+  }
+  return now >= c.from || now <= c.to // window spans midnight
+}
+
+// badRules remembers the malformed rules already warned about, so
+// that a watch doesn't repeat the warning at every check
+var badRules = map[string]bool{}
+
+// CompileRules compiles the rules in the configuration.  Malformed
+// rules are skipped with a warning, leaving the others to run.
+func CompileRules() []*compiledRule {
+  var rules []*compiledRule
+  for _, r := range conf.Rules {
+    c, err := CompileRule(r)
+    if err != nil {
+      if !badRules[err.Error()] {
+        fmt.Fprintf(os.Stderr, "Skipping %v\n", err)
+        badRules[err.Error()] = true
+      }
+      continue
+    }
+    rules = append(rules, c)
+  }
+  return rules
+}
+
+// label names a rule in messages and in the cooldown state; a rule
+// without a name is known by its condition
+func (c *compiledRule) label() string {
+  if c.Name != "" {
+    return c.Name
+  }
+  return c.When
+}
+
+// CheckRules evaluates the configured rules against current
+// conditions and/or each day of a forecast (either may be nil) and
+// fires the actions of those that match and aren't cooling down.
+func CheckRules(station string, current *Current, forecast *ForecastConditions) {
+  if len(conf.Rules) == 0 {
+    return
+  }
+  rules := CompileRules()
+  path := StateFile("rules", station)
+  fired := map[string]time.Time{}
+  if b, err := ioutil.ReadFile(path); err == nil {
+    json.Unmarshal(b, &fired)
+  }
+  now := time.Now()
+
+  for _, r := range rules {
+    if current != nil {
+      lookup := func(field string) (interface{}, bool) {
+        get, ok := currentFields[field]
+        if !ok {
+          return nil, false
+        }
+        return get(current), true
+      }
+      if r.within(observedAt(current, now)) && r.expr.eval(lookup) {
+        r.fire(fired, r.label()+"@current", now, Notification{
+          Event:   "rule",
+          Station: station,
+          Title:   fmt.Sprintf("Rule %q matched at %s", r.label(), station),
+          Text:    fmt.Sprintf("%s (%s)", r.When, current.Observation_time),
+          Current: current,
+        })
+      }
+    }
+    if forecast != nil {
+      for i := range forecast.Forecast.Simpleforecast.Forecastday {
+        day := &forecast.Forecast.Simpleforecast.Forecastday[i]
+        lookup := func(field string) (interface{}, bool) {
+          get, ok := forecastFields[field]
+          if !ok {
+            return nil, false
+          }
+          return get(day), true
+        }
+        // Forecast days span the whole day, so time windows don't apply.
+        if r.expr.eval(lookup) {
+          r.fire(fired, r.label()+"@"+day.Date.Epoch, now, Notification{
+            Event:   "rule",
+            Station: station,
+            Title:   fmt.Sprintf("Rule %q matched for %s", r.label(), day.Date.Weekday),
+            Text:    fmt.Sprintf("%s (forecast: %s)", r.When, day.Conditions),
+          })
+        }
+      }
+    }
+  }
+
+  // Forget firings that have long since cooled down.
+  for key, t := range fired {
+    if now.Sub(t) > 7*24*time.Hour {
+      delete(fired, key)
+    }
+  }
+  if b, err := json.MarshalIndent(fired, "", "  "); err == nil {
+    if err := ioutil.WriteFile(path, b, 0600); err != nil {
+      fmt.Fprintf(os.Stderr, "Could not save rule state: %v\n", err)
+    }
+  }
+}
+
+// observedAt returns the time the reading was taken, or now if the
+// provider didn't give one.  (Local_epoch is when the request was
+// served, which changes on every call.)
+func observedAt(c *Current, now time.Time) time.Time {
+  if epoch, err := strconv.ParseInt(c.Observation_epoch, 10, 64); err == nil && epoch > 0 {
+    return time.Unix(epoch, 0)
+  }
+  return now
+}
+
+// fire runs a matched rule's actions unless it fired for the same
+// target within its cooldown
+func (c *compiledRule) fire(fired map[string]time.Time, key string, now time.Time, n Notification) {
+  if last, ok := fired[key]; ok && now.Sub(last) < c.cooldown {
+    return
+  }
+  fired[key] = now
+  n.Time = now.Format(time.RFC3339)
+
+  actions := c.Actions
+  if len(actions) == 0 {
+    actions = []string{"print"}
+  }
+  for _, action := range actions {
+    switch action {
+    case "print":
+      out := os.Stdout
+      if outputFormat != "" && outputFormat != "text" {
+        out = os.Stderr
+      }
+      fmt.Fprintf(out, "%s: %s\n", n.Title, n.Text)
+    case "webhook":
+      Notify(n)
+    case "exec":
+      cmd := exec.Command("sh", "-c", c.Command)
+      cmd.Env = append(os.Environ(),
+        "WU_RULE="+c.label(), "WU_STATION="+n.Station, "WU_MESSAGE="+n.Title+": "+n.Text)
+      cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+      if err := cmd.Run(); err != nil {
+        fmt.Fprintf(os.Stderr, "Rule %q: command failed: %v\n", c.label(), err)
+      }
+    }
+  }
+}
+
+// RunRules fetches the current conditions and forecast for a station
+// and checks them against the rules; it's what --rules does
+func RunRules(station string) {
+  var cond Conditions
+  if err := Get("conditions", station, &cond); err != nil {
+    fmt.Fprintf(os.Stderr, "%s: %v\n", time.Now().Format(time.Kitchen), err)
+  } else {
+    NoteObservation(station, &cond.Current_observation)
+    CheckRules(station, &cond.Current_observation, nil)
+  }
+  var fc ForecastConditions
+  if err := Get("forecast10day", station, &fc); err != nil {
+    fmt.Fprintf(os.Stderr, "%s: %v\n", time.Now().Format(time.Kitchen), err)
+  } else {
+    CheckRules(station, nil, &fc)
+  }
+}
@@ -0,0 +1,144 @@
+/*
+* rules_test.go
+*
+* This file is part of wu.  It contains tests for the threshold rules
+* engine.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 03:02:06 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "strings"
+  "testing"
+  "time"
+)
+
+func TestCompileRule(t *testing.T) {
+  fields := map[string]interface{}{
+    "temp_f":         Number(25),
+    "wind_gust_mph":  Number(40),
+    "pressure_in":    Number(29.5),
+    "pressure_trend": "falling",
+    "weather":        "Partly Cloudy",
+  }
+  lookup := func(field string) (interface{}, bool) {
+    v, ok := fields[field]
+    return v, ok
+  }
+  tests := []struct {
+    when  string
+    match bool
+    err   string // part of the error, if it shouldn't compile
+  }{
+    {"temp_f < 28", true, ""},
+    {"temp_f >= 25.5", false, ""},
+    {"TEMP_F == 25", true, ""},
+    {"humidity > 50", false, ""}, // missing
+    // && binds more tightly than ||
+    {"temp_f > 80 && wind_gust_mph > 35 || pressure_in < 29.6", true, ""},
+    {"temp_f > 80 && (wind_gust_mph > 35 || pressure_in < 29.6)", false, ""},
+    {"temp_f > 80 || wind_gust_mph > 35 && pressure_in > 30", false, ""},
+    {"(temp_f > 80 || wind_gust_mph > 35) && pressure_trend == falling", true, ""},
+    {`weather == "partly cloudy"`, true, ""},
+    {"weather == 'Partly Cloudy' && pressure_trend != rising", true, ""},
+    {"weather != 'Partly Cloudy'", false, ""},
+    {"temp_f < '28'", true, ""},
+    {"temp_f < 28 between 22:00 and 07:00", true, ""},
+
+    {"temp_f > warm", false, "is a number"},
+    {"temp_f > 'cold'", false, "is a number"},
+    {"weather < 'Rain'", false, "is a word"},
+    {"pressure_trend >= steady", false, "is a word"},
+    {"dew > 50", false, "unknown field"},
+    {"temp_f 50", false, "expected a comparison"},
+    {"temp_f <", false, "expected a value"},
+    {"(temp_f < 28", false, "missing )"},
+    {"temp_f < 28 weather", false, "unexpected"},
+    {"temp_f < 28 between 22:00 and 7pm", false, "HH:MM"},
+  }
+  for _, test := range tests {
+    c, err := CompileRule(Rule{Name: "test", When: test.when})
+    if test.err != "" {
+      if err == nil || !strings.Contains(err.Error(), test.err) {
+        t.Errorf("%s: error %v; want one about %q", test.when, err, test.err)
+      }
+      continue
+    }
+    if err != nil {
+      t.Errorf("%s: %v", test.when, err)
+      continue
+    }
+    if match := c.expr.eval(lookup); match != test.match {
+      t.Errorf("%s: matched %v; want %v", test.when, match, test.match)
+    }
+  }
+}
+
+func TestRuleWindow(t *testing.T) {
+  c, err := CompileRule(Rule{When: "temp_f < 28 between 22:00 and 07:00"})
+  if err != nil {
+    t.Fatal(err)
+  }
+  for _, test := range []struct {
+    hour, min int
+    within    bool
+  }{
+    {22, 0, true}, {23, 30, true}, {3, 0, true}, {7, 0, true}, {7, 1, false}, {12, 0, false},
+  } {
+    at := time.Date(2020, 1, 1, test.hour, test.min, 0, 0, time.Local)
+    if c.within(at) != test.within {
+      t.Errorf("%02d:%02d within 22:00-07:00 is %v; want %v", test.hour, test.min, !test.within, test.within)
+    }
+  }
+}
+
+func TestRuleCooldown(t *testing.T) {
+  if _, err := CompileRule(Rule{When: "temp_f < 28", Cooldown: "soon"}); err == nil {
+    t.Errorf("a cooldown of %q compiled", "soon")
+  }
+  c, err := CompileRule(Rule{Name: "frost", When: "temp_f < 28", Cooldown: "2h"})
+  if err != nil {
+    t.Fatal(err)
+  }
+  fired := map[string]time.Time{}
+  start := time.Date(2020, 1, 1, 22, 0, 0, 0, time.UTC)
+  for _, test := range []struct {
+    after time.Duration
+    key   string
+    fires bool
+  }{
+    {0, "frost@current", true},
+    {time.Hour, "frost@current", false},
+    {time.Hour, "frost@1577923200", true}, // another target
+    {2 * time.Hour, "frost@current", true},
+    {3 * time.Hour, "frost@current", false},
+  } {
+    out := stdout(t, func() {
+      c.fire(fired, test.key, start.Add(test.after), Notification{Title: "Rule \"frost\" matched", Text: "temp_f < 28"})
+    })
+    if fires := out != ""; fires != test.fires {
+      t.Errorf("%s after %v: fired %v; want %v", test.key, test.after, fires, test.fires)
+    }
+  }
+}
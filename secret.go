@@ -0,0 +1,119 @@
+/*
+* secret.go
+*
+* This file is part of wu.  It contains functions for loading
+* the API key from a file, the environment or a command, and for
+* keeping it out of messages.
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 00:24:32 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "errors"
+  "fmt"
+  "io/ioutil"
+  "os"
+  "os/exec"
+  "regexp"
+  "strings"
+)
+
+// ResolveKey sets the API key from wherever the configuration says
+// to find it: $WU_KEY, then the environment variable named by
+// "key_env", then the file named by "key_file", then the output of
+// "key_command", and only then "key" itself
+func ResolveKey(c *Config) error {
+  if os.Getenv("WU_KEY") != "" {
+    return nil
+  }
+  switch {
+  case c.Key_env != "":
+    c.Key = os.Getenv(c.Key_env)
+    if c.Key == "" {
+      return fmt.Errorf("key_env names $%s, which is not set", c.Key_env)
+    }
+  case c.Key_file != "":
+    path := ExpandHome(c.Key_file)
+    b, err := ioutil.ReadFile(path)
+    if err != nil {
+      return fmt.Errorf("can't read key_file: %v", err)
+    }
+    if info, err := os.Stat(path); err == nil && info.Mode().Perm()&0077 != 0 {
+      warnReadable(path)
+    }
+    c.Key = strings.TrimSpace(string(b))
+  case c.Key_command != "":
+    out, err := exec.Command("sh", "-c", c.Key_command).Output()
+    if err != nil {
+      return fmt.Errorf("key_command failed: %v", err)
+    }
+    c.Key = strings.TrimSpace(string(out))
+  }
+  return nil
+}
+
+// CheckPermissions warns when a configuration file holding the key
+// itself can be read by other users
+func CheckPermissions(path string, c *Config) {
+  if path == "" || c.Key == "" {
+    return
+  }
+  if info, err := os.Stat(path); err == nil && info.Mode().Perm()&0077 != 0 {
+    warnReadable(path)
+  }
+}
+
+func warnReadable(path string) {
+  fmt.Fprintf(os.Stderr, "Warning: %s holds your API key and can be read by other users (chmod 600 %s)\n", path, path)
+}
+
+// keyInURL matches the key in an API URL
+var keyInURL = regexp.MustCompile(`/api/[^/]+/`)
+
+// Redact removes the API key from a message
+func Redact(s string) string {
+  if len(conf.Key) >= 4 {
+    s = strings.Replace(s, conf.Key, "REDACTED", -1)
+  }
+  return keyInURL.ReplaceAllString(s, "/api/REDACTED/")
+}
+
+// RedactError removes the API key from an error message
+func RedactError(err error) error {
+  if err == nil {
+    return nil
+  }
+  if s := Redact(err.Error()); s != err.Error() {
+    return errors.New(s)
+  }
+  return err
+}
+
+// MaskKey shows just enough of a key to tell which one it is
+func MaskKey(key string) string {
+  if len(key) <= 4 {
+    return strings.Repeat("*", len(key))
+  }
+  return strings.Repeat("*", len(key)-4) + key[len(key)-4:]
+}
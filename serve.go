@@ -0,0 +1,148 @@
+/*
+* serve.go
+*
+* This file is part of wu.  It contains functions related to
+* the serve subcommand (wu as a long-running server).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 03:05:43 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "encoding/json"
+  "flag"
+  "fmt"
+  "net/http"
+  "os"
+  "strings"
+  "sync"
+  "time"
+)
+
+// server polls the API for a set of stations on a schedule and
+// answers requests from what it has already fetched
+type server struct {
+  cache    *Cache
+  stations []string
+  refresh  time.Duration
+
+  mu      sync.Mutex
+  current map[string]*Current
+  updated map[string]time.Time
+}
+
+// Serve runs wu as a server (wu serve --metrics :9120 --http :8080)
+func Serve(args []string) {
+  ReadConf()
+  flags := flag.NewFlagSet("serve", flag.ExitOnError)
+  metrics := flags.String("metrics", "", "Address for the Prometheus metrics endpoint, e.g. --metrics=\":9120\"")
+  api := flags.String("http", "", "Address for the JSON API, e.g. --http=\":8080\"")
+  refresh := flags.Duration("refresh", 10*time.Minute, "How long fetched data is reused before it is refreshed")
+  stations := flags.String("stations", "", "Comma-separated list of stations (defaults to \"stations\" or \"station\" in .condrc)")
+  flags.Parse(args)
+
+  if *metrics == "" && *api == "" {
+    fmt.Println("Usage: wu serve [--metrics=ADDRESS] [--http=ADDRESS] [--refresh=10m] [--stations=LIST]")
+    flags.PrintDefaults()
+    os.Exit(0)
+  }
+  if *refresh < time.Minute {
+    fmt.Fprintln(os.Stderr, "The --refresh interval must be at least one minute.")
+    os.Exit(1)
+  }
+
+  s := newServer(ConfiguredStations(*stations), *refresh)
+  errs := make(chan error)
+
+  if *metrics != "" {
+    go s.poll()
+    mux := http.NewServeMux()
+    mux.HandleFunc("/metrics", s.serveMetrics)
+    fmt.Printf("Serving metrics for %s on %s\n", strings.Join(s.stations, ", "), *metrics)
+    go func() { errs <- http.ListenAndServe(*metrics, mux) }()
+  }
+  if *api != "" {
+    fmt.Printf("Serving the JSON API on %s\n", *api)
+    go func() { errs <- http.ListenAndServe(*api, s.apiHandler()) }()
+  }
+  CheckError(<-errs)
+}
+
+func newServer(stations []string, refresh time.Duration) *server {
+  return &server{
+    cache:    NewCache(refresh),
+    stations: stations,
+    refresh:  refresh,
+    current:  map[string]*Current{},
+    updated:  map[string]time.Time{},
+  }
+}
+
+// ConfiguredStations returns the stations to use in the long-running
+// modes: those given on the command line, else those in .condrc
+func ConfiguredStations(list string) []string {
+  var stations []string
+  if list != "" {
+    stations = strings.Split(list, ",")
+  } else if len(conf.Stations) > 0 {
+    stations = append([]string(nil), conf.Stations...) // normalized below, leaving the config alone
+  } else if conf.Station != "" {
+    stations = []string{conf.Station}
+  } else {
+    stations = []string{defaultStation}
+  }
+  for i, station := range stations {
+    normalized, err := NormalizeStation(station)
+    CheckError(err)
+    stations[i] = normalized
+  }
+  return stations
+}
+
+// poll refreshes the current conditions for every station, forever
+func (s *server) poll() {
+  for {
+    for _, station := range s.stations {
+      s.refreshConditions(station)
+    }
+    time.Sleep(s.refresh)
+  }
+}
+
+func (s *server) refreshConditions(station string) {
+  e, err := s.cache.Get("conditions", "", station)
+  if err != nil {
+    fmt.Fprintf(os.Stderr, "%s: %s: %v\n", time.Now().Format(time.Kitchen), station, err)
+    return
+  }
+  var obs Conditions
+  if err := json.Unmarshal(e.Body, &obs); err != nil {
+    fmt.Fprintf(os.Stderr, "%s: %s: %v\n", time.Now().Format(time.Kitchen), station, err)
+    return
+  }
+  NoteObservation(station, &obs.Current_observation)
+  s.mu.Lock()
+  s.current[station] = &obs.Current_observation
+  s.updated[station] = e.Fetched
+  s.mu.Unlock()
+}
@@ -0,0 +1,255 @@
+/*
+* templates.go
+*
+* This file is part of wu.  It contains the functions for
+* rendering reports through text/template (the --template switch
+* and the "templates" section of .condrc).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 03:13:25 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "fmt"
+  "io"
+  "math"
+  "os"
+  "path/filepath"
+  "regexp"
+  "strings"
+  "text/template"
+  "time"
+)
+
+// builtinTemplates are the standard layouts of the reports.  Each
+// lives alongside the report's data types.
+var builtinTemplates = map[string]string{
+  "alerts":      alertsTemplate,
+  "almanac":     almanacTemplate,
+  "anomaly":     anomalyTemplate,
+  "astro":       astroTemplate,
+  "climate":     climateTemplate,
+  "climateyear": climateYearTemplate,
+  "conditions":  conditionsTemplate,
+  "forecast":    forecastTemplate,
+  "forecast10":  forecast10Template,
+  "frost":       frostTemplate,
+  "history":     historyTemplate,
+  "hourly":      hourlyTemplate,
+  "lookup":      lookupTemplate,
+  "planner":     plannerTemplate,
+  "tides":       tidesTemplate,
+}
+
+// rendering is the context of the report being rendered, for the
+// station and degrees template functions
+var rendering struct {
+  station string
+  degrees string
+}
+
+// templateFuncs are the helper functions available to templates
+var templateFuncs = template.FuncMap{
+  "station": func() string { return rendering.station },
+  "degrees": func() string { return rendering.degrees },
+  "celsius": func() bool { return rendering.degrees == "C" },
+  "verbose": func() bool { return verbose },
+  "derived": func() bool { return derived },
+  "zambretti": func() bool { return zambretti },
+
+  "num":  toFloat,
+  "atoi": func(v interface{}) int { return int(toFloat(v)) },
+  "add":  func(a, b interface{}) float64 { return toFloat(a) + toFloat(b) },
+  "sub":  func(a, b interface{}) float64 { return toFloat(a) - toFloat(b) },
+  "fixed": func(places int, v interface{}) string {
+    return formatNumber(Number(toFloat(v)), places)
+  },
+  "measured": func(places int, v interface{}) string {
+    n := Number(toFloat(v))
+    if !n.Valid() {
+      return "M"
+    }
+    return formatNumber(n, places)
+  },
+  "signed": func(places int, v interface{}) string {
+    n := toFloat(v)
+    if math.IsNaN(n) {
+      return "M"
+    }
+    return fmt.Sprintf("%+.*f", places, n)
+  },
+  "amount": func(places int, v interface{}, trace bool) string {
+    if trace {
+      return "T"
+    }
+    n := Number(toFloat(v))
+    if !n.Valid() {
+      return "M"
+    }
+    return formatNumber(n, places)
+  },
+
+  "ftoc":     func(v interface{}) float64 { return (toFloat(v) - 32) * 5 / 9 },
+  "ctof":     func(v interface{}) float64 { return toFloat(v)*9/5 + 32 },
+  "intomb":   func(v interface{}) float64 { return toFloat(v) * 33.8639 },
+  "mbtoin":   func(v interface{}) float64 { return toFloat(v) / 33.8639 },
+  "mphtokph": func(v interface{}) float64 { return toFloat(v) * 1.609344 },
+  "kphtomph": func(v interface{}) float64 { return toFloat(v) / 1.609344 },
+  "mitokm":   func(v interface{}) float64 { return toFloat(v) * 1.609344 },
+  "kmtomi":   func(v interface{}) float64 { return toFloat(v) / 1.609344 },
+  "intomm":   func(v interface{}) float64 { return toFloat(v) * 25.4 },
+  "mmtoin":   func(v interface{}) float64 { return toFloat(v) / 25.4 },
+  "mtoft":    func(v interface{}) float64 { return toFloat(v) / 0.3048 },
+  "fttom":    func(v interface{}) float64 { return toFloat(v) * 0.3048 },
+  "convert":  Convert,
+  "temps": func(f, c interface{}) string {
+    if rendering.degrees == "C" {
+      return formatNumber(Number(toFloat(c)), 0) + " C (" + formatNumber(Number(toFloat(f)), 0) + " F)"
+    }
+    return tempString(Number(toFloat(f)), Number(toFloat(c)))
+  },
+
+  "temp":    StyleTemp,
+  "alert":   StyleAlert,
+  "precip":  StylePrecip,
+  "heading": StyleHeading,
+  "sky":     StyleSky,
+  "color":   Style,
+
+  "compass": func(v interface{}) string { return boxCompass(fmt.Sprint(v)) },
+  "trend":   trendWord,
+  "moon":    func(v interface{}) string { return MoonPhase(int(toFloat(v))) },
+  "comfort": DewpointComfort,
+
+  "month": func(v interface{}) string { return MonthName(int(toFloat(v))) },
+  "longdate": func(month interface{}, day string, year string) string {
+    return LongDate(int(toFloat(month)), day, year)
+  },
+  "monthday": func(mmdd string) string {
+    var month, day int
+    fmt.Sscanf(mmdd, "%d-%d", &month, &day)
+    return fmt.Sprintf("%s %d", MonthName(month), day)
+  },
+  "T":       T,
+  "plural":  Plural,
+  "decimal": DecimalSeparator,
+  "date":  formatDate,
+
+  "matches": func(pattern string, s string) bool {
+    m, _ := regexp.MatchString(pattern, s)
+    return m
+  },
+  "upper": strings.ToUpper,
+  "lower": strings.ToLower,
+  "join":  strings.Join,
+  "pad": func(width int, v interface{}) string {
+    return fmt.Sprintf("%-*s", width, fmt.Sprint(v))
+  },
+  "wrap": func(width int, text string) string {
+    return strings.Join(wrap(text, width, ""), "\n")
+  },
+}
+
+// toFloat converts the numbers and numeric strings found in the
+// API's data to a float64 (NaN if it isn't a number)
+func toFloat(v interface{}) float64 {
+  switch v := v.(type) {
+  case Number:
+    return float64(v)
+  case float64:
+    return v
+  case int:
+    return float64(v)
+  case string:
+    return float64(ParseNumber(v))
+  }
+  return math.NaN()
+}
+
+// formatDate formats a Unix time (as the API's epoch strings) or a
+// time.Time using a Go time layout
+func formatDate(layout string, v interface{}) string {
+  switch v := v.(type) {
+  case time.Time:
+    return v.Format(layout)
+  default:
+    epoch := toFloat(v)
+    if math.IsNaN(epoch) {
+      return ""
+    }
+    return time.Unix(int64(epoch), 0).Format(layout)
+  }
+}
+
+// templatePath returns the template file to use for a report, if
+// any: the one chosen with --template (by name from .condrc, or by
+// path), else the one configured for the report
+func templatePath(report string) string {
+  path := conf.Templates[report]
+  if templateName != "" {
+    path = templateName
+    if named, ok := conf.Templates[templateName]; ok {
+      path = named
+    }
+  }
+  return ExpandHome(path)
+}
+
+// reportTemplate returns the template for a report.  A template
+// file may either be a single template used for whatever report is
+// being run, or define templates named after the reports
+// ({{define "conditions"}}...{{end}}).
+func reportTemplate(report string) (*template.Template, error) {
+  if path := templatePath(report); path != "" {
+    t, err := template.New(filepath.Base(path)).Funcs(templateFuncs).ParseFiles(path)
+    if err != nil {
+      return nil, err
+    }
+    if named := t.Lookup(report); named != nil {
+      return named, nil
+    }
+    return t, nil
+  }
+  return template.Must(template.New(report).Funcs(templateFuncs).Parse(builtinTemplates[report])), nil
+}
+
+// Render writes a report to standard out using its template
+func Render(report string, station string, degrees string, data interface{}) {
+  if outputFormat != "" && outputFormat != "text" {
+    Export(report, data)
+    return
+  }
+  CheckError(RenderText(os.Stdout, report, station, degrees, data))
+}
+
+// RenderText writes a report to w using its template, whatever the
+// --format; the dashboard renders its panes this way
+func RenderText(w io.Writer, report string, station string, degrees string, data interface{}) error {
+  t, err := reportTemplate(report)
+  if err != nil {
+    return err
+  }
+  rendering.station, rendering.degrees = station, degrees
+  return t.Execute(w, data)
+}
+
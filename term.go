@@ -0,0 +1,157 @@
+/*
+* term.go
+*
+* This file is part of wu.  It contains functions for working
+* with the terminal (size, input modes).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 01:46:15 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "os"
+  "os/exec"
+  "regexp"
+  "strconv"
+  "strings"
+  "unicode/utf8"
+)
+
+// TermSize returns the height and width of the terminal, falling
+// back on $LINES and $COLUMNS, and then on 24x80
+func TermSize() (rows int, cols int) {
+  cmd := exec.Command("stty", "size")
+  cmd.Stdin = os.Stdin
+  if out, err := cmd.Output(); err == nil {
+    fields := strings.Fields(string(out))
+    if len(fields) == 2 {
+      rows, _ = strconv.Atoi(fields[0])
+      cols, _ = strconv.Atoi(fields[1])
+    }
+  }
+  if rows <= 0 {
+    rows, _ = strconv.Atoi(os.Getenv("LINES"))
+  }
+  if cols <= 0 {
+    cols, _ = strconv.Atoi(os.Getenv("COLUMNS"))
+  }
+  if rows <= 0 {
+    rows = 24
+  }
+  if cols <= 0 {
+    cols = 80
+  }
+  return rows, cols
+}
+
+// IsTerminal reports whether f is a terminal (rather than a file or pipe)
+func IsTerminal(f *os.File) bool {
+  info, err := f.Stat()
+  return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// cbreak puts the terminal into character-at-a-time mode without
+// echo, and returns a function that restores the previous mode
+func cbreak() (restore func(), err error) {
+  save := exec.Command("stty", "-g")
+  save.Stdin = os.Stdin
+  state, err := save.Output()
+  if err != nil {
+    return nil, err
+  }
+  if err := stty("-icanon", "-echo", "min", "1"); err != nil {
+    return nil, err
+  }
+  return func() { stty(strings.TrimSpace(string(state))) }, nil
+}
+
+func stty(args ...string) error {
+  cmd := exec.Command("stty", args...)
+  cmd.Stdin = os.Stdin
+  return cmd.Run()
+}
+
+// ansiCode matches the escape sequences that color text
+var ansiCode = regexp.MustCompile("^\033\\[[0-9;]*m")
+
+// visibleWidth is the number of characters in s, leaving out color
+// codes
+func visibleWidth(s string) int {
+  n := 0
+  for s != "" {
+    if loc := ansiCode.FindStringIndex(s); loc != nil {
+      s = s[loc[1]:]
+      continue
+    }
+    _, size := utf8.DecodeRuneInString(s)
+    s = s[size:]
+    n++
+  }
+  return n
+}
+
+// truncate shortens s to at most width characters, keeping its
+// color codes and resetting the color if any were cut off
+func truncate(s string, width int) string {
+  if visibleWidth(s) <= width {
+    return s
+  }
+  var b strings.Builder
+  colored := false
+  for n := 0; s != "" && n < width; {
+    if loc := ansiCode.FindStringIndex(s); loc != nil {
+      b.WriteString(s[:loc[1]])
+      s, colored = s[loc[1]:], true
+      continue
+    }
+    _, size := utf8.DecodeRuneInString(s)
+    b.WriteString(s[:size])
+    s = s[size:]
+    n++
+  }
+  if colored {
+    b.WriteString("\033[0m")
+  }
+  return b.String()
+}
+
+// wrap breaks text into lines of at most width characters, each
+// starting with indent
+func wrap(text string, width int, indent string) []string {
+  var lines []string
+  for _, para := range strings.Split(text, "\n") {
+    line := indent
+    for _, word := range strings.Fields(para) {
+      if line != indent && visibleWidth(line)+1+visibleWidth(word) > width {
+        lines = append(lines, line)
+        line = indent
+      }
+      if line != indent {
+        line += " "
+      }
+      line += word
+    }
+    lines = append(lines, line)
+  }
+  return lines
+}
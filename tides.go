@@ -7,7 +7,7 @@
 * Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
 * and Anthony Starks.
 *
-* Last Modified: Wed Dec 18 16:12:03 CST 2013
+* Last Modified: Mon Oct 19 01:46:15 CDT 2026
 *
 * Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
 *
@@ -31,8 +31,6 @@ package main
 import (
   "fmt"
   "os"
-  "strconv"
-  "time"
 )
 
 type TideConditions struct {
@@ -58,35 +56,21 @@ type Data struct {
   Type   string
 }
 
+const tidesTemplate = `{{T "Tidal data for"}} {{(index .Tide.Tideinfo 0).Tidesite}}
+{{$prev := ""}}{{range .Tide.Tidesummary -}}
+{{$date := printf "%s:" (longdate .Date.Mon .Date.Mday .Date.Year) -}}
+{{if ne $date $prev}}{{$date}}
+{{end}}{{$prev = $date -}}
+{{$hour := atoi .Date.Hour -}}
+{{if lt $hour 12}}     {{.Data.Type}} {{T "at"}} {{if eq $hour 0}}12{{else}}{{$hour}}{{end}}:{{.Date.Min}} AM
+{{else}}     {{.Data.Type}} {{T "at"}} {{if eq $hour 12}}12{{else}}{{sub $hour 12}}{{end}}:{{.Date.Min}} PM
+{{end}}{{end}}`
+
 // printTides prints the tidal data for given station to standard out
 func PrintTides(obs *TideConditions, stationID string) {
-  tide := obs.Tide
-  info := tide.Tideinfo
-  summary := tide.Tidesummary
-
-  if len(summary) == 0 {
-    fmt.Println("No tidal data available.")
+  if len(obs.Tide.Tidesummary) == 0 {
+    fmt.Println(T("No tidal data available."))
     os.Exit(0)
   }
-
-  fmt.Printf("Tidal data for %s\n", info[0].Tidesite)
-
-  var date_string string
-  var prev_date string
-
-  for _, s := range summary {
-    month, _ := strconv.Atoi(s.Date.Mon)
-    hour, _ := strconv.Atoi(s.Date.Hour)
-    prev_date = date_string
-    date_string = time.Month(month).String() + " " + s.Date.Mday + ", " + s.Date.Year + ":"
-    if date_string != prev_date {
-      fmt.Println(date_string)
-    }
-    if hour < 13 {
-      fmt.Printf("     %s at %d:%s AM\n", s.Data.Type, hour, s.Date.Min)
-    } else {
-      hour = hour - 12
-      fmt.Printf("     %s at %d:%s PM\n", s.Data.Type, hour, s.Date.Min)
-    }
-  }
+  Render("tides", stationID, conf.Degrees, obs)
 }
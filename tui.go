@@ -0,0 +1,422 @@
+/*
+* tui.go
+*
+* This file is part of wu.  It contains functions related to
+* the tui subcommand (a full-screen dashboard).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 01:50:59 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "bytes"
+  "encoding/json"
+  "flag"
+  "fmt"
+  "os"
+  "os/signal"
+  "strconv"
+  "strings"
+  "sync"
+  "syscall"
+  "time"
+)
+
+// dashboard holds the reports fetched for one station
+type dashboard struct {
+  conditions *Conditions
+  forecast   *ForecastConditions
+  forecast10 *ForecastConditions
+  alerts     *AlertConditions
+  astro      *AstroConditions
+  tides      *TideConditions
+  errors     map[string]error
+  updated    time.Time
+}
+
+// pane is one page of the dashboard, rendered from a station's reports
+type pane struct {
+  title  string
+  render func(d *dashboard, station string, width int) []string
+}
+
+var panes = []pane{
+  {"Conditions", conditionsPane},
+  {"Forecast", forecastPane},
+  {"10-Day", forecast10Pane},
+  {"Alerts", alertsPane},
+  {"Astronomy", astroPane},
+  {"Tides", tidesPane},
+}
+
+type tui struct {
+  cache    *Cache
+  stations []string
+  refresh  time.Duration
+
+  mu      sync.Mutex
+  data    map[string]*dashboard
+  station int
+  pane    int
+  scroll  int
+
+  changed chan bool // the data has changed; redraw
+  wake    chan bool // fetch the selected station now
+}
+
+// Tui runs the full-screen dashboard (wu tui)
+func Tui(args []string) {
+  ReadConf()
+  flags := flag.NewFlagSet("tui", flag.ExitOnError)
+  refresh := flags.Duration("refresh", 10*time.Minute, "How often to refresh the reports")
+  stations := flags.String("stations", "", "Comma-separated list of stations (defaults to \"stations\" or \"station\" in .condrc)")
+  flags.StringVar(&templateName, "template", "", "Formats the panes with a text/template file, or a template named in .condrc")
+  flags.Parse(args)
+
+  if *refresh < time.Minute {
+    fmt.Fprintln(os.Stderr, "The --refresh interval must be at least one minute.")
+    os.Exit(1)
+  }
+
+  t := &tui{
+    cache:    NewCache(*refresh),
+    stations: ConfiguredStations(*stations),
+    refresh:  *refresh,
+    data:     map[string]*dashboard{},
+    changed:  make(chan bool, 1),
+    wake:     make(chan bool, 1),
+  }
+
+  restore, err := cbreak()
+  CheckError(err)
+  fmt.Print("\033[?1049h\033[?25l") // alternate screen, hidden cursor
+  defer func() {
+    fmt.Print("\033[?25h\033[?1049l")
+    restore()
+  }()
+
+  go t.poll()
+  t.run()
+}
+
+// run handles keys and redraws until the user quits
+func (t *tui) run() {
+  keys := make(chan string)
+  go readKeys(keys)
+  interrupt := make(chan os.Signal, 1)
+  signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+  resize := time.NewTicker(time.Second)
+  defer resize.Stop()
+
+  rows, cols := TermSize()
+  t.draw(rows, cols)
+  for {
+    select {
+    case key := <-keys:
+      if !t.handle(key) {
+        return
+      }
+    case <-t.changed:
+    case <-resize.C:
+      r, c := TermSize()
+      if r == rows && c == cols {
+        continue
+      }
+      rows, cols = r, c
+    case <-interrupt:
+      return
+    }
+    t.draw(rows, cols)
+  }
+}
+
+// readKeys sends each key press, with arrow keys named "up",
+// "down", "left" and "right"
+func readKeys(keys chan<- string) {
+  buf := make([]byte, 8)
+  for {
+    n, err := os.Stdin.Read(buf)
+    if err != nil {
+      close(keys)
+      return
+    }
+    switch {
+    case n >= 3 && buf[0] == 27 && buf[1] == '[':
+      switch buf[2] {
+      case 'A':
+        keys <- "up"
+      case 'B':
+        keys <- "down"
+      case 'C':
+        keys <- "right"
+      case 'D':
+        keys <- "left"
+      case 'Z':
+        keys <- "backtab"
+      }
+    case n == 1 && buf[0] == 27:
+      keys <- "esc"
+    case n >= 1:
+      keys <- string(buf[:1])
+    }
+  }
+}
+
+// handle acts on a key, returning false if the user wants to quit
+func (t *tui) handle(key string) bool {
+  t.mu.Lock()
+  defer t.mu.Unlock()
+
+  switch key {
+  case "", "q", "Q", "esc":
+    return false
+  case "\t", "right", "l":
+    t.pane = (t.pane + 1) % len(panes)
+    t.scroll = 0
+  case "backtab", "left", "h":
+    t.pane = (t.pane + len(panes) - 1) % len(panes)
+    t.scroll = 0
+  case "down", "j":
+    t.scroll++
+  case "up", "k":
+    if t.scroll > 0 {
+      t.scroll--
+    }
+  case "n", "]":
+    t.station = (t.station + 1) % len(t.stations)
+    t.scroll = 0
+    t.fetchNow()
+  case "p", "[":
+    t.station = (t.station + len(t.stations) - 1) % len(t.stations)
+    t.scroll = 0
+    t.fetchNow()
+  case "r":
+    t.cache = NewCache(t.refresh) // forget what we have
+    t.fetchNow()
+  default:
+    if n, err := strconv.Atoi(key); err == nil && n >= 1 && n <= len(panes) {
+      t.pane = n - 1
+      t.scroll = 0
+    }
+  }
+  return true
+}
+
+func (t *tui) fetchNow() {
+  select {
+  case t.wake <- true:
+  default:
+  }
+}
+
+func (t *tui) notify() {
+  select {
+  case t.changed <- true:
+  default:
+  }
+}
+
+// poll fetches the selected station's reports every refresh
+// interval, or sooner when asked to
+func (t *tui) poll() {
+  for {
+    t.mu.Lock()
+    station, cache := t.stations[t.station], t.cache
+    t.mu.Unlock()
+    t.fetch(cache, station)
+
+    select {
+    case <-time.After(t.refresh):
+    case <-t.wake:
+    }
+  }
+}
+
+// fetch loads every report for a station, updating the display as
+// each arrives
+func (t *tui) fetch(cache *Cache, station string) {
+  load := func(operation string, obs interface{}, store func(d *dashboard)) {
+    e, err := cache.Get(operation, "", station)
+    if err == nil {
+      err = json.Unmarshal(e.Body, obs)
+    }
+    t.mu.Lock()
+    d := t.dashboard(station)
+    if err != nil {
+      d.errors[operation] = err
+    } else {
+      delete(d.errors, operation)
+      store(d)
+      d.updated = time.Now()
+    }
+    t.mu.Unlock()
+    t.notify()
+  }
+
+  var cond Conditions
+  load("conditions", &cond, func(d *dashboard) { d.conditions = &cond })
+  var fc ForecastConditions
+  load("forecast", &fc, func(d *dashboard) { d.forecast = &fc })
+  var fc10 ForecastConditions
+  load("forecast10day", &fc10, func(d *dashboard) { d.forecast10 = &fc10 })
+  var alerts AlertConditions
+  load("alerts", &alerts, func(d *dashboard) { d.alerts = &alerts })
+  var astro AstroConditions
+  load("astronomy", &astro, func(d *dashboard) { d.astro = &astro })
+  var tides TideConditions
+  load("tide", &tides, func(d *dashboard) { d.tides = &tides })
+}
+
+// dashboard returns the reports for a station; t.mu must be held
+func (t *tui) dashboard(station string) *dashboard {
+  d, ok := t.data[station]
+  if !ok {
+    d = &dashboard{errors: map[string]error{}}
+    t.data[station] = d
+  }
+  return d
+}
+
+// draw redraws the whole screen
+func (t *tui) draw(rows int, cols int) {
+  t.mu.Lock()
+  defer t.mu.Unlock()
+
+  station := t.stations[t.station]
+  d := t.dashboard(station)
+
+  header := " wu: " + station + " (" + fmt.Sprintf(T("%d of %d"), t.station+1, len(t.stations)) + ")"
+  if !d.updated.IsZero() {
+    header += "   " + T("updated") + " " + d.updated.Format(time.Kitchen)
+  }
+
+  var tabs string
+  for i, p := range panes {
+    tab := fmt.Sprintf(" %d %s ", i+1, T(p.title))
+    if i == t.pane {
+      tab = "\033[7m" + tab + "\033[0m"
+    }
+    tabs += tab
+  }
+
+  body := panes[t.pane].render(d, station, cols-2)
+  height := rows - 4
+  if height < 1 {
+    height = 1
+  }
+  if t.scroll > len(body)-height {
+    t.scroll = len(body) - height
+  }
+  if t.scroll < 0 {
+    t.scroll = 0
+  }
+  body = body[t.scroll:]
+
+  var b strings.Builder
+  b.WriteString("\033[H")
+  b.WriteString("\033[1m" + truncate(header, cols) + "\033[0m\033[K\r\n")
+  b.WriteString(tabs + "\033[K\r\n\033[K\r\n")
+  for i := 0; i < height; i++ {
+    if i < len(body) {
+      b.WriteString(" " + truncate(body[i], cols-1))
+    }
+    b.WriteString("\033[K\r\n")
+  }
+  help := " " + T("Tab/←→ panes  n/p stations  ↑↓ scroll  r refresh  q quit")
+  b.WriteString("\033[2m" + truncate(help, cols) + "\033[0m\033[K")
+  os.Stdout.WriteString(b.String())
+}
+
+// unavailable explains why a report is missing
+func unavailable(d *dashboard, operation string) []string {
+  if err, ok := d.errors[operation]; ok {
+    return []string{T("Couldn't fetch this report:"), "   " + err.Error()}
+  }
+  return []string{T("Loading...")}
+}
+
+// renderPane renders a report as wu prints it, so that templates,
+// themes and the language all apply.  Text too wide for the screen
+// is wrapped; table rows (with columns lined up by runs of spaces)
+// are left for draw to cut short.
+func renderPane(report string, station string, data interface{}, width int) []string {
+  var b bytes.Buffer
+  if err := RenderText(&b, report, station, conf.Degrees, data); err != nil {
+    return []string{T("Couldn't show this report:"), "   " + err.Error()}
+  }
+  var lines []string
+  for _, line := range strings.Split(strings.TrimRight(b.String(), "\n"), "\n") {
+    text := strings.TrimLeft(line, " ")
+    if visibleWidth(line) <= width || strings.Contains(text, "  ") {
+      lines = append(lines, line)
+      continue
+    }
+    lines = append(lines, wrap(text, width, line[:len(line)-len(text)])...)
+  }
+  return lines
+}
+
+func conditionsPane(d *dashboard, station string, width int) []string {
+  if d.conditions == nil {
+    return unavailable(d, "conditions")
+  }
+  return renderPane("conditions", station, d.conditions, width)
+}
+
+func forecastPane(d *dashboard, station string, width int) []string {
+  if d.forecast == nil {
+    return unavailable(d, "forecast")
+  }
+  return renderPane("forecast", station, d.forecast, width)
+}
+
+func forecast10Pane(d *dashboard, station string, width int) []string {
+  if d.forecast10 == nil {
+    return unavailable(d, "forecast10day")
+  }
+  return renderPane("forecast10", station, d.forecast10, width)
+}
+
+func alertsPane(d *dashboard, station string, width int) []string {
+  if d.alerts == nil {
+    return unavailable(d, "alerts")
+  }
+  return renderPane("alerts", station, d.alerts, width)
+}
+
+func astroPane(d *dashboard, station string, width int) []string {
+  if d.astro == nil {
+    return unavailable(d, "astronomy")
+  }
+  return renderPane("astro", station, d.astro, width)
+}
+
+func tidesPane(d *dashboard, station string, width int) []string {
+  if d.tides == nil {
+    return unavailable(d, "tide")
+  }
+  if len(d.tides.Tide.Tidesummary) == 0 || len(d.tides.Tide.Tideinfo) == 0 {
+    return []string{T("No tidal data available.")}
+  }
+  return renderPane("tides", station, d.tides, width)
+}
@@ -6,7 +6,7 @@
 * Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
 * and Anthony Starks.
 *
-* Last Modified: Mon Aug 01 12:25:26 CDT 2016
+* Last Modified: Mon Oct 19 00:24:32 CDT 2026
 *
 * Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
 *
@@ -27,7 +27,15 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
 	"regexp"
+	"strconv"
+	"strings"
 )
 
 func Convert(temp string) string {
@@ -38,3 +46,102 @@ func Convert(temp string) string {
 
 	return newTemp
 }
+
+// StateDir returns the directory in which wu keeps the state it
+// needs between runs ($HOME/.wu), creating it if necessary.
+func StateDir() string {
+	dir := filepath.Join(os.Getenv("HOME"), ".wu")
+	CheckError(os.MkdirAll(dir, 0700))
+	return dir
+}
+
+// ExpandHome expands a leading "~/" in a path to $HOME
+func ExpandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		return filepath.Join(os.Getenv("HOME"), path[2:])
+	}
+	return path
+}
+
+// StateFile returns the path of a per-station state file in StateDir
+// (e.g. "alerts" and "NE/Lincoln" become $HOME/.wu/alerts-NE_Lincoln.json)
+func StateFile(name string, station string) string {
+	unsafe := regexp.MustCompile("[^A-Za-z0-9.-]+")
+	return filepath.Join(StateDir(), name+"-"+unsafe.ReplaceAllString(station, "_")+".json")
+}
+
+// Number is a measurement that the API sends sometimes as a JSON
+// number and sometimes as a string ("29.92", "NA", "-9999").  Missing
+// values decode as NaN, and encode as null.
+type Number float64
+
+// ParseNumber converts one of the API's string measurements (which
+// may carry a trailing "%" or unit) to a Number
+func ParseNumber(s string) Number {
+	s = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(s), "%"))
+	if fields := strings.Fields(s); len(fields) > 0 {
+		s = fields[0]
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f == -9999 || f == -999 {
+		return Number(math.NaN())
+	}
+	return Number(f)
+}
+
+func (n *Number) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if len(b) > 0 && b[0] == '"' {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		*n = ParseNumber(s)
+		return nil
+	}
+	*n = ParseNumber(string(b))
+	return nil
+}
+
+func (n Number) MarshalJSON() ([]byte, error) {
+	if !n.Valid() {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.FormatFloat(float64(n), 'f', -1, 64)), nil
+}
+
+// Valid reports whether the measurement was actually supplied
+func (n Number) Valid() bool {
+	return !math.IsNaN(float64(n)) && !math.IsInf(float64(n), 0)
+}
+
+// missingNumbers marks every Number in a struct (and the structs it
+// contains) as missing, so that fields the API leaves out don't
+// read as zero.  Types call it before decoding themselves.
+func missingNumbers(v interface{}) {
+	clearNumbers(reflect.ValueOf(v).Elem())
+}
+
+func clearNumbers(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Float64:
+		if v.Type() == reflect.TypeOf(Number(0)) {
+			v.SetFloat(math.NaN())
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				clearNumbers(v.Field(i))
+			}
+		}
+	}
+}
+
+// formatNumber formats a measurement with the given number of
+// decimal places, or as "-" if it is missing
+func formatNumber(n Number, places int) string {
+	if !n.Valid() {
+		return "-"
+	}
+	return strconv.FormatFloat(float64(n), 'f', places, 64)
+}
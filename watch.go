@@ -0,0 +1,168 @@
+/*
+* watch.go
+*
+* This file is part of wu.  It contains functions related to
+* the -conditions switch when combined with --watch (a live,
+* redrawing display of the current conditions).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 03:03:59 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "context"
+  "fmt"
+  "math"
+  "os"
+  "os/signal"
+  "syscall"
+  "time"
+)
+
+const clearScreen = "\033[H\033[2J"
+
+// maxBackoff is the longest we'll wait between attempts after the
+// API has returned errors
+const maxBackoff = time.Hour
+
+// WatchConditions redraws the current conditions every interval,
+// along with how they have changed since the previous reading.  It
+// retries less and less often while the API is failing, and exits
+// cleanly on an interrupt.
+func WatchConditions(station string, interval time.Duration) {
+  ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+  defer stop()
+  fetchContext = ctx
+  screen := IsTerminal(os.Stdout)
+
+  var previous *Current
+  var updated time.Time
+  records := &recordWatch{}
+  failures := 0
+
+  for {
+    wait := interval
+    var obs Conditions
+    if err := Get("conditions", station, &obs); err != nil {
+      if ctx.Err() != nil {
+        fmt.Println()
+        return
+      }
+      failures++
+      wait = backoff(interval, failures)
+      fmt.Fprintf(os.Stderr, "%s: %v (retrying in %s)\n", time.Now().Format(time.Kitchen), err, wait)
+    } else {
+      failures = 0
+      updated = time.Now()
+      current := &obs.Current_observation
+      NoteObservation(station, current)
+      if screen {
+        fmt.Print(clearScreen)
+      }
+      PrintConditions(&obs, conf.Degrees)
+      if previous != nil {
+        PrintChanges(previous, current, conf.Degrees)
+      }
+      fmt.Printf("\n"+T("Last updated %s (every %s; Ctrl-C to quit)")+"\n", updated.Format(time.Kitchen), interval)
+      records.check(station, current)
+      CheckRules(station, current, nil)
+      previous = current
+    }
+
+    select {
+    case <-ctx.Done():
+      fmt.Println()
+      return
+    case <-time.After(wait):
+    }
+  }
+}
+
+// backoff doubles the wait for each consecutive failure, up to maxBackoff
+func backoff(interval time.Duration, failures int) time.Duration {
+  wait := interval
+  for i := 1; i < failures && wait < maxBackoff; i++ {
+    wait *= 2
+  }
+  if wait > maxBackoff && interval < maxBackoff {
+    wait = maxBackoff
+  }
+  return wait
+}
+
+// PrintChanges prints how the conditions have changed between two readings
+func PrintChanges(prev *Current, cur *Current, degrees string) {
+  var lines []string
+
+  temp, prevTemp, unit := cur.Temp_f, prev.Temp_f, "\u00B0 F"
+  dew, prevDew := cur.Dewpoint_f, prev.Dewpoint_f
+  pressure, prevPressure, punit, pformat := ParseNumber(cur.Pressure_in), ParseNumber(prev.Pressure_in), "in", "%.2f"
+  wind, prevWind, wunit := cur.Wind_mph, prev.Wind_mph, "mph"
+  if degrees == "C" {
+    temp, prevTemp, unit = cur.Temp_c, prev.Temp_c, "\u00B0 C"
+    dew, prevDew = cur.Dewpoint_c, prev.Dewpoint_c
+    pressure, prevPressure, punit, pformat = ParseNumber(cur.Pressure_mb), ParseNumber(prev.Pressure_mb), "mb", "%.0f"
+    wind, prevWind, wunit = cur.Wind_kph, prev.Wind_kph, "km/h"
+  }
+
+  if d, ok := delta(prevTemp, temp); ok {
+    lines = append(lines, T("Temperature")+" "+direction(d, "up", "down", "unchanged")+fmt.Sprintf(" %.1f%s", math.Abs(d), unit))
+  }
+  if d, ok := delta(prevDew, dew); ok {
+    lines = append(lines, T("Dewpoint")+" "+direction(d, "up", "down", "unchanged")+fmt.Sprintf(" %.1f%s", math.Abs(d), unit))
+  }
+  if d, ok := delta(ParseNumber(prev.Relative_humidity), ParseNumber(cur.Relative_humidity)); ok {
+    lines = append(lines, T("Humidity")+" "+direction(d, "up", "down", "unchanged")+fmt.Sprintf(" %.0f%%", math.Abs(d)))
+  }
+  if d, ok := delta(prevPressure, pressure); ok {
+    lines = append(lines, T("Pressure")+" "+direction(d, "rising", "falling", "steady")+fmt.Sprintf(" "+pformat+" %s", math.Abs(d), punit))
+  }
+  if d, ok := delta(prevWind, wind); ok {
+    lines = append(lines, T("Wind")+" "+direction(d, "up", "down", "unchanged")+fmt.Sprintf(" %.0f %s", math.Abs(d), wunit))
+  }
+
+  if len(lines) > 0 {
+    fmt.Printf(T("Since %s:")+"\n", prev.Observation_time)
+    for _, l := range lines {
+      fmt.Println("   " + l)
+    }
+  }
+}
+
+func delta(prev Number, cur Number) (float64, bool) {
+  if !prev.Valid() || !cur.Valid() {
+    return 0, false
+  }
+  return float64(cur - prev), true
+}
+
+// direction names (in the user's language) the way a value moved
+func direction(d float64, up string, down string, same string) string {
+  switch {
+  case d > 0.005:
+    return T(up)
+  case d < -0.005:
+    return T(down)
+  }
+  return T(same)
+}
@@ -7,7 +7,7 @@
 * Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
 * and Anthony Starks.
 *
-* Last Modified: Mon Aug 01 12:28:06 CDT 2016
+* Last Modified: Mon Oct 19 03:03:59 CDT 2026
 *
 * Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
 *
@@ -29,20 +29,31 @@
 package main
 
 import (
+  "context"
   "encoding/json"
   "flag"
   "fmt"
   "io/ioutil"
   "net/http"
   "os"
-  "regexp"
-  "strings"
+  "time"
 )
 
 type Config struct {
-  Key     string
-  Station string
-	Degrees string
+  Key      string
+  Key_env     string
+  Key_file    string
+  Key_command string
+  Station  string
+	Degrees  string
+  Webhooks []Webhook
+  Rules    []Rule
+  Stations  []string
+  Templates map[string]string
+  Theme     Theme
+  Language  string
+  Comfort   string
+  Comfort_scales map[string]ComfortScale
 }
 
 var (
@@ -58,10 +69,27 @@ var (
   doastro      bool
   doyesterday  bool
   dotides      bool
+  dorules      bool
   dohistory    string
   doplanner    string
+  doanomaly    string
+  debug        bool
+  verbose      bool
+  derived      bool
+  zambretti    bool
+  chart        bool
+  dohourly     bool
+  watch        string
+  templateName string
+  colorMode    string
+  outputFormat string
   date         string
   conf         Config
+  stationName  string
+
+  lookupRadiusFlag string
+  lookupLimit      int
+  lookupPick       bool
 )
 
 // Struct common to several data streams
@@ -81,15 +109,28 @@ func GetVersion() string {
   return "3.10.2"
 }
 
-// GetConf returns the API key and weather station from
-// the configuration file at $HOME/.condrc
+// ReadConf reads the configuration file ($WU_CONFIG,
+// $XDG_CONFIG_HOME/wu/config or $HOME/.condrc) and applies any
+// overrides from the environment
 func ReadConf() {
-
-  if b, err := ioutil.ReadFile(os.Getenv("HOME") + "/.condrc"); err == nil {
-    jsonErr := json.Unmarshal(b, &conf)
-    CheckError(jsonErr)
-  } else {
-    fmt.Println("You must create a .condrc file in $HOME.")
+  path, err := ConfigPath()
+  if err == nil && path != "" {
+    err = LoadConfig(path, &conf)
+  }
+  if err != nil {
+    fmt.Fprintln(os.Stderr, err)
+    os.Exit(1)
+  }
+  configSource = path
+  CheckPermissions(path, &conf)
+  ApplyEnv(&conf)
+  if err := ResolveKey(&conf); err != nil {
+    fmt.Fprintln(os.Stderr, err)
+    os.Exit(1)
+  }
+  if path == "" && conf.Key == "" {
+    fmt.Println("You must create a configuration file: run \"wu config init\",")
+    fmt.Println("or see the README (or set $WU_KEY).")
     os.Exit(0)
   }
 }
@@ -109,31 +150,51 @@ func Options() string {
   flag.BoolVar(&doconditions, "conditions", false, "Reports the current weather conditions")
   flag.BoolVar(&doalerts, "alerts", false, "Reports any active weather alerts")
   flag.BoolVar(&dolookup, "lookup", false, "Lookup the codes for the weather stations in a particular area")
+  flag.StringVar(&lookupRadiusFlag, "radius", "", "With -lookup, how far to look for stations, e.g. --radius=\"50km\" or \"30mi\"")
+  flag.IntVar(&lookupLimit, "limit", 10, "With -lookup, the most stations to list")
+  flag.BoolVar(&lookupPick, "pick", false, "With -lookup, choose one of the stations as your default")
   flag.BoolVar(&doastro, "astro", false, "Reports sunrise, sunset, and lunar phase")
   flag.BoolVar(&doforecast, "forecast", false, "Reports the current (3-day) forecast")
   flag.BoolVar(&doforecast10, "forecast10", false, "Reports the current (7-day) forecast")
+  flag.BoolVar(&dohourly, "hourly", false, "Reports the hourly forecast")
+  flag.BoolVar(&derived, "derived", false, "With -conditions, adds the measures wu works out itself (heat index, wet-bulb, cloud base...)")
+  flag.BoolVar(&zambretti, "zambretti", false, "With -conditions, adds a Zambretti forecast from the pressure tendency")
+  flag.BoolVar(&chart, "chart", false, "With -forecast10, -hourly, -history or -conditions, adds a chart (temperatures, precipitation or the pressure) sized to the terminal")
+  flag.BoolVar(&verbose, "verbose", false, "With -forecast10, adds the text forecast to the table")
   flag.BoolVar(&doalmanac, "almanac", false, "Reports average high, low and record temperatures")
   flag.BoolVar(&doyesterday, "yesterday", false, "Reports yesterday's weather data")
-  flag.StringVar(&dohistory, "history", "", "Reports historical data for a particular day --history=\"YYYYMMDD\"")
+  flag.StringVar(&dohistory, "history", "", "Reports historical data for a particular day --history=\"YYYYMMDD\" (with -chart, a range of archived days --history=\"YYYYMMDD-YYYYMMDD\")")
+  flag.StringVar(&doanomaly, "anomaly", "", "Compares a day's temperatures with the normals and records --anomaly=\"today\", \"yesterday\" or \"YYYYMMDD\"")
   flag.StringVar(&doplanner, "planner", "", "Reports historical data for a particular date range (30-day max) --planner=\"MMDDMMDD\"")
   flag.BoolVar(&dotides, "tides", false, "Reports tidal data (if available")
+  flag.BoolVar(&dorules, "rules", false, "Checks the current conditions and forecast against the rules in .condrc")
+  flag.StringVar(&watch, "watch", "", "Repeats the report at an interval --watch=\"10m\" (with -conditions, redraws the display; with -alerts, reports only changes)")
+  flag.StringVar(&templateName, "template", "", "Formats reports with a text/template file, or a template named in .condrc --template=\"FILE\"")
+  flag.StringVar(&colorMode, "color", "auto", "Colors the output: auto (when writing to a terminal and $NO_COLOR is unset), always, or never")
+  flag.StringVar(&outputFormat, "format", "text", "Output format: text, json, or csv")
+  flag.BoolVar(&debug, "debug", false, "Print the API requests made (with the key removed)")
   flag.BoolVar(&help, "help", false, "Print this message")
   flag.BoolVar(&version, "version", false, "Print the version number")
   flag.BoolVar(&doall, "all", false, "Show all weather data")
   flag.StringVar(&station, "s", sconf,
-    "Weather station: \"city, state-abbreviation\", (US or Canadian) zipcode, 3- or 4-letter airport code, or LAT,LONG")
+    "Weather station: \"city, state-abbreviation\" or \"city, country\", (US or Canadian) zipcode, 3- or 4-letter airport code, LAT,LONG, geohash, or personal weather station ID")
   flag.Parse()
 
   // Check for correct usage of wu -lookup
   if dolookup {
-    if len(os.Args) == 3 {
-      station = os.Args[len(os.Args)-1]
+    if flag.NArg() > 0 {
+      station = flag.Arg(0)
+      // switches may follow the station
+      flag.CommandLine.Parse(flag.Args()[1:])
     } else {
-      fmt.Println("Usage: wu -lookup [station] where station is a \"city, state-abbreviation\", (US or Canadian) zipcode, 3- or 4-letter airport code, or LAT,LONG")
+      fmt.Println("Usage: wu -lookup [--radius=DISTANCE] [--limit=N] [--pick] [station] where station is a \"city, state-abbreviation\", (US or Canadian) zipcode, 3- or 4-letter airport code, or LAT,LONG")
       os.Exit(0)
     }
   }
 
+  checkFormat()
+  checkColor()
+
   if help {
     flag.PrintDefaults()
     os.Exit(0)
@@ -151,30 +212,66 @@ func Options() string {
     os.Exit(0)
   }
 
-  // Trap for city-state combinations (e.g. "San Francisco, CA") and
-  // make them URL-friendly (e.g. "CA/SanFranciso")
-  cityStatePattern := regexp.MustCompile("([A-Za-z ]+), ([A-Za-z ]+)")
-
-  if cityState := cityStatePattern.FindStringSubmatch(station); cityState != nil {
-    station = cityState[2] + "/" + cityState[1]
-    station = strings.Replace(station, " ", "_", -1)
+  stationName = station
+  station, err := NormalizeStation(station)
+  if err != nil {
+    fmt.Fprintln(os.Stderr, err)
+    os.Exit(1)
   }
   return station
 }
 
+// NormalizeStation parses a station or place (see ParseLocation)
+// and returns it in the form the API expects (e.g. "Lincoln, NE"
+// becomes "NE/Lincoln")
+func NormalizeStation(station string) (string, error) {
+  loc, err := LocateStation(station)
+  if err != nil {
+    return "", err
+  }
+  return loc.Query()
+}
+
+// checkFormat exits if --format isn't one wu knows
+func checkFormat() {
+  if outputFormat != "text" && outputFormat != "json" && outputFormat != "csv" {
+    fmt.Fprintln(os.Stderr, "--format must be text, json, or csv")
+    os.Exit(1)
+  }
+}
+
+// CommandStation returns the station for a subcommand: the one
+// given (normalized), or else the first configured
+func CommandStation(station string) string {
+  if station == "" {
+    return ConfiguredStations("")[0]
+  }
+  normalized, err := NormalizeStation(station)
+  CheckError(err)
+  return normalized
+}
+
 // BuildURL returns the URL required by the Weather Underground API
 // from the query type, station id, and API key
 func BuildURL(infoType string, stationId string) string {
 
-  const URLstem = "http://api.wunderground.com/api/"
-  const query = "/q/"
-  const format = ".json"
   if dohistory != "" {
     date = dohistory
   } else if doplanner != "" {
     date = doplanner
   }
 
+  return buildURL(infoType, date, stationId)
+}
+
+// buildURL returns the URL for a query type, date (which may be
+// empty) and station id
+func buildURL(infoType string, date string, stationId string) string {
+
+  const URLstem = "http://api.wunderground.com/api/"
+  const query = "/q/"
+  const format = ".json"
+
   var URL string
 
   if date != "" {
@@ -183,34 +280,71 @@ func BuildURL(infoType string, stationId string) string {
     URL = URLstem + conf.Key + "/" + infoType + "_" + date + query + stationId + format
   }
 
-  // fmt.Println(URL) //DEBUG
+  if debug {
+    fmt.Fprintln(os.Stderr, "GET "+Redact(URL))
+  }
 
   return URL
 }
 
+// apiClient gives up on a request the API hasn't answered within its
+// timeout, so that a stalled connection can't hang wu
+var apiClient = &http.Client{Timeout: 30 * time.Second}
+
+// fetchContext is the context requests are made in.  Modes that
+// catch interrupts (--watch, wu archive) replace it with one that an
+// interrupt cancels, so that Ctrl-C doesn't wait for the request.
+var fetchContext = context.Background()
+
 // Fetch does URL processing
 func Fetch(url string) ([]byte, error) {
-  res, err := http.Get(url)
-  CheckError(err)
+  req, err := http.NewRequestWithContext(fetchContext, "GET", url, nil)
+  if err != nil {
+    return nil, RedactError(err)
+  }
+  res, err := apiClient.Do(req)
+  if err != nil {
+    return nil, RedactError(err)
+  }
+  defer res.Body.Close()
   if res.StatusCode != 200 {
-    fmt.Fprintf(os.Stderr, "Bad HTTP Status: %d\n", res.StatusCode)
-    return nil, err
+    return nil, fmt.Errorf("Bad HTTP Status: %d", res.StatusCode)
   }
-  b, err := ioutil.ReadAll(res.Body)
-  res.Body.Close()
-  return b, err
+  return ioutil.ReadAll(res.Body)
 }
 
-// CheckError exits on error with a message
-func CheckError(err error) {
+// Get fetches a data stream for a station and decodes it into obs.
+// Unlike weather, it returns errors rather than exiting, so that
+// long-running modes can recover from a failed request.
+func Get(operation string, station string, obs interface{}) error {
+  b, err := Fetch(BuildURL(operation, station))
   if err != nil {
-    fmt.Fprintf(os.Stderr, "Fatal error\n%v\n", err)
+    return err
+  }
+  return json.Unmarshal(b, obs)
+}
+
+// watchInterval returns the --watch interval, or zero if the
+// switch wasn't given
+func watchInterval() time.Duration {
+  if watch == "" {
+    return 0
+  }
+  d, err := time.ParseDuration(watch)
+  CheckError(err)
+  if d < time.Minute {
+    fmt.Fprintln(os.Stderr, "The --watch interval must be at least one minute.")
     os.Exit(1)
   }
+  return d
 }
 
-func init() {
-  ReadConf()
+// CheckError exits on error with a message
+func CheckError(err error) {
+  if err != nil {
+    fmt.Fprintf(os.Stderr, "Fatal error\n%v\n", RedactError(err))
+    os.Exit(1)
+  }
 }
 
 // weather prints various weather information for a specified station
@@ -239,26 +373,40 @@ func weather(operation string, station string) {
     var obs Conditions
     jsonErr := json.Unmarshal(b, &obs)
     CheckError(jsonErr)
+    NoteObservation(station, &obs.Current_observation)
     PrintConditions(&obs, conf.Degrees)
+    if charting() {
+      printChart(T("Pressure"), PressureChart(LoadObservations(station)))
+    }
+    CheckRules(station, &obs.Current_observation, nil)
   case "forecast":
     var obs ForecastConditions
     jsonErr := json.Unmarshal(b, &obs)
     CheckError(jsonErr)
     PrintForecast(&obs, station)
+    CheckRules(station, nil, &obs)
   case "forecast10day":
     var obs ForecastConditions
     jsonErr := json.Unmarshal(b, &obs)
     CheckError(jsonErr)
     PrintForecast10(&obs, station)
+    CheckRules(station, nil, &obs)
+  case "hourly":
+    var obs HourlyConditions
+    jsonErr := json.Unmarshal(b, &obs)
+    CheckError(jsonErr)
+    PrintHourly(&obs, station)
   case "yesterday":
     var obs HistoryConditions
     jsonErr := json.Unmarshal(b, &obs)
     CheckError(jsonErr)
+    ArchiveHistory(station, &obs)
     PrintHistory(&obs, station, conf.Degrees)
   case "history":
     var obs HistoryConditions
     jsonErr := json.Unmarshal(b, &obs)
     CheckError(jsonErr)
+    ArchiveHistory(station, &obs)
     PrintHistory(&obs, station, conf.Degrees)
   case "planner":
     var obs PlannerConditions
@@ -270,15 +418,30 @@ func weather(operation string, station string) {
     jsonErr := json.Unmarshal(b, &obs)
     CheckError(jsonErr)
     PrintTides(&obs, station)
-  case "geolookup":
-    var l Lookup
-    jsonErr := json.Unmarshal(b, &l)
-    CheckError(jsonErr)
-    PrintLookup(&l)
   }
 }
 
+// commands are the subcommands (wu serve, ...), which take their
+// own switches
+var commands = map[string]func(args []string){
+  "backfill": Backfill,
+  "chart":    Chart,
+  "climate":  Climate,
+  "config":   Configure,
+  "frost":    Frost,
+  "serve":    Serve,
+  "tui":      Tui,
+}
+
 func main() {
+  if len(os.Args) > 1 {
+    if command, ok := commands[os.Args[1]]; ok {
+      command(os.Args[2:])
+      os.Exit(0)
+    }
+  }
+
+  ReadConf()
   stationId := Options()
   if doall {
     weather("conditions", stationId)
@@ -291,11 +454,15 @@ func main() {
     weather("yesterday", stationId)
     weather("astronomy", stationId)
     weather("tide", stationId)
-    weather("geolookup", stationId)
+    LookupStations(stationName)
     os.Exit(0)
   }
   if doalerts {
-    weather("alerts", stationId)
+    if interval := watchInterval(); interval > 0 {
+      WatchAlerts(stationId, interval)
+    } else {
+      weather("alerts", stationId)
+    }
   }
   if doalmanac {
     weather("almanac", stationId)
@@ -304,7 +471,11 @@ func main() {
     weather("astronomy", stationId)
   }
   if doconditions {
-    weather("conditions", stationId)
+    if interval := watchInterval(); interval > 0 {
+      WatchConditions(stationId, interval)
+    } else {
+      weather("conditions", stationId)
+    }
   }
   if doforecast {
     weather("forecast", stationId)
@@ -312,7 +483,12 @@ func main() {
   if doforecast10 {
     weather("forecast10day", stationId)
   }
-  if dohistory != "" {
+  if dohourly {
+    weather("hourly", stationId)
+  }
+  if historyRange.MatchString(dohistory) {
+    ChartHistory(stationId, dohistory)
+  } else if dohistory != "" {
     weather("history", stationId)
   }
   if doyesterday {
@@ -321,11 +497,25 @@ func main() {
   if doplanner != "" {
     weather("planner", stationId)
   }
+  if doanomaly != "" {
+    PrintAnomaly(stationId, doanomaly)
+  }
   if dotides {
     weather("tide", stationId)
   }
   if dolookup {
-    weather("geolookup", stationId)
+    LookupStations(stationName)
+  }
+  if dorules {
+    CompileRules() // report malformed rules before polling
+    interval := watchInterval()
+    for {
+      RunRules(stationId)
+      if interval == 0 {
+        break
+      }
+      time.Sleep(interval)
+    }
   }
   if flag.NFlag() == 0 {
     weather("conditions", stationId)
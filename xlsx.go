@@ -0,0 +1,239 @@
+/*
+* xlsx.go
+*
+* This file is part of wu.  It contains the functions that write
+* reports as Excel workbooks (--format=xlsx).
+*
+* Written and maintained by Stephen Ramsay <sramsay.unl@gmail.com>
+* and Anthony Starks.
+*
+* Last Modified: Mon Oct 19 00:51:21 CDT 2026
+*
+* Copyright © 2010-2016 by Stephen Ramsay and Anthony Starks.
+*
+* wu is free software; you can redistribute it and/or modify
+* it under the terms of the GNU General Public License as published by
+* the Free Software Foundation; either version 3, or (at your option)
+* any later version.
+*
+* wu is distributed in the hope that it will be useful, but WITHOUT
+* ANY WARRANTY; without even the implied warranty of MERCHANTABILITY
+* or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU General Public
+* License for more details.
+*
+* You should have received a copy of the GNU General Public License
+* along with wu; see the file COPYING.  If not see
+* <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+  "archive/zip"
+  "encoding/xml"
+  "fmt"
+  "io"
+  "math"
+  "reflect"
+  "strconv"
+  "strings"
+)
+
+// Sheet is a worksheet of a workbook: rows of strings and numbers
+// (float64 or Number; NaN leaves the cell empty).  The first row is
+// shown in bold.
+type Sheet struct {
+  Name string
+  Rows [][]interface{}
+}
+
+// TableSheet makes a sheet of a slice of structs, with a column for
+// each field (as in the CSV output) and a header row
+func TableSheet(name string, table interface{}) Sheet {
+  v := reflect.Indirect(reflect.ValueOf(table))
+  sheet := Sheet{Name: name}
+  for i := 0; i < v.Len(); i++ {
+    var header []string
+    var row []interface{}
+    cells(v.Index(i), "", &header, &row)
+    if i == 0 {
+      var names []interface{}
+      for _, h := range header {
+        names = append(names, h)
+      }
+      sheet.Rows = append(sheet.Rows, names)
+    }
+    sheet.Rows = append(sheet.Rows, row)
+  }
+  return sheet
+}
+
+// FieldSheet makes a sheet of a struct, with a row for each field
+func FieldSheet(name string, data interface{}) Sheet {
+  var header []string
+  var row []interface{}
+  cells(reflect.Indirect(reflect.ValueOf(data)), "", &header, &row)
+  sheet := Sheet{Name: name}
+  for i := range header {
+    sheet.Rows = append(sheet.Rows, []interface{}{header[i], row[i]})
+  }
+  return sheet
+}
+
+// cells flattens a struct into named cells, as flatten does for CSV
+func cells(v reflect.Value, prefix string, header *[]string, row *[]interface{}) {
+  t := v.Type()
+  for i := 0; i < t.NumField(); i++ {
+    f, name := v.Field(i), prefix+t.Field(i).Name
+    switch f.Kind() {
+    case reflect.Struct:
+      cells(f, name+".", header, row)
+      continue
+    case reflect.Slice, reflect.Map:
+      continue
+    case reflect.Float32, reflect.Float64:
+      *row = append(*row, f.Float())
+    case reflect.Int, reflect.Int64:
+      *row = append(*row, float64(f.Int()))
+    case reflect.Bool:
+      *row = append(*row, fmt.Sprint(f.Bool()))
+    default:
+      *row = append(*row, fmt.Sprint(f.Interface()))
+    }
+    *header = append(*header, name)
+  }
+}
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+%s</Types>`
+
+const xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>
+</styleSheet>`
+
+// WriteXLSX writes sheets as an Excel (Office Open XML) workbook
+func WriteXLSX(w io.Writer, sheets []Sheet) error {
+  z := zip.NewWriter(w)
+  add := func(name string, content string) error {
+    f, err := z.Create(name)
+    if err != nil {
+      return err
+    }
+    _, err = io.WriteString(f, content)
+    return err
+  }
+
+  var overrides, entries, rels strings.Builder
+  for i, sheet := range sheets {
+    n := i + 1
+    fmt.Fprintf(&overrides, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`+"\n", n)
+    fmt.Fprintf(&entries, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xmlEscape(sheetName(sheet.Name)), n, n)
+    fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`+"\n", n, n)
+  }
+  fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`+"\n", len(sheets)+1)
+
+  parts := []struct{ name, content string }{
+    {"[Content_Types].xml", fmt.Sprintf(xlsxContentTypes, overrides.String())},
+    {"_rels/.rels", xlsxRels},
+    {"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>` + entries.String() + `</sheets></workbook>`},
+    {"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+` + rels.String() + `</Relationships>`},
+    {"xl/styles.xml", xlsxStyles},
+  }
+  for i, sheet := range sheets {
+    parts = append(parts, struct{ name, content string }{
+      fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), sheetXML(sheet)})
+  }
+  for _, p := range parts {
+    if err := add(p.name, p.content); err != nil {
+      return err
+    }
+  }
+  return z.Close()
+}
+
+// sheetXML returns the worksheet part for a sheet
+func sheetXML(sheet Sheet) string {
+  var b strings.Builder
+  b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+  for r, row := range sheet.Rows {
+    fmt.Fprintf(&b, `<row r="%d">`, r+1)
+    style := ""
+    if r == 0 {
+      style = ` s="1"`
+    }
+    for c, value := range row {
+      ref := fmt.Sprintf("%s%d", columnName(c), r+1)
+      switch v := value.(type) {
+      case Number:
+        value = float64(v)
+      case int:
+        value = float64(v)
+      }
+      switch v := value.(type) {
+      case float64:
+        if math.IsNaN(v) || math.IsInf(v, 0) {
+          continue
+        }
+        fmt.Fprintf(&b, `<c r="%s"%s><v>%s</v></c>`, ref, style, strconv.FormatFloat(v, 'f', -1, 64))
+      default:
+        s := fmt.Sprint(v)
+        if s == "" {
+          continue
+        }
+        fmt.Fprintf(&b, `<c r="%s"%s t="inlineStr"><is><t>%s</t></is></c>`, ref, style, xmlEscape(s))
+      }
+    }
+    b.WriteString(`</row>`)
+  }
+  b.WriteString(`</sheetData></worksheet>`)
+  return b.String()
+}
+
+// columnName returns the letters of a (zero-based) column: A-Z, AA...
+func columnName(c int) string {
+  name := ""
+  for c++; c > 0; c = (c - 1) / 26 {
+    name = string(rune('A'+(c-1)%26)) + name
+  }
+  return name
+}
+
+// sheetName makes a valid sheet name: at most 31 characters, none of
+// []:*?/\
+func sheetName(name string) string {
+  name = strings.Map(func(r rune) rune {
+    if strings.ContainsRune(`[]:*?/\`, r) {
+      return '-'
+    }
+    return r
+  }, name)
+  if len(name) > 31 {
+    name = name[:31]
+  }
+  return name
+}
+
+func xmlEscape(s string) string {
+  var b strings.Builder
+  xml.EscapeText(&b, []byte(s))
+  return b.String()
+}